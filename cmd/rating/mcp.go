@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"track/internal/track/adapters/primary/cli"
+	"track/internal/track/adapters/primary/mcp"
+	ratingPort "track/internal/track/ports/primary/rating"
+	"track/internal/track/ports/secondary"
+
+	"github.com/spf13/cobra"
+)
+
+// version is reported to MCP clients, set with -ldflags "-X track/cmd/rating.version=..."
+var version = "dev"
+
+func newMCPCmd(service ratingPort.Service, clk secondary.Clock) *cobra.Command {
+	return &cobra.Command{
+		Use:   "mcp",
+		Short: "Run a Model Context Protocol server over stdio",
+		Long: `Run a Model Context Protocol server over stdio.
+
+Assistants launch this as a subprocess to read and log day ratings with the
+set_day_rating, get_week_ratings and get_summary tools, and to read recent
+history from the track://ratings/recent resource.`,
+		Args: cli.UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return mcp.NewServer(service, clk, version).Serve(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+}
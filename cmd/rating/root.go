@@ -4,31 +4,184 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"log"
 	"os"
+	"os/user"
 	"path/filepath"
 	"track/internal/track/adapters/primary/cli"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/crypt"
 	"track/internal/track/adapters/secondary/file"
+	"track/internal/track/adapters/secondary/gitlog"
+	"track/internal/track/adapters/secondary/gitsync"
+	"track/internal/track/adapters/secondary/notify"
+	"track/internal/track/adapters/secondary/scheduler"
+	"track/internal/track/application/activity"
+	"track/internal/track/application/backup"
+	"track/internal/track/application/correlate"
+	"track/internal/track/application/goals"
+	"track/internal/track/application/prompt"
 	"track/internal/track/application/rating"
+	"track/internal/track/application/remind"
+	"track/internal/track/application/replica"
+	"track/internal/track/application/storage"
+	"track/internal/track/application/sync"
+	"track/internal/track/application/team"
+	"track/internal/track/config"
+	domain "track/internal/track/domain/rating"
+	remindDomain "track/internal/track/domain/remind"
+	"track/internal/track/ports/secondary"
 )
 
 func Execute() {
+	os.Exit(run())
+}
+
+// run wires the adapters together and returns the process exit code
+func run() int {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		log.Fatal(err)
+		return cli.ReportError(os.Stderr, domain.Errorf(domain.KindConfig, "finding home directory: %w", err), false, false)
+	}
+
+	cfg, err := config.Load(config.Path(homeDir), homeDir)
+	if err != nil {
+		return cli.ReportError(os.Stderr, err, false, false)
 	}
 
-	//todo: make configurable
-	repoPath := filepath.Join(homeDir, ".track.rating.json")
-	repo, err := file.NewFileRepository(repoPath)
+	git := gitsync.New(cfg.RatingFile, homeDir, cfg.SyncRemote, cfg.SyncBranch)
+	var (
+		ratingRepo   secondary.RatingRepository
+		data         secondary.Versioned
+		replicaStore secondary.Replica
+		records      secondary.RecordReader
+	)
+	switch cfg.Store {
+	case config.StoreCRDT:
+		repo, err := file.NewReplicaRepository(cfg.RatingFile)
+		if err != nil {
+			return cli.ReportError(os.Stderr, err, false, false)
+		}
+		ratingRepo, data, replicaStore, records = repo, repo, repo, repo
+	default:
+		// Opened lazily, so a cached `track prompt` doesn't read the ratings at all
+		repo, err := file.OpenFileRepository(cfg.RatingFile)
+		if err != nil {
+			return cli.ReportError(os.Stderr, err, false, false)
+		}
+		// Once synced, every write to the ratings is committed
+		ratingRepo, data, records = gitsync.NewRepository(repo, git), repo, repo
+	}
+	// The day's first change is preceded by a snapshot of the file as it was
+	snapshots := file.NewSnapshots(cfg.BackupDir, cfg.RatingFile, cfg.Backup)
+	ratingRepo = file.NewSnapshotRepository(ratingRepo, snapshots)
+
+	goalRepo, err := file.NewGoalRepository(cfg.GoalsFile)
+	if err != nil {
+		return cli.ReportError(os.Stderr, err, false, false)
+	}
+
+	// Days are sealed above the backend once a key is created, the passphrase asked for on first use
+	keys := crypt.NewKeyFile(cfg.KeyFile, crypt.Passphrases{
+		Getenv:  os.Getenv,
+		Command: cfg.PassphraseCommand,
+		Prompt:  crypt.Terminal,
+	}, crypt.DefaultParams)
+
+	// One clock for every adapter, so --as-of applies everywhere, but ratings are stamped with the real time
+	clk := clock.NewAsOf(clock.System{})
+	// Without a hostname ratings simply don't record one
+	host, _ := os.Hostname()
+	ratingService := rating.NewService(crypt.NewRepository(ratingRepo, keys), clk,
+		rating.WithWallClock(clock.System{}), rating.WithHostname(host), rating.WithAggregation(cfg.Aggregation))
+
+	goalService := goals.NewService(goalRepo, ratingService, clk)
+
+	remindService, err := newRemindService(cfg, homeDir, ratingService, clk)
+	if err != nil {
+		return cli.ReportError(os.Stderr, err, false, false)
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = filepath.Join(homeDir, ".cache")
+	}
+	promptCache, err := file.NewPromptCache(filepath.Join(cacheDir, "track", "prompt.json"))
 	if err != nil {
-		log.Fatal(err)
+		return cli.ReportError(os.Stderr, err, false, false)
 	}
+	promptService := prompt.NewService(ratingService, data, promptCache, clk, prompt.WithCipher(keys))
+
+	backupService := backup.NewService(snapshots, records, keys)
+	teamService := newTeamService(cfg, ratingService, clk)
+	activityService := activity.NewService(ratingService, file.NewMetricStore(cfg.MetricsFile), gitlog.New(cfg.CommitAuthors), cfg.CommitRepos, file.CalendarFiles{Emails: cfg.CommitAuthors}, clk)
 
-	ratingService := rating.NewService(repo)
+	rootCmd := cli.NewRootCmd(ratingService, goalService, activityService, clk)
+	rootCmd.AddCommand(
+		newServeCmd(ratingService, clk),
+		newMCPCmd(ratingService, clk),
+		cli.NewRemindCmd(remindService),
+		cli.NewPromptCmd(promptService, cfg.PromptFormat, cfg.PromptUnrated),
+		cli.NewSyncCmd(sync.NewService(git, sync.WithCipher(keys))),
+		cli.NewMergeCmd(replica.NewService(replicaStore)),
+		cli.NewStorageCmd(storage.NewService(ratingRepo, keys, keys, records, snapshots)),
+		cli.NewBackupCmd(backupService),
+		cli.NewRestoreCmd(backupService),
+		cli.NewTeamCmd(teamService, clk),
+		cli.NewCorrelateCmd(correlate.NewService(ratingService, file.SeriesFiles{}), clk),
+		cli.NewImportCmd(activityService, clk),
+		cli.NewInsightsCmd(activityService, clk),
+	)
+	return cli.Execute(rootCmd)
+}
 
-	rootCmd := cli.NewRootCmd(ratingService)
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
+// newTeamService shares ratings through team.dir, as the login name unless team.member is set
+func newTeamService(cfg config.Config, ratings *rating.Service, clk *clock.AsOf) *team.Service {
+	var dir secondary.TeamDirectory
+	if cfg.TeamDir != "" {
+		dir = file.NewTeamDirectory(cfg.TeamDir)
 	}
+	member := cfg.TeamMember
+	if member == "" {
+		if u, err := user.Current(); err == nil {
+			member = u.Username
+		}
+	}
+	return team.NewService(dir, ratings, clk, member, cfg.TeamMinGroup)
+}
+
+// newRemindService sets up reminders to run this executable's `remind check` with the same configuration
+func newRemindService(cfg config.Config, homeDir string, ratings *rating.Service, clk *clock.AsOf) (*remind.Service, error) {
+	notifier, err := notify.New(cfg.Notifier, cfg.NotifyCommand, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	snooze, err := file.NewSnoozeStore(filepath.Join(homeDir, ".track.remind.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, domain.Errorf(domain.KindConfig, "finding the track executable: %w", err)
+	}
+	check := []string{executable, "remind", "check"}
+	var env []string
+	if path := os.Getenv("TRACK_CONFIG"); path != "" {
+		env = append(env, "TRACK_CONFIG="+path)
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = filepath.Join(homeDir, ".config")
+	}
+
+	return remind.NewService(ratings, notifier, snooze, clk,
+		remind.WithSchedule(cfg.Remind),
+		remind.WithScheduler(remindDomain.SchedulerSystemd, &scheduler.Systemd{
+			UnitDir: filepath.Join(configDir, "systemd", "user"),
+			Command: check,
+			Env:     env,
+			Run:     scheduler.Exec,
+		}),
+		remind.WithScheduler(remindDomain.SchedulerCron, &scheduler.Cron{Command: check, Env: env, Run: scheduler.Exec}),
+	), nil
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"track/internal/track/adapters/primary/cli"
+	"track/internal/track/adapters/primary/http"
+	ratingPort "track/internal/track/ports/primary/rating"
+	"track/internal/track/ports/secondary"
+
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd(service ratingPort.Service, clk secondary.Clock) *cobra.Command {
+	var (
+		addr  string
+		token string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the JSON API and dashboard for rating from other devices",
+		Long: `Serve the JSON API for rating from other devices.
+
+Every API call needs an "Authorization: Bearer <token>" header. The token
+comes from --token or TRACK_API_TOKEN, otherwise one is generated and printed.
+The dashboard is served from / and asks for the same token once.`,
+		Args: cli.UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if token == "" {
+				token = os.Getenv("TRACK_API_TOKEN")
+			}
+			if token == "" {
+				generated, err := generateToken()
+				if err != nil {
+					return err
+				}
+				token = generated
+				fmt.Fprintf(cmd.OutOrStdout(), "API token: %s\n", token)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Listening on http://%s\n", addr)
+			return http.NewServer(service, clk, token).ListenAndServe(ctx, addr)
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8420", "Address to listen on, use 0.0.0.0:8420 to serve the LAN")
+	cmd.Flags().StringVar(&token, "token", "", "Bearer token required by the API")
+	return cmd
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -7,9 +7,12 @@ go 1.23.6
 require (
 	github.com/magiconair/properties v1.8.7
 	github.com/spf13/cobra v1.8.1
+	golang.org/x/crypto v0.36.0
+	golang.org/x/term v0.30.0
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/sys v0.31.0 // indirect
 )
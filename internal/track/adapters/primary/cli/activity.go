@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/domain/activity"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/series"
+	activityPort "track/internal/track/ports/primary/activity"
+)
+
+// NewImportCmd is added by the composition root, which knows the repositories to read
+func NewImportCmd(service activityPort.Service, clk Clock) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import measures of each day, such as commits or meetings, to show next to the ratings.",
+		Long: `Import measures of each day, such as commits or meetings, to show next to the ratings.
+
+Imported metrics are kept in metrics.file in ~/.track.properties
+(~/.track.metrics.json), shown in day report and compared with the ratings by
+track insights. Importing again replaces the days imported.`,
+	}
+	cmd.AddCommand(
+		newImportCommitsCmd(service, clk),
+		newImportMeetingsCmd(service, clk),
+	)
+	return cmd
+}
+
+func newImportCommitsCmd(service activityPort.Service, clk Clock) *cobra.Command {
+	var since string
+	var days int
+	cmd := &cobra.Command{
+		Use:   "commits",
+		Short: "Count your commits per day in the repositories in commits.repos.",
+		Long: `Count your commits per day in the repositories in commits.repos.
+
+commits.repos in ~/.track.properties lists local git repositories, comma
+separated. Commits on every branch count, merges and other authors' left out;
+commits.author lists the emails you commit as, each repository's user.email by
+default. A commit's day is the date where it was made, and a commit found in
+several clones counts once.
+
+  commits.repos = ~/src/track, ~/work/api`,
+		Example: `  track import commits
+  track import commits --since 2025-01-01`,
+		Args: UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, err := importStart(cmd, clk, since, days)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+
+			report, err := service.ImportCommits(ctx, start)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %s on %s from %s, %s to %s\n",
+				countLabel(report.Items, "commit"), daysLabel(report.Days), countLabel(report.Sources, "repository"),
+				rating.DayID(report.Start), rating.DayID(report.End))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", "First day to import, YYYY-MM-DD")
+	cmd.Flags().IntVar(&days, "days", 365, "Number of days to import, up to today, unless --since is given")
+	return cmd
+}
+
+func newImportMeetingsCmd(service activityPort.Service, clk Clock) *cobra.Command {
+	var since string
+	var days int
+	cmd := &cobra.Command{
+		Use:   "meetings <file.ics>...",
+		Short: "Count meetings and the hours in them per day in iCalendar files.",
+		Long: `Count meetings and the hours in them per day in iCalendar files.
+
+Export your calendar as .ics and import it again whenever it changes. Every
+timed event is a meeting, recurring ones on each of their days; all-day,
+cancelled and free events are left out, as are those you declined as one of
+the emails in commits.author. An event found in several files counts once,
+and a meeting running past midnight adds its hours to both days. A recurrence
+track can't expand, such as the last weekday of each month, is an error rather
+than counted wrong.`,
+		Example: `  track import meetings ~/Downloads/work.ics
+  track import meetings work.ics team.ics --since 2025-01-01`,
+		Args: UsageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, err := importStart(cmd, clk, since, days)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			report, err := service.ImportMeetings(ctx, args, start)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %s, %sh, on %s from %s, %s to %s\n",
+				countLabel(report.Items, "meeting"), number(report.Hours), daysLabel(report.Days), countLabel(report.Sources, "file"),
+				rating.DayID(report.Start), rating.DayID(report.End))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", "First day to import, YYYY-MM-DD")
+	cmd.Flags().IntVar(&days, "days", 365, "Number of days to import, up to today, unless --since is given")
+	return cmd
+}
+
+// importStart is the first day an import covers, from --since or else --days
+func importStart(cmd *cobra.Command, clk Clock, since string, days int) (time.Time, error) {
+	if since != "" {
+		date, err := time.ParseInLocation("2006-01-02", since, time.Local)
+		if err != nil {
+			return time.Time{}, &usageError{path: cmd.CommandPath(), err: fmt.Errorf("invalid --since %q, expected YYYY-MM-DD", since)}
+		}
+		return date, nil
+	}
+	if days < 1 {
+		return time.Time{}, &usageError{path: cmd.CommandPath(), err: fmt.Errorf("--days must be at least 1, got %d", days)}
+	}
+	return rating.DayStart(clk.Now()).AddDate(0, 0, -(days - 1)), nil
+}
+
+// NewInsightsCmd is added by the composition root next to the import command
+func NewInsightsCmd(service activityPort.Service, clk Clock) *cobra.Command {
+	var days int
+	cmd := &cobra.Command{
+		Use:   "insights",
+		Short: "Compare the imported metrics, such as commits or meetings, with how the days felt.",
+		Long: `Compare the imported metrics, such as commits or meetings, with how the days felt.
+
+For each metric a day is high, productive by commits or meeting-heavy, when
+it has any and at least the median, and felt good when rated Good or better. The
+table counts the days by both, so a productive day that did not feel good
+stands out. The correlation is Spearman's, over the rated days with a value.`,
+		Example: `  track import commits
+  track import meetings work.ics
+  track insights --days 180`,
+		Args: UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if days < 2 {
+				return &usageError{path: cmd.CommandPath(), err: fmt.Errorf("--days must be at least 2, got %d", days)}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			end := rating.DayEnd(clk.Now())
+			start := rating.DayStart(clk.Now()).AddDate(0, 0, -(days - 1))
+			insights, err := service.Insights(ctx, start, end)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOutput(cmd) {
+				list := make([]insightJSON, 0, len(insights))
+				for _, in := range insights {
+					list = append(list, toInsightJSON(in))
+				}
+				return writeJSON(out, list)
+			}
+
+			fmt.Fprintf(out, "Ratings from %s to %s\n", rating.DayID(start), rating.DayID(end))
+			fmt.Fprintf(out, "─────────────────────\n")
+			for i, in := range insights {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				printInsight(out, in)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&days, "days", 90, "Number of days to include, up to today")
+	return cmd
+}
+
+func printInsight(out io.Writer, in activity.Insight) {
+	if in.Days == 0 {
+		fmt.Fprintf(out, "%s: no rated days with a value\n", in.Metric)
+		return
+	}
+	fmt.Fprintf(out, "%s over %s, median %s\n", in.Metric, daysLabel(in.Days), number(in.Median))
+	fmt.Fprintf(out, "  Correlation:  %s %s\n", coefficient(in.Correlation), series.Strength(in.Correlation))
+	high := highLabel(in.Metric)
+	fmt.Fprintf(out, "  %s days average %s vs %s\n", capitalize(high), average(in.HighRating), average(in.LowRating))
+	fmt.Fprintf(out, "  Days that felt good average %s %s vs %s\n", average(in.GoodValue), in.Metric, average(in.OtherValue))
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "  %-24s %9s %9s\n", "", "felt good", "did not")
+	fmt.Fprintf(out, "  %-24s %9d %9d\n", high, in.Table[1][1], in.Table[1][0])
+	fmt.Fprintf(out, "  %-24s %9d %9d\n", "not "+high, in.Table[0][1], in.Table[0][0])
+}
+
+// highLabel names the days a metric is high on
+func highLabel(metric string) string {
+	switch metric {
+	case activity.Commits:
+		return "productive"
+	case activity.Meetings, activity.MeetingHours:
+		return "meeting-heavy"
+	}
+	return "high " + metric
+}
+
+func capitalize(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func average(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+func countLabel(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if plural, ok := strings.CutSuffix(noun, "y"); ok {
+		return fmt.Sprintf("%d %sies", n, plural)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// dayMetrics looks up each imported metric's value on a day for the daily list
+type dayMetrics []series.Series
+
+// label lists the day's metrics with a value, such as " · 4 commits · 2 meetings, 1.5h"
+func (m dayMetrics) label(day time.Time) string {
+	values := make(map[string]float64, len(m))
+	for _, s := range m {
+		values[s.Name] = s.Values[series.Day(day)]
+	}
+	var b strings.Builder
+	for _, s := range m {
+		v := values[s.Name]
+		switch {
+		case v <= 0 || s.Name == activity.MeetingHours:
+			// Meeting hours are shown with the meetings
+		case s.Name == activity.Meetings:
+			fmt.Fprintf(&b, " · %s, %sh", amount(v, s.Name), number(values[activity.MeetingHours]))
+		default:
+			fmt.Fprintf(&b, " · %s", amount(v, s.Name))
+		}
+	}
+	return b.String()
+}
+
+// amount shows a metric's value, metrics being named in the plural, such as commits
+func amount(v float64, metric string) string {
+	if v == 1 {
+		return "1 " + strings.TrimSuffix(metric, "s")
+	}
+	return number(v) + " " + metric
+}
+
+type insightJSON struct {
+	Metric      string   `json:"metric"`
+	Days        int      `json:"days"`
+	Median      float64  `json:"median"`
+	Correlation *float64 `json:"correlation"`
+	// High names the days the metric is high on, such as productive or meeting-heavy
+	High string `json:"high"`
+	// The average rating on high days and on the others
+	RatingHigh  *float64 `json:"ratingHigh"`
+	RatingOther *float64 `json:"ratingOther"`
+	// The metric's average on days that felt good and on the others
+	ValueGood  *float64  `json:"valueGood"`
+	ValueOther *float64  `json:"valueOther"`
+	Table      tableJSON `json:"table"`
+}
+
+type tableJSON struct {
+	HighGood     int `json:"highGood"`
+	HighNotGood  int `json:"highNotGood"`
+	OtherGood    int `json:"otherGood"`
+	OtherNotGood int `json:"otherNotGood"`
+}
+
+func toInsightJSON(in activity.Insight) insightJSON {
+	// NaN has no JSON form, an undefined value is null
+	optional := func(v float64) *float64 {
+		if math.IsNaN(v) {
+			return nil
+		}
+		return &v
+	}
+	return insightJSON{
+		Metric:      in.Metric,
+		Days:        in.Days,
+		Median:      in.Median,
+		Correlation: optional(in.Correlation),
+		High:        highLabel(in.Metric),
+		RatingHigh:  optional(in.HighRating),
+		RatingOther: optional(in.LowRating),
+		ValueGood:   optional(in.GoodValue),
+		ValueOther:  optional(in.OtherValue),
+		Table: tableJSON{
+			HighGood:     in.Table[1][1],
+			HighNotGood:  in.Table[1][0],
+			OtherGood:    in.Table[0][1],
+			OtherNotGood: in.Table[0][0],
+		},
+	}
+}
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/domain/rating"
+	backupPort "track/internal/track/ports/primary/backup"
+)
+
+// NewBackupCmd is added by the composition root, which knows where the snapshots are kept
+func NewBackupCmd(service backupPort.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Take, list and check snapshots of the ratings file.",
+		Long: `Take, list and check snapshots of the ratings file.
+
+A snapshot is taken before the first change of each day, and with
+track backup create. They are kept in backup.dir (~/.track.backups), each
+next to its SHA-256 checksum, and pruned to the newest of each of the last
+backup.daily days (7), backup.weekly weeks (4) and backup.monthly months (12).`,
+	}
+
+	cmd.AddCommand(
+		newBackupCreateCmd(service),
+		newBackupListCmd(service),
+		newBackupVerifyCmd(service),
+	)
+	return cmd
+}
+
+func newBackupCreateCmd(service backupPort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "create",
+		Short: "Take a snapshot of the ratings file now.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			snapshot, err := service.Create(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created snapshot %s\n", snapshot.ID)
+			return nil
+		},
+	}
+}
+
+func newBackupListCmd(service backupPort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the snapshots, newest first.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			snapshots, err := service.List(ctx)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(snapshots) == 0 {
+				fmt.Fprintln(out, "No snapshots")
+				return nil
+			}
+			for _, s := range snapshots {
+				fmt.Fprintf(out, "%s  %s  %s\n", s.ID, s.Taken.Local().Format("2006-01-02 15:04"), sizeLabel(s.Size))
+			}
+			return nil
+		},
+	}
+}
+
+func newBackupVerifyCmd(service backupPort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:               "verify [snapshot]",
+		Short:             "Check the snapshots' checksums and records, or one snapshot's.",
+		Args:              UsageArgs(cobra.MaximumNArgs(1)),
+		ValidArgsFunction: completeSnapshots(service),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			var id string
+			if len(args) > 0 {
+				id = args[0]
+			}
+			verifications, err := service.Verify(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			failed := 0
+			for _, v := range verifications {
+				switch {
+				case v.Err != nil:
+					fmt.Fprintf(out, "%s  FAILED: %v\n", v.Snapshot.ID, v.Err)
+				case len(v.Problems) > 0:
+					fmt.Fprintf(out, "%s  %s\n", v.Snapshot.ID, problemsLabel(len(v.Problems)))
+					printProblems(out, v.Problems)
+				default:
+					fmt.Fprintf(out, "%s  OK\n", v.Snapshot.ID)
+					continue
+				}
+				failed++
+			}
+			if failed > 0 {
+				return rating.Errorf(rating.KindStorage, "%d of %d snapshots failed verification", failed, len(verifications))
+			}
+			return nil
+		},
+	}
+}
+
+// NewRestoreCmd is added by the composition root along with NewBackupCmd
+func NewRestoreCmd(service backupPort.Service) *cobra.Command {
+	var yes, dryRun bool
+	cmd := &cobra.Command{
+		Use:   "restore <snapshot>",
+		Short: "Replace the ratings with a snapshot's, after showing what changes.",
+		Long: `Replace the ratings with a snapshot's, after showing what changes.
+
+The ratings are snapshotted first, so a restore can itself be undone by
+restoring that snapshot. Asks for confirmation on a terminal, elsewhere
+--yes is required.`,
+		Args:              UsageArgs(cobra.ExactArgs(1)),
+		ValidArgsFunction: completeSnapshots(service),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			out := cmd.OutOrStdout()
+			changes, err := service.Preview(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if len(changes) == 0 {
+				fmt.Fprintf(out, "The ratings match snapshot %s\n", args[0])
+				return nil
+			}
+			fmt.Fprintf(out, "Restoring %s changes %s:\n", args[0], daysLabel(len(changes)))
+			for _, c := range changes {
+				fmt.Fprintf(out, "  %s  %s -> %s\n", c.ID, changeSide(c.Current), changeSide(c.Snapshot))
+			}
+			if dryRun {
+				return nil
+			}
+
+			if !yes {
+				if !stdinIsTerminal() {
+					return &usageError{path: cmd.CommandPath(), err: fmt.Errorf("pass --yes to restore without a terminal")}
+				}
+				// Waits on the user, so not bound by the command timeout
+				ok, err := newPrompter(cmd.InOrStdin(), out).confirm("Restore?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Fprintln(out, "Kept the current ratings")
+					return nil
+				}
+			}
+
+			undo, err := service.Restore(context.Background(), args[0])
+			if err != nil {
+				return err
+			}
+			if undo.ID != "" {
+				fmt.Fprintf(out, "Restored %s, the previous ratings are in snapshot %s\n", args[0], undo.ID)
+			} else {
+				fmt.Fprintf(out, "Restored %s\n", args[0])
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Restore without asking")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only show what would change")
+	return cmd
+}
+
+// completeSnapshots suggests the snapshot IDs, newest first, with when they were taken
+func completeSnapshots(service backupPort.Service) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		snapshots, _ := service.List(ctx)
+		ids := make([]string, 0, len(snapshots))
+		for _, s := range snapshots {
+			ids = append(ids, s.ID+"\t"+s.Taken.Local().Format("Mon 2006-01-02 15:04"))
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	}
+}
+
+// changeSide shows one side of a restore's change, a missing day being absent from it
+func changeSide(dr *rating.DayRating) string {
+	if dr == nil {
+		return "none"
+	}
+	return conflictSide(dr)
+}
+
+func problemsLabel(n int) string {
+	if n == 1 {
+		return "1 problem"
+	}
+	return fmt.Sprintf("%d problems", n)
+}
+
+func printProblems(out io.Writer, problems []rating.Problem) {
+	for _, p := range problems {
+		fmt.Fprintf(out, "  %s\n", p)
+	}
+}
+
+func sizeLabel(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f KiB", float64(n)/1024)
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/adapters/primary/jsonview"
+	"track/internal/track/domain/rating"
+	ratingPort "track/internal/track/ports/primary/rating"
+)
+
+func newCheckInCmd(service ratingPort.Service, clk Clock) *cobra.Command {
+	var (
+		dayID   string
+		weekday string
+		note    string
+		at      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "checkin <rating>",
+		Short: "Rate how the day is going right now, one of several check-ins.",
+		Long: `Rate how the day is going right now, one of several check-ins.
+
+The day's rating is the aggregate of its check-ins, set with rating.aggregation
+in ~/.track.properties: last (default), mean, min or max. A day's rating is a
+whole rating, so the mean is rounded to the nearest, halves up: check-ins of 3
+and 4 rate the day 4.`,
+		Args:              UsageArgs(cobra.ExactArgs(1)),
+		ValidArgsFunction: completeRatings,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			now := clk.Now()
+			target, err := resolveTarget(now, dayID, weekday)
+			if err != nil {
+				return err
+			}
+			moment, err := checkInTime(target, now, at)
+			if err != nil {
+				return err
+			}
+			value, err := parseRatingArg(args[0])
+			if err != nil {
+				return err
+			}
+
+			dr, err := service.AddCheckIn(ratingPort.WithSource(ctx, rating.SourceCLI), moment, value, note)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Checked in %s %s %s, %s is %s %s from %s\n",
+				moment.Format("15:04"), value.String(), value.Emoji(),
+				dr.ID, dr.Rating.String(), dr.Rating.Emoji(), checkInsLabel(len(dr.CheckIns)))
+			return nil
+		},
+	}
+
+	addDayFlags(cmd, &dayID, &weekday, service, clk)
+	cmd.Flags().StringVarP(&note, "note", "n", "", "Optional note to store with the check-in")
+	cmd.Flags().StringVar(&at, "at", "", "Time of the check-in as HH:MM, default now")
+	return cmd
+}
+
+func newCheckInsCmd(service ratingPort.Service, clk Clock) *cobra.Command {
+	var (
+		dayID   string
+		weekday string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "checkins",
+		Short: "List a day's check-ins, default today.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			target, err := resolveTarget(clk.Now(), dayID, weekday)
+			if err != nil {
+				return err
+			}
+			dr, err := service.GetDayRating(ctx, target)
+			if err != nil {
+				return fmt.Errorf("getting %s: %w", rating.DayID(target), err)
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOutput(cmd) {
+				return writeJSON(out, jsonview.NewDayRating(dr))
+			}
+
+			if len(dr.CheckIns) == 0 {
+				fmt.Fprintf(out, "%s: %s %s, rated without check-ins\n", dr.ID, dr.Rating.String(), dr.Rating.Emoji())
+				return nil
+			}
+			fmt.Fprintf(out, "%s: %s %s from %s\n", dr.ID, dr.Rating.String(), dr.Rating.Emoji(), checkInsLabel(len(dr.CheckIns)))
+			for _, c := range dr.CheckIns {
+				fmt.Fprintf(out, "  %s  %s %s", c.At.In(dr.Date.Location()).Format("15:04"), c.Rating.Emoji(), c.Rating.String())
+				if c.Note != "" {
+					fmt.Fprintf(out, "  %s", c.Note)
+				}
+				fmt.Fprintln(out)
+			}
+			return nil
+		},
+	}
+
+	addDayFlags(cmd, &dayID, &weekday, service, clk)
+	return cmd
+}
+
+// checkInTime places a check-in on the target day at the --at time, or the current time of day
+func checkInTime(target, now time.Time, at string) (time.Time, error) {
+	hour, minute, second := now.Clock()
+	if at != "" {
+		parsed, err := time.Parse("15:04", at)
+		if err != nil {
+			return time.Time{}, rating.Errorf(rating.KindValidation, "invalid --at time %q, expected HH:MM", at)
+		}
+		hour, minute, second = parsed.Hour(), parsed.Minute(), 0
+	}
+	y, m, d := target.Date()
+	return time.Date(y, m, d, hour, minute, second, 0, now.Location()), nil
+}
+
+// checkInCount notes how many check-ins a day's rating comes from
+func checkInCount(dr rating.DayRating) string {
+	if len(dr.CheckIns) < 2 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", checkInsLabel(len(dr.CheckIns)))
+}
+
+func checkInsLabel(n int) string {
+	if n == 1 {
+		return "1 check-in"
+	}
+	return fmt.Sprintf("%d check-ins", n)
+}
@@ -1,41 +1,88 @@
 package cli
 
 import (
-	ratingService "track/internal/track/application/rating" // aliased this import
+	"track/internal/track/adapters/primary/jsonview"
 	"track/internal/track/domain/rating"
+	activityPort "track/internal/track/ports/primary/activity"
+	goalsPort "track/internal/track/ports/primary/goals"
+	ratingPort "track/internal/track/ports/primary/rating"
 
 	// "track/internal/track/domain/short"
 	"strconv"
+	"strings"
 
-	//"track/internal/track/ports/primary/rating"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
 	"time"
 )
 
-func NewRootCmd(ratingService *ratingService.Service) *cobra.Command {
+// Clock is the time source for commands, the --as-of flag pins its date
+type Clock interface {
+	Now() time.Time
+	SetAsOf(date time.Time)
+}
+
+// NewRootCmd builds the commands every configuration has, activity is nil when metrics aren't shown
+func NewRootCmd(ratingService ratingPort.Service, goalService goalsPort.Service, activity activityPort.Service, clk Clock) *cobra.Command {
+	var (
+		asOf   string
+		debug  bool
+		asJSON bool
+	)
+
 	rootCmd := &cobra.Command{
 		Use:   "track",
 		Short: "Track the important stuff",
+		// Execute reports errors, so cobra stays quiet
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if asOf == "" {
+				return nil
+			}
+			date, err := parseAsOf(asOf)
+			if err != nil {
+				return err
+			}
+			clk.SetAsOf(date)
+			return nil
+		},
 	}
 
+	rootCmd.PersistentFlags().StringVar(&asOf, "as-of", "", "Behave as if today were this date, YYYY-MM-DD or YYwWW-D")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Show the full error chain when a command fails")
+	rootCmd.PersistentFlags().BoolVar(&asJSON, "json", false, "Machine-readable output for results and errors")
+	_ = rootCmd.RegisterFlagCompletionFunc("as-of", completeDayIDs(ratingService, clk))
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{path: cmd.CommandPath(), err: err}
+	})
+
 	rootCmd.AddCommand(
-		newDayCmd(ratingService),
+		newDayCmd(ratingService, goalService, activity, clk),
+		newGoalsCmd(goalService),
+		newMetricsCmd(ratingService),
 	)
 	return rootCmd
 }
 
-func newDayCmd(service *ratingService.Service) *cobra.Command {
+func newDayCmd(service ratingPort.Service, goals goalsPort.Service, activity activityPort.Service, clk Clock) *cobra.Command {
 	dayCmd := &cobra.Command{
 		Use:   "day",
 		Short: "Day rating",
 	}
 
 	dayCmd.AddCommand(
-		newSetCmd(service),
+		newSetCmd(service, clk),
+		newGetCmd(service, clk),
+		newDeleteCmd(service, clk),
+		newCheckInCmd(service, clk),
+		newCheckInsCmd(service, clk),
 		newListCmd(service),
-		newWeekCmd(service),
+		newWeekCmd(service, goals, activity, clk),
+		newGapsCmd(service, clk),
+		newStatsCmd(service, clk),
 	)
 
 	return dayCmd
@@ -54,34 +101,37 @@ func parseInt(s string) int {
 func parseDayID(id string) (time.Time, error) {
 	// Parse format: YYwWW-D
 	if len(id) != 7 || id[2] != 'w' || id[5] != '-' {
-		return time.Time{}, fmt.Errorf("invalid format, expected YYwWW-D")
+		return time.Time{}, rating.Errorf(rating.KindValidation, "invalid format, expected YYwWW-D")
 	}
 
-	year, _ := strconv.Atoi("20" + id[0:2])
-	week, _ := strconv.Atoi(id[3:5])
-	day, _ := strconv.Atoi(id[6:])
-
-	day = day % 7
+	year, yErr := strconv.Atoi("20" + id[0:2])
+	week, wErr := strconv.Atoi(id[3:5])
+	day, dErr := strconv.Atoi(id[6:])
+	if yErr != nil || wErr != nil || dErr != nil {
+		return time.Time{}, rating.Errorf(rating.KindValidation, "invalid format, expected YYwWW-D")
+	}
+	if week < 1 || week > 53 {
+		return time.Time{}, rating.Errorf(rating.KindValidation, "invalid format, week range from 1-53")
+	}
 	if day < 0 || day > 7 {
-		return time.Time{}, fmt.Errorf("invalid format, isoWeekDay range from 1-7, with Mon as start of the week")
+		return time.Time{}, rating.Errorf(rating.KindValidation, "invalid format, isoWeekDay range from 1-7, with Mon as start of the week")
 	}
 
-	//d,w,y => d, m, y
-	//startDate, convert to ordinal days, add weeks, plus day
+	// ISO week 1 is the week containing January 4th, so walk back to its Monday
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
 
-	// get the absDay of start of year and add week days + weekday
-	start := time.Date(year, time.Month(1), 1, 0, 0, 0, 0, time.UTC)
-	days := ((week - 1) * 7) + day
-	date := start.Add(time.Duration(time.Second.Nanoseconds() * int64(86400*days))) //int64(days * time.Second)))
+	// Day IDs are written with Go's weekday numbering (0 = Sunday), accept 7 for Sunday too
+	offset := (day + 6) % 7
 
-	return date, nil
+	return monday.AddDate(0, 0, (week-1)*7+offset), nil
 }
 
 // GetWeekdayInISOWeek returns the date for the specified weekday (1-7)
 // in the ISO week of the provided reference date
 func GetWeekdayInISOWeek(referenceDate time.Time, weekday int) (time.Time, error) {
 	if weekday < 1 || weekday > 7 {
-		return time.Time{}, fmt.Errorf("invalid weekday: %d, must be between 1 and 7", weekday)
+		return time.Time{}, rating.Errorf(rating.KindValidation, "invalid weekday: %d, must be between 1 and 7", weekday)
 	}
 
 	// Get the ISO year and week of the reference date
@@ -126,6 +176,67 @@ func convertToWeekday(isoWeekday int) time.Weekday {
 	return time.Weekday(isoWeekday)
 }
 
+// parseAsOf reads the --as-of date as either YYYY-MM-DD or a day ID
+func parseAsOf(value string) (time.Time, error) {
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+	date, err := parseDayID(value)
+	if err != nil {
+		return time.Time{}, rating.Errorf(rating.KindValidation, "invalid --as-of date %q, expected YYYY-MM-DD or YYwWW-D", value)
+	}
+	return date, nil
+}
+
+// parseRatingArg reads a rating given as a command argument
+func parseRatingArg(arg string) (rating.Rating, error) {
+	number, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, rating.Errorf(rating.KindValidation, "rating must be a number between %d and %d, got %q", rating.Bad, rating.Awesome, arg)
+	}
+	return rating.NewRating(number)
+}
+
+// lateMarker flags ratings entered after their day, with when they were entered
+func lateMarker(dr rating.DayRating) string {
+	if !dr.RatedLate() {
+		return ""
+	}
+	return fmt.Sprintf("  ⏱ rated late, %s", dr.CreatedAt.In(dr.Date.Location()).Format("Mon 02 Jan"))
+}
+
+// resolveTarget works out the day a command applies to from the --long and --weekday flags
+func resolveTarget(now time.Time, dayID, weekday string) (time.Time, error) {
+	target := now
+
+	if dayID != "" {
+		// Add rating for specified day
+		parsedDate, err := parseDayID(dayID)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid day ID format: %w", err)
+		}
+		target = time.Date(parsedDate.Year(), parsedDate.Month(), parsedDate.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	if weekday != "" {
+		day, err := strconv.Atoi(weekday)
+		if err != nil {
+			// Not a number, so a name like mon or friday
+			name, err := rating.ParseWeekday(weekday)
+			if err != nil {
+				return time.Time{}, err
+			}
+			day = rating.ISOWeekday(name)
+		}
+		target, err = GetWeekdayInISOWeek(target, day)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return target, nil
+}
+
 //	func isoWeekStart(year, week int) time.Time {
 //		// Find a day in the week
 //		jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.Local)
@@ -134,92 +245,283 @@ func convertToWeekday(isoWeekday int) time.Weekday {
 //		daysSinceJan4 := (week - w) * 7
 //		return jan4.AddDate(0, 0, daysSinceJan4-int(jan4.Weekday())+1)
 //	}
-func newSetCmd(service *ratingService.Service) *cobra.Command {
+func newSetCmd(service ratingPort.Service, clk Clock) *cobra.Command {
 	var (
 		dayID   string
 		weekday string
-		target  time.Time
+		note    string
+		force   bool
+		update  bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "set [rating]",
 		Short: "Set a day rating between 1 and 5, for today.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Set a day rating between 1 and 5, for today.
+
+Without a rating argument and with a terminal attached, set walks through
+an interactive prompt. Scripts should always pass the rating.
+
+A day that is already rated is not overwritten: set asks first in a
+terminal and fails otherwise. Pass --update to change an existing rating,
+keeping its note unless --note is given, or --force to replace it.`,
+		Args:              UsageArgs(cobra.MaximumNArgs(1)),
+		ValidArgsFunction: completeRatings,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if force && update {
+				return &usageError{path: cmd.CommandPath(), err: fmt.Errorf("--force and --update can't be used together")}
+			}
+
+			target, err := resolveTarget(clk.Now(), dayID, weekday)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				if !stdinIsTerminal() {
+					return &usageError{
+						path: cmd.CommandPath(),
+						err:  fmt.Errorf("rating required, expected a value between %d and %d", rating.Bad, rating.Awesome),
+					}
+				}
+				// The prompt waits on the user, so it is not bound by the command timeout
+				return runSetPrompt(context.Background(), cmd, service, target, note)
+			}
+
+			value, err := parseRatingArg(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx = ratingPort.WithSource(ctx, rating.SourceCLI)
+			switch {
+			case force:
+				_, err = service.SetDayRating(ctx, target, value, note)
+			case update:
+				_, err = updateRating(ctx, service, target, value, note, cmd.Flags().Changed("note"))
+			default:
+				_, err = service.CreateDayRating(ctx, target, value, note)
+				if errors.Is(err, rating.ErrAlreadyRated) && stdinIsTerminal() {
+					// Waits on the user like the prompt, so not bound by the command timeout
+					err = confirmOverwrite(context.Background(), cmd, service, target, value, note)
+				}
+			}
+			switch {
+			case errors.Is(err, rating.ErrAlreadyRated):
+				return fmt.Errorf("%w, pass --update to change it", err)
+			case errors.Is(err, rating.ErrHasCheckIns):
+				return fmt.Errorf("%w, add a check-in or pass --force to replace them", err)
+			}
+			return err
+		},
+	}
+
+	addDayFlags(cmd, &dayID, &weekday, service, clk)
+	cmd.Flags().StringVarP(&note, "note", "n", "", "Optional note to store with the rating")
+	cmd.Flags().BoolVarP(&update, "update", "u", false, "Change the day's existing rating")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Replace the day's rating and note whether or not it is rated")
+	return cmd
+}
+
+// updateRating changes target's rating, keeping its note unless a new one was given,
+// and fails if someone else changes the day in between
+func updateRating(ctx context.Context, service ratingPort.Service, target time.Time, value rating.Rating, note string, newNote bool) (rating.DayRating, error) {
+	current, err := service.GetDayRating(ctx, target)
+	if err != nil {
+		return rating.DayRating{}, fmt.Errorf("getting %s: %w", rating.DayID(target), err)
+	}
+	if !newNote {
+		note = current.Note
+	}
+	return service.UpdateDayRating(ctx, target, value, note, current.Revision)
+}
+
+func newGetCmd(service ratingPort.Service, clk Clock) *cobra.Command {
+	var (
+		dayID   string
+		weekday string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show the rating for a day, default today.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			target, err := resolveTarget(clk.Now(), dayID, weekday)
+			if err != nil {
+				return err
+			}
+
+			dr, err := service.GetDayRating(ctx, target)
+			if err != nil {
+				return fmt.Errorf("getting %s: %w", rating.DayID(target), err)
+			}
+
+			if jsonOutput(cmd) {
+				return writeJSON(cmd.OutOrStdout(), jsonview.NewDayRating(dr))
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), dr)
+			if dr.Note != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Note: %s\n", dr.Note)
+			}
+			return nil
+		},
+	}
+
+	addDayFlags(cmd, &dayID, &weekday, service, clk)
+	return cmd
+}
+
+func newDeleteCmd(service ratingPort.Service, clk Clock) *cobra.Command {
+	var (
+		dayID   string
+		weekday string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "delete",
+		Aliases: []string{"rm"},
+		Short:   "Remove the rating for a day, default today.",
+		Args:    UsageArgs(cobra.NoArgs),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			value, err := rating.NewRating(parseInt(args[0])) // using domain package
+
+			target, err := resolveTarget(clk.Now(), dayID, weekday)
 			if err != nil {
 				return err
 			}
 
-			target = time.Now()
+			if err := service.DeleteDayRating(ctx, target); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s\n", rating.DayID(target))
+			return nil
+		},
+	}
+
+	addDayFlags(cmd, &dayID, &weekday, service, clk)
+	return cmd
+}
+
+func newGapsCmd(service ratingPort.Service, clk Clock) *cobra.Command {
+	var fill int
+
+	cmd := &cobra.Command{
+		Use:   "gaps",
+		Short: "List unrated days since the last rating, optionally filling them.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
 
-			if dayID != "" {
-				// Add rating for specified day
-				parsedDate, err := parseDayID(dayID)
+			// Today is still open for rating, so gaps run up to but not including it
+			today := rating.DayStart(clk.Now())
+			lastRating, err := service.GetLastRatingBefore(ctx, today)
+			if err != nil {
+				return fmt.Errorf("getting last rating: %w", err)
+			}
+			if lastRating.Date.IsZero() {
+				fmt.Fprintln(cmd.OutOrStdout(), "No ratings in the last 30 days")
+				return nil
+			}
+
+			if fill != 0 {
+				value, err := rating.NewRating(fill)
 				if err != nil {
-					fmt.Errorf("invalid day ID format: %w", err)
 					return err
 				}
-				target = time.Date(parsedDate.Year(), parsedDate.Month(), parsedDate.Day(), 0, 0, 0, 0, time.UTC)
+				filled, err := service.FillMissingRatings(ctx, lastRating.Date, today, value)
+				if err != nil {
+					return fmt.Errorf("filling gaps: %w", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Filled %d missing days with rating %s\n", len(filled), value.String())
+				return nil
 			}
 
-			if weekday != "" {
-				day, iWeekdayErr := strconv.Atoi(weekday)
-				if iWeekdayErr != nil {
-					fmt.Errorf("invalid weekday format: %w", iWeekdayErr)
-					return iWeekdayErr
-				}
-				target, _ = GetWeekdayInISOWeek(target, day)
-				// fmt.Printf("target %d, %d, %d\n", target.Year(), target.Month(), target.Day())
+			missing, err := service.GetMissingDays(ctx, lastRating.Date, today)
+			if err != nil {
+				return fmt.Errorf("finding gaps: %w", err)
+			}
+			if len(missing) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No gaps since", lastRating.Label())
+				return nil
+			}
+			for _, day := range missing {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", rating.DayID(day), day.Format("Mon 02 Jan"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&fill, "fill", "f", 0, "Rate every gap with this value")
+	return cmd
+}
+
+func newStatsCmd(service ratingPort.Service, clk Clock) *cobra.Command {
+	var (
+		days        int
+		excludeLate bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show average, distribution and streaks over recent days.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if days < 1 {
+				return rating.Errorf(rating.KindValidation, "days must be at least 1, got %d", days)
 			}
-			_, err = service.SetDayRating(ctx, target, value)
+			now := clk.Now()
+			stats, err := service.GetStats(ctx, now.AddDate(0, 0, -(days-1)), now, rating.StatsOptions{ExcludeLate: excludeLate})
 			if err != nil {
-				return err
+				return fmt.Errorf("getting stats: %w", err)
 			}
 
-			//fmt.Printf("rating: %s\n", dayRating)
-			// } else {
-			//
-			//
-			// 	// fmt.Println("parseDayID", date.Local())
-			// 	_, err = service.SetDayRating(ctx, date, value)
-			// 	if err != nil {
-			// 		return err
-			// 	}
-			// 	//fmt.Printf("Added rating for %s: %s\n", dayID, dayRating)
-			//
-			// 	// Fill gaps if requested
-			// 	if fillGaps {
-			// 		lastRating, err := service.GetLastRatingBefore(ctx, date)
-			// 		if err != nil {
-			// 			return fmt.Errorf("getting last rating: %w", err)
-			// 		}
-			//
-			// 		if !lastRating.Date.IsZero() {
-			// 			filled, err := service.FillMissingRatings(ctx, lastRating.Date, date, value)
-			// 			if err != nil {
-			// 				return fmt.Errorf("filling gaps: %w", err)
-			// 			}
-			// 			if len(filled) > 0 {
-			// 				fmt.Printf("Filled %d missing days with rating %s\n", len(filled), value.String())
-			// 			}
-			// 		}
-			// }
-			// },
-			//
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Last %d Days:\n", days)
+			fmt.Fprintf(out, "─────────────────────\n")
+			if stats.Count == 0 {
+				fmt.Fprintln(out, "No ratings recorded")
+				if excludeLate && stats.Late > 0 {
+					fmt.Fprintf(out, "(%d rated late, excluded)\n", stats.Late)
+				}
+				return nil
+			}
+			fmt.Fprintf(out, "Days Rated:     %d\n", stats.Count)
+			if stats.Late > 0 {
+				if excludeLate {
+					fmt.Fprintf(out, "Rated Late:     %d, excluded\n", stats.Late)
+				} else {
+					fmt.Fprintf(out, "Rated Late:     %d\n", stats.Late)
+				}
+			}
+			fmt.Fprintf(out, "Average:        %.1f\n", stats.Average)
+			fmt.Fprintf(out, "Current Streak: %d\n", stats.CurrentStreak)
+			fmt.Fprintf(out, "Longest Streak: %d\n", stats.LongestStreak)
+			fmt.Fprintln(out)
+			for r := rating.Awesome; r >= rating.Bad; r-- {
+				fmt.Fprintf(out, "%s %-8s %s %d\n", r.Emoji(), r.String(), strings.Repeat("█", stats.Distribution[r]), stats.Distribution[r])
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&dayID, "long", "l", "", "Day ID in format YYwWW-D. 25w05-3")
-	cmd.Flags().StringVarP(&weekday, "weekday", "d", "", "Week Day 1-7 (e.g. 1 = Monday")
-	// cmd.Flags().BoolVarP(&fillGaps, "fill", "f", false, "Fill missing days from last entry")
+	cmd.Flags().IntVarP(&days, "days", "n", 30, "Number of days to include, ending today")
+	cmd.Flags().BoolVar(&excludeLate, "exclude-late", false, "Leave out ratings entered after their day")
 	return cmd
 }
 
-func newListCmd(service *ratingService.Service) *cobra.Command {
+func newListCmd(service ratingPort.Service) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List ratings for a week, default current.",
@@ -232,9 +534,18 @@ func newListCmd(service *ratingService.Service) *cobra.Command {
 				return err
 			}
 
+			out := cmd.OutOrStdout()
+			if jsonOutput(cmd) {
+				list := make([]jsonview.DayRating, 0, len(ratings))
+				for _, r := range ratings {
+					list = append(list, jsonview.NewDayRating(r))
+				}
+				return writeJSON(out, list)
+			}
+
 			// Display ratings
 			for _, r := range ratings {
-				fmt.Printf("%s: %s %s\n", r.Label(), r.Rating.String(), r.Rating.Emoji())
+				fmt.Fprintf(out, "%s: %s %s\n", r.Label(), r.Rating.String(), r.Rating.Emoji())
 			}
 			return nil
 		},
@@ -243,7 +554,7 @@ func newListCmd(service *ratingService.Service) *cobra.Command {
 	return cmd
 }
 
-func newWeekCmd(service *ratingService.Service) *cobra.Command {
+func newWeekCmd(service ratingPort.Service, goals goalsPort.Service, activity activityPort.Service, clk Clock) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "report",
 		Short: "Show ratings for current week with optional trend analysis",
@@ -251,53 +562,78 @@ func newWeekCmd(service *ratingService.Service) *cobra.Command {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
+			out := cmd.OutOrStdout()
+
 			// Current week summary and details (existing code)
-			year, week := time.Now().ISOWeek()
+			year, week := clk.Now().ISOWeek()
 			summary, err := service.GetWeekSummary(ctx, year, week)
 			if err != nil {
 				return fmt.Errorf("getting week summary: %w", err)
 			}
 
 			// Print summary header
-			fmt.Printf("Week %d, %d Summary:\n", summary.Week, summary.Year)
-			fmt.Printf("─────────────────────\n")
+			fmt.Fprintf(out, "Week %d, %d Summary:\n", summary.Week, summary.Year)
+			fmt.Fprintf(out, "─────────────────────\n")
 
 			if summary.DayCount == 0 {
-				fmt.Println("No ratings recorded this week")
-				return nil
+				fmt.Fprintln(out, "No ratings recorded this week")
+				return printGoalsAtRisk(ctx, out, goals)
 			}
 
 			// Print stats
-			fmt.Printf("Days Rated: %d\n", summary.DayCount)
-			fmt.Printf("Average:    %.1f\n", summary.Average)
-			fmt.Printf("Best Day:   %s %s\n", summary.Best.Label(), summary.Best.Rating.Emoji())
-			fmt.Printf("Worst Day:  %s %s\n", summary.Worst.Label(), summary.Worst.Rating.Emoji())
+			fmt.Fprintf(out, "Days Rated: %d\n", summary.DayCount)
+			fmt.Fprintf(out, "Average:    %.1f\n", summary.Average)
+			fmt.Fprintf(out, "Best Day:   %s %s\n", summary.Best.Label(), summary.Best.Rating.Emoji())
+			fmt.Fprintf(out, "Worst Day:  %s %s\n", summary.Worst.Label(), summary.Worst.Rating.Emoji())
 
 			// Get detailed ratings for the week
 			ratings, err := service.GetWeekRatings(ctx, year, week)
 			if err != nil {
 				return fmt.Errorf("getting week ratings: %w", err)
 			}
+			var metrics dayMetrics
+			if activity != nil && len(ratings) > 0 {
+				first, last := ratings[0].Date, ratings[0].Date
+				for _, r := range ratings {
+					if r.Date.Before(first) {
+						first = r.Date
+					}
+					if r.Date.After(last) {
+						last = r.Date
+					}
+				}
+				metrics, err = activity.Metrics(ctx, first, last)
+				if err != nil {
+					return fmt.Errorf("getting metrics: %w", err)
+				}
+			}
 
 			// Print daily list
-			fmt.Printf("\nDaily List:\n")
-			fmt.Printf("───────────────\n")
+			fmt.Fprintf(out, "\nDaily List:\n")
+			fmt.Fprintf(out, "───────────────\n")
 			for _, r := range ratings {
-				fmt.Printf("%s: %s %s\n",
+				fmt.Fprintf(out, "%s: %s %s%s%s%s\n",
 					r.Date.Format("Mon"),
 					r.Rating.String(),
 					r.Rating.Emoji(),
+					checkInCount(r),
+					metrics.label(r.Date),
+					lateMarker(r),
 				)
 			}
 
+			if err := printGoalsAtRisk(ctx, out, goals); err != nil {
+				return err
+			}
+
 			//todo; Print daily grid
 
 			// Add 13-week trend analysis
-			fmt.Printf("\n13-Week Trend:\n")
-			fmt.Printf("────���────────\n")
+			fmt.Fprintf(out, "\n13-Week Trend:\n")
+			fmt.Fprintf(out, "──────────────\n")
 
 			// Calculate start date (13 weeks ago)
-			currentDate := time.Now()
+			currentDate := clk.Now()
 			startDate := currentDate.AddDate(0, 0, -13*7)
 
 			trends, err := service.GetDateRangeRatings(ctx, startDate, currentDate)
@@ -331,10 +667,10 @@ func newWeekCmd(service *ratingService.Service) *cobra.Command {
 							trend = "↓"
 						}
 					}
-					fmt.Printf("Week %02d: %.1f %s (%d days)\n", w, avg, trend, count)
+					fmt.Fprintf(out, "Week %02d: %.1f %s (%d days)\n", w, avg, trend, count)
 					lastAvg = avg
 				} else {
-					fmt.Printf("Week %02d: No data\n", w)
+					fmt.Fprintf(out, "Week %02d: No data\n", w)
 				}
 			}
 
@@ -1,9 +1,19 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"github.com/magiconair/properties/assert"
+	"sort"
+	"strings"
 	"testing"
 	"time"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/memory"
+	ratingService "track/internal/track/application/rating"
+	"track/internal/track/domain/rating"
+	ratingPort "track/internal/track/ports/primary/rating"
 )
 
 func TestParseDayID(t *testing.T) {
@@ -15,3 +25,259 @@ func TestParseDayID(t *testing.T) {
 	assert.Equal(t, got.YearDay(), want.YearDay())
 	//t.Errorf("Abs(-1) = %d; want 1")
 }
+
+// fakeService keeps ratings in a map. Every port method is spelled out so a change to
+// the port breaks the build here instead of panicking in a test; the summaries
+// no command test reads return errNotFaked.
+type fakeService struct {
+	ratings map[string]rating.DayRating
+}
+
+var _ ratingPort.Service = (*fakeService)(nil)
+
+func newFakeService() *fakeService {
+	return &fakeService{ratings: make(map[string]rating.DayRating)}
+}
+
+func errNotFaked(method string) error {
+	return rating.Errorf(rating.KindInternal, "fakeService doesn't fake %s", method)
+}
+
+func (f *fakeService) CreateDayRating(_ context.Context, date time.Time, r rating.Rating, note string) (rating.DayRating, error) {
+	if _, ok := f.ratings[rating.DayID(date)]; ok {
+		return rating.DayRating{}, rating.ErrAlreadyRated
+	}
+	dr := rating.DayRating{ID: rating.DayID(date), Date: date, Rating: r, Note: note, Revision: 1}
+	f.ratings[dr.ID] = dr
+	return dr, nil
+}
+
+func (f *fakeService) SetDayRating(_ context.Context, date time.Time, r rating.Rating, note string) (rating.DayRating, error) {
+	dr := rating.DayRating{ID: rating.DayID(date), Date: date, Rating: r, Note: note}
+	dr.Revision = f.ratings[dr.ID].Revision + 1
+	f.ratings[dr.ID] = dr
+	return dr, nil
+}
+
+func (f *fakeService) GetDayRating(_ context.Context, date time.Time) (rating.DayRating, error) {
+	dr, ok := f.ratings[rating.DayID(date)]
+	if !ok {
+		return rating.DayRating{}, rating.ErrNotFound
+	}
+	return dr, nil
+}
+
+func (f *fakeService) GetTodayRating(context.Context) (rating.DayRating, error) {
+	return rating.DayRating{}, errNotFaked("GetTodayRating")
+}
+
+func (f *fakeService) IsDayRated(_ context.Context, date time.Time) (bool, error) {
+	_, ok := f.ratings[rating.DayID(date)]
+	return ok, nil
+}
+
+func (f *fakeService) UpdateDayRating(_ context.Context, date time.Time, r rating.Rating, note string, revision int) (rating.DayRating, error) {
+	dr, ok := f.ratings[rating.DayID(date)]
+	if !ok {
+		return rating.DayRating{}, rating.ErrNotFound
+	}
+	if revision != 0 && revision != dr.Revision {
+		return rating.DayRating{}, rating.ErrStaleRevision
+	}
+	dr.Rating, dr.Note, dr.Revision = r, note, dr.Revision+1
+	f.ratings[dr.ID] = dr
+	return dr, nil
+}
+
+func (f *fakeService) UpdateTodayRating(context.Context, rating.Rating) (rating.DayRating, error) {
+	return rating.DayRating{}, errNotFaked("UpdateTodayRating")
+}
+
+func (f *fakeService) DeleteDayRating(_ context.Context, date time.Time) error {
+	if _, ok := f.ratings[rating.DayID(date)]; !ok {
+		return rating.ErrNotFound
+	}
+	delete(f.ratings, rating.DayID(date))
+	return nil
+}
+
+func (f *fakeService) AddCheckIn(ctx context.Context, at time.Time, r rating.Rating, note string) (rating.DayRating, error) {
+	return f.SetDayRating(ctx, rating.DayStart(at), r, note)
+}
+
+func (f *fakeService) GetWeekRatings(_ context.Context, year, week int) ([]rating.DayRating, error) {
+	var ratings []rating.DayRating
+	for _, dr := range f.sorted() {
+		if y, w := dr.Date.ISOWeek(); y == year && w == week {
+			ratings = append(ratings, dr)
+		}
+	}
+	return ratings, nil
+}
+
+func (f *fakeService) GetCurrentWeekRatings(context.Context) ([]rating.DayRating, error) {
+	return nil, errNotFaked("GetCurrentWeekRatings")
+}
+
+func (f *fakeService) GetDateRangeRatings(_ context.Context, start, end time.Time) ([]rating.DayRating, error) {
+	var ratings []rating.DayRating
+	for _, dr := range f.sorted() {
+		if !dr.Date.Before(start) && !dr.Date.After(end) {
+			ratings = append(ratings, dr)
+		}
+	}
+	return ratings, nil
+}
+
+func (f *fakeService) GetWeekSummary(context.Context, int, int) (rating.WeekSummary, error) {
+	return rating.WeekSummary{}, errNotFaked("GetWeekSummary")
+}
+
+func (f *fakeService) GetWeeklyTrend(context.Context, time.Time, int) ([]rating.WeekTrend, error) {
+	return nil, errNotFaked("GetWeeklyTrend")
+}
+
+func (f *fakeService) GetWeekdayBreakdown(context.Context, time.Time, time.Time) ([]rating.WeekdayStat, error) {
+	return nil, errNotFaked("GetWeekdayBreakdown")
+}
+
+func (f *fakeService) GetStats(context.Context, time.Time, time.Time, rating.StatsOptions) (rating.Stats, error) {
+	return rating.Stats{}, errNotFaked("GetStats")
+}
+
+func (f *fakeService) GetMetrics(context.Context) (rating.Metrics, error) {
+	return rating.Metrics{}, errNotFaked("GetMetrics")
+}
+
+func (f *fakeService) GetLastRatingBefore(_ context.Context, date time.Time) (rating.DayRating, error) {
+	var last rating.DayRating
+	for _, dr := range f.sorted() {
+		if dr.Date.Before(date) {
+			last = dr
+		}
+	}
+	return last, nil
+}
+
+func (f *fakeService) GetMissingDays(_ context.Context, start, end time.Time) ([]time.Time, error) {
+	var missing []time.Time
+	for current := start.AddDate(0, 0, 1); current.Before(end); current = current.AddDate(0, 0, 1) {
+		if _, ok := f.ratings[rating.DayID(current)]; !ok {
+			missing = append(missing, current)
+		}
+	}
+	return missing, nil
+}
+
+func (f *fakeService) FillMissingRatings(ctx context.Context, start, end time.Time, r rating.Rating) ([]rating.DayRating, error) {
+	missing, _ := f.GetMissingDays(ctx, start, end)
+	var filled []rating.DayRating
+	for _, day := range missing {
+		dr, _ := f.SetDayRating(ctx, day, r, "")
+		filled = append(filled, dr)
+	}
+	return filled, nil
+}
+
+func (f *fakeService) sorted() []rating.DayRating {
+	ratings := make([]rating.DayRating, 0, len(f.ratings))
+	for _, dr := range f.ratings {
+		ratings = append(ratings, dr)
+	}
+	sort.Slice(ratings, func(i, j int) bool { return ratings[i].Date.Before(ratings[j].Date) })
+	return ratings
+}
+
+func runRoot(t *testing.T, service ratingPort.Service, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	// None of the commands under test read goals
+	root := NewRootCmd(service, nil, nil, clock.NewAsOf(clock.NewFixed(time.Date(2025, time.February, 19, 9, 0, 0, 0, time.UTC))))
+	root.SetArgs(args)
+	root.SetOut(&out)
+	root.SetErr(&out)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestSetGetDeleteWithFake(t *testing.T) {
+	service := newFakeService()
+
+	if _, err := runRoot(t, service, "day", "set", "4", "--long", "25w08-1", "--note", "demo day"); err != nil {
+		t.Fatal(err)
+	}
+	dr, ok := service.ratings["25w08-1"]
+	assert.Equal(t, ok, true)
+	assert.Equal(t, dr.Date, time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, dr.Rating, rating.Good)
+
+	out, err := runRoot(t, service, "day", "get", "--long", "25w08-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, out, "25w08-1: Good 😊\nNote: demo day\n")
+
+	if _, err := runRoot(t, service, "day", "rm", "--long", "25w08-1"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = runRoot(t, service, "day", "rm", "--long", "25w08-1")
+	assert.Equal(t, errors.Is(err, rating.ErrNotFound), true)
+}
+
+func TestAsOf(t *testing.T) {
+	tests := []struct {
+		asOf string
+		want string
+	}{
+		{"2025-02-17", "25w08-1"},
+		{"25w01-0", "25w01-0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.asOf, func(t *testing.T) {
+			service := newFakeService()
+			if _, err := runRoot(t, service, "--as-of", tt.asOf, "day", "set", "3"); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := service.ratings[tt.want]; !ok {
+				t.Errorf("--as-of %s rated %v, want %s", tt.asOf, service.ratings, tt.want)
+			}
+		})
+	}
+
+	_, err := runRoot(t, newFakeService(), "--as-of", "last tuesday", "day", "set", "3")
+	if err == nil {
+		t.Error("expected an error for an unparseable --as-of")
+	}
+}
+
+func TestAsOfStampsTheRealTime(t *testing.T) {
+	wall := clock.NewFixed(time.Date(2025, time.February, 19, 9, 0, 0, 0, time.UTC))
+	clk := clock.NewAsOf(wall)
+	repo := memory.NewMemoryRepository()
+	root := NewRootCmd(ratingService.NewService(repo, clk, ratingService.WithWallClock(wall)), nil, nil, clk)
+	root.SetArgs([]string{"--as-of", "2025-02-17", "day", "set", "3"})
+	root.SetOut(&bytes.Buffer{})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	dr, err := repo.GetByID(context.Background(), "25w08-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, dr.CreatedAt, wall.Now())
+	assert.Equal(t, dr.UpdatedAt, wall.Now())
+	assert.Equal(t, dr.RatedLate(), true)
+}
+
+func TestCompletionScripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		out, err := runRoot(t, newFakeService(), "completion", shell)
+		if err != nil {
+			t.Fatalf("completion %s: %v", shell, err)
+		}
+		if !strings.Contains(out, "__complete") {
+			t.Errorf("completion %s doesn't call back into track for dynamic values", shell)
+		}
+	}
+}
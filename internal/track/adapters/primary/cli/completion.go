@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/domain/goal"
+	"track/internal/track/domain/rating"
+	goalsPort "track/internal/track/ports/primary/goals"
+	ratingPort "track/internal/track/ports/primary/rating"
+)
+
+// completionDays is how far back --long suggests day IDs
+const completionDays = 14
+
+// completionFunc suggests values for an argument or flag, as cobra's ValidArgsFunction
+type completionFunc = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// addDayFlags adds --long and --weekday, which pick the day a command applies to, with their completions
+func addDayFlags(cmd *cobra.Command, dayID, weekday *string, service ratingPort.Service, clk Clock) {
+	cmd.Flags().StringVarP(dayID, "long", "l", "", "Day ID in format YYwWW-D. 25w05-3")
+	cmd.Flags().StringVarP(weekday, "weekday", "d", "", "Day of this week, 1-7 or a name (1 or mon = Monday)")
+	_ = cmd.RegisterFlagCompletionFunc("long", completeDayIDs(service, clk))
+	_ = cmd.RegisterFlagCompletionFunc("weekday", completeWeekdays(clk))
+}
+
+// completeRatings suggests the rating values with their labels for a command's first argument
+func completeRatings(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var values []string
+	for r := rating.Awesome; r >= rating.Bad; r-- {
+		values = append(values, fmt.Sprintf("%d\t%s %s", r, r.String(), r.Emoji()))
+	}
+	return values, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeDayIDs suggests the recent days, newest first, with their rating or as unrated
+func completeDayIDs(service ratingPort.Service, clk Clock) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		today := rating.DayStart(clk.Now())
+		start := today.AddDate(0, 0, -(completionDays - 1))
+		rated := make(map[string]rating.DayRating)
+		// Completion still offers the days when the ratings can't be read. Today's
+		// rating is stamped with the time it was made, so the range runs to its end.
+		if ratings, err := service.GetDateRangeRatings(ctx, start, rating.DayEnd(today)); err == nil {
+			for _, dr := range ratings {
+				rated[dr.ID] = dr
+			}
+		}
+
+		var ids []string
+		for d := today; !d.Before(start); d = d.AddDate(0, 0, -1) {
+			id := rating.DayID(d)
+			status := "unrated"
+			if dr, ok := rated[id]; ok {
+				status = dr.Rating.String() + " " + dr.Rating.Emoji()
+			}
+			ids = append(ids, fmt.Sprintf("%s\t%s, %s", id, d.Format("Mon 02 Jan"), status))
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	}
+}
+
+// completeWeekdays suggests the days of this week, as numbers or names depending on what was typed
+func completeWeekdays(clk Clock) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		byName := toComplete != "" && !strings.ContainsAny(toComplete[:1], "0123456789")
+
+		var days []string
+		for iso := 1; iso <= 7; iso++ {
+			date, err := GetWeekdayInISOWeek(clk.Now(), iso)
+			if err != nil {
+				continue
+			}
+			name := strings.ToLower(date.Weekday().String())
+			if byName {
+				days = append(days, fmt.Sprintf("%s\t%s", name, date.Format("02 Jan")))
+			} else {
+				days = append(days, fmt.Sprintf("%d\t%s", iso, date.Format("Monday 02 Jan")))
+			}
+		}
+		return days, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	}
+}
+
+// completeGoalIDs suggests the goals' IDs with what they are
+func completeGoalIDs(service goalsPort.Service) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		goals, err := service.ListGoals(ctx)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var ids []string
+		for _, g := range goals {
+			ids = append(ids, g.ID+"\t"+g.String())
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	}
+}
+
+// completeGoalKinds suggests the kinds of goal for goals add
+func completeGoalKinds(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return []string{
+		string(goal.KindAverage) + "\taverage rating of at least the target",
+		string(goal.KindMaxBad) + "\tat most the target number of Bad days",
+		string(goal.KindEveryDay) + "\trate every day",
+	}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeFixed suggests the given values, for flags that take one of a few words
+func completeFixed(values ...string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	}
+}
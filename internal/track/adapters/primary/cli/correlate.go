@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/series"
+	correlatePort "track/internal/track/ports/primary/correlate"
+)
+
+// NewCorrelateCmd is added by the composition root, which knows how series files are read
+func NewCorrelateCmd(service correlatePort.Service, clk Clock) *cobra.Command {
+	var days, quantiles, bins int
+	var lags []int
+	var scatter bool
+	cmd := &cobra.Command{
+		Use:   "correlate <file>...",
+		Short: "Relate dated series such as sleep or steps to the day ratings.",
+		Long: `Relate dated series such as sleep or steps to the day ratings.
+
+Each file is a CSV with a header, or JSON: an array of objects or an object
+of numbers by date. The date is the column named date or day, or else the
+first, as YYYY-MM-DD; every other numeric column is a series. Values on the
+same day are added up, so a file with a line per commit counts commits.
+
+  date,sleep,steps
+  2025-02-17,7.5,8200
+
+For each series and lag the Pearson (linear) and Spearman (rank) coefficients
+are shown, a lag of 1 comparing yesterday's value with today's rating. The
+strongest lag of each series is then broken down by quantile of its values.`,
+		Example: `  track correlate sleep.csv --lag 0,1
+  track correlate health.json commits.csv --days 180 --scatter`,
+		Args: UsageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if days < 2 {
+				return &usageError{path: cmd.CommandPath(), err: fmt.Errorf("--days must be at least 2, got %d", days)}
+			}
+			for _, lag := range lags {
+				if lag < 0 {
+					return &usageError{path: cmd.CommandPath(), err: fmt.Errorf("--lag must not be negative, got %d", lag)}
+				}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			end := rating.DayEnd(clk.Now())
+			start := rating.DayStart(clk.Now()).AddDate(0, 0, -(days - 1))
+			results, err := service.Correlate(ctx, args, correlatePort.Options{
+				Start: start, End: end, Lags: lags, Quantiles: quantiles, Bins: bins,
+			})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOutput(cmd) {
+				list := make([]correlationJSON, 0, len(results))
+				for _, r := range results {
+					list = append(list, toCorrelationJSON(r))
+				}
+				return writeJSON(out, list)
+			}
+
+			fmt.Fprintf(out, "Ratings from %s to %s\n", rating.DayID(start), rating.DayID(end))
+			fmt.Fprintf(out, "─────────────────────\n")
+			fmt.Fprintf(out, "%-16s %-12s %5s %8s %9s\n", "Series", "Lag", "Days", "Pearson", "Spearman")
+			for _, r := range results {
+				fmt.Fprintf(out, "%-16s %-12s %5d %8s %9s  %s\n",
+					r.Series, lagLabel(r.Lag), r.N, coefficient(r.Pearson), coefficient(r.Spearman), series.Strength(r.Spearman))
+			}
+
+			for _, r := range strongest(results) {
+				if r.N == 0 {
+					continue
+				}
+				fmt.Fprintf(out, "\n%s, %s, by quantile:\n", r.Series, lagLabel(r.Lag))
+				for _, q := range r.Quantiles {
+					fmt.Fprintf(out, "  %10s to %-10s %4d days  average %.1f\n", number(q.Low), number(q.High), q.Days, q.Average)
+				}
+				if scatter {
+					fmt.Fprintln(out)
+					printScatter(out, r.Scatter)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&days, "days", 90, "Number of days to include, up to today")
+	cmd.Flags().IntSliceVar(&lags, "lag", []int{0, 1}, "Days each series value comes before the rating, e.g. 0,1,2")
+	cmd.Flags().IntVar(&quantiles, "quantiles", 4, "Number of groups in the quantile tables")
+	cmd.Flags().BoolVar(&scatter, "scatter", false, "Show a scatter plot of ratings against each series")
+	cmd.Flags().IntVar(&bins, "bins", 10, "Number of columns in the scatter plots")
+	return cmd
+}
+
+// strongest picks each series' result at the lag with the largest rank correlation, in series order
+func strongest(results []correlatePort.Result) []correlatePort.Result {
+	var picked []correlatePort.Result
+	index := make(map[string]int)
+	for _, r := range results {
+		i, ok := index[r.Series]
+		if !ok {
+			index[r.Series] = len(picked)
+			picked = append(picked, r)
+			continue
+		}
+		if strength(r.Spearman) > strength(picked[i].Spearman) {
+			picked[i] = r
+		}
+	}
+	return picked
+}
+
+func strength(r float64) float64 {
+	if math.IsNaN(r) {
+		return -1
+	}
+	return math.Abs(r)
+}
+
+// printScatter draws a rating against series value grid, counting the days in each cell
+func printScatter(out io.Writer, s series.Scatter) {
+	if len(s.Edges) < 2 {
+		return
+	}
+	for r := rating.Awesome; r >= rating.Bad; r-- {
+		var cells []string
+		for _, n := range s.Counts[r] {
+			switch {
+			case n == 0:
+				cells = append(cells, " ·")
+			case n > 9:
+				cells = append(cells, " +")
+			default:
+				cells = append(cells, fmt.Sprintf("%2d", n))
+			}
+		}
+		fmt.Fprintf(out, "  %s %d │%s\n", r.Emoji(), r, strings.Join(cells, ""))
+	}
+	width := 2 * (len(s.Edges) - 1)
+	low, high := number(s.Edges[0]), number(s.Edges[len(s.Edges)-1])
+	fmt.Fprintf(out, "       └%s\n", strings.Repeat("─", width))
+	fmt.Fprintf(out, "        %s%*s\n", low, max(width-len(low), len(high)+1), high)
+}
+
+func lagLabel(lag int) string {
+	switch lag {
+	case 0:
+		return "same day"
+	case 1:
+		return "1 day before"
+	}
+	return fmt.Sprintf("%d days before", lag)
+}
+
+func coefficient(r float64) string {
+	if math.IsNaN(r) {
+		return "-"
+	}
+	return fmt.Sprintf("%+.2f", r)
+}
+
+// number shows a series value without needless decimals
+func number(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
+
+type correlationJSON struct {
+	Series    string         `json:"series"`
+	Lag       int            `json:"lag"`
+	Days      int            `json:"days"`
+	Pearson   *float64       `json:"pearson"`
+	Spearman  *float64       `json:"spearman"`
+	Quantiles []quantileJSON `json:"quantiles"`
+}
+
+type quantileJSON struct {
+	Low     float64 `json:"low"`
+	High    float64 `json:"high"`
+	Days    int     `json:"days"`
+	Average float64 `json:"average"`
+}
+
+func toCorrelationJSON(r correlatePort.Result) correlationJSON {
+	// NaN has no JSON form, an undefined coefficient is null
+	optional := func(v float64) *float64 {
+		if math.IsNaN(v) {
+			return nil
+		}
+		return &v
+	}
+	j := correlationJSON{
+		Series:    r.Series,
+		Lag:       r.Lag,
+		Days:      r.N,
+		Pearson:   optional(r.Pearson),
+		Spearman:  optional(r.Spearman),
+		Quantiles: make([]quantileJSON, 0, len(r.Quantiles)),
+	}
+	for _, q := range r.Quantiles {
+		j.Quantiles = append(j.Quantiles, quantileJSON{Low: q.Low, High: q.High, Days: q.Days, Average: q.Average})
+	}
+	return j
+}
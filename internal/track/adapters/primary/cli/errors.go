@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/domain/rating"
+)
+
+// Exit codes, documented in the README
+const (
+	ExitOK         = 0
+	ExitError      = 1
+	ExitUsage      = 2
+	ExitValidation = 3
+	ExitNotFound   = 4
+	ExitConflict   = 5
+	ExitStorage    = 6
+	ExitConfig     = 7
+)
+
+// usageError marks a command invoked with the wrong flags or arguments
+type usageError struct {
+	path string
+	err  error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// UsageArgs reports argument validation failures as usage errors, for any command in the tree
+func UsageArgs(args cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, a []string) error {
+		if err := args(cmd, a); err != nil {
+			return &usageError{path: cmd.CommandPath(), err: err}
+		}
+		return nil
+	}
+}
+
+// ExitCode maps an error onto the documented exit codes
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var ue *usageError
+	if errors.As(err, &ue) {
+		return ExitUsage
+	}
+
+	switch rating.KindOf(err) {
+	case rating.KindValidation:
+		return ExitValidation
+	case rating.KindNotFound:
+		return ExitNotFound
+	case rating.KindConflict:
+		return ExitConflict
+	case rating.KindStorage:
+		return ExitStorage
+	case rating.KindConfig:
+		return ExitConfig
+	}
+	return ExitError
+}
+
+// errorKind names an error for machine output
+func errorKind(err error) string {
+	var ue *usageError
+	if errors.As(err, &ue) {
+		return "usage"
+	}
+	return rating.KindOf(err).String()
+}
+
+type errorJSON struct {
+	Kind     string   `json:"kind"`
+	Code     int      `json:"code"`
+	Message  string   `json:"message"`
+	Chain    []string `json:"chain,omitempty"`
+	Usage    string   `json:"usage,omitempty"`
+	Internal bool     `json:"internal,omitempty"`
+}
+
+// errorChain lists every layer of err, outermost first, with its Go type
+func errorChain(err error) []string {
+	var chain []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, fmt.Sprintf("%T: %s", err, err))
+	}
+	return chain
+}
+
+// ReportError writes err to w as a one-line message, or a JSON object, and returns the exit code.
+// With debug the full wrapping chain is included.
+func ReportError(w io.Writer, err error, debug, asJSON bool) int {
+	code := ExitCode(err)
+
+	var usage string
+	var ue *usageError
+	if errors.As(err, &ue) {
+		usage = fmt.Sprintf("Run '%s --help' for usage.", ue.path)
+	}
+
+	if asJSON {
+		out := errorJSON{
+			Kind:     errorKind(err),
+			Code:     code,
+			Message:  err.Error(),
+			Usage:    usage,
+			Internal: code == ExitError,
+		}
+		if debug {
+			out.Chain = errorChain(err)
+		}
+		enc := json.NewEncoder(w)
+		enc.Encode(map[string]errorJSON{"error": out})
+		return code
+	}
+
+	fmt.Fprintf(w, "error: %s\n", err)
+	switch {
+	case debug:
+		fmt.Fprintf(w, "kind: %s, exit code: %d\n", errorKind(err), code)
+		for i, layer := range errorChain(err) {
+			fmt.Fprintf(w, "  %d. %s\n", i+1, layer)
+		}
+	case usage != "":
+		fmt.Fprintln(w, usage)
+	case code == ExitError:
+		fmt.Fprintln(w, "Run again with --debug for details.")
+	}
+	return code
+}
+
+// Execute runs the command tree and reports any error on its stderr, returning the exit code
+func Execute(root *cobra.Command) int {
+	err := root.Execute()
+	if err == nil {
+		return ExitOK
+	}
+	debug, _ := root.PersistentFlags().GetBool("debug")
+	asJSON, _ := root.PersistentFlags().GetBool("json")
+	return ReportError(root.ErrOrStderr(), err, debug, asJSON)
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/domain/goal"
+	"track/internal/track/domain/rating"
+	goalsPort "track/internal/track/ports/primary/goals"
+)
+
+func newGoalsCmd(service goalsPort.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "goals",
+		Short: "Show progress on your goals for the current week and month.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			progress, err := service.GetProgress(ctx)
+			if err != nil {
+				return fmt.Errorf("getting goal progress: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOutput(cmd) {
+				results := make([]goalJSON, 0, len(progress))
+				for _, p := range progress {
+					results = append(results, toGoalJSON(p))
+				}
+				return writeJSON(out, results)
+			}
+
+			if len(progress) == 0 {
+				fmt.Fprintln(out, "No goals set, add one with: track goals add average 3.5 --per month")
+				return nil
+			}
+			for _, p := range progress {
+				fmt.Fprintf(out, "%-3s %-30s %s  %-14s %s\n",
+					p.Goal.ID, p.Goal.String(), progressBar(p.Fraction, 10), goalValue(p), statusLabel(p.Status))
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(
+		newGoalAddCmd(service),
+		newGoalRemoveCmd(service),
+	)
+	return cmd
+}
+
+func newGoalAddCmd(service goalsPort.Service) *cobra.Command {
+	var per string
+
+	cmd := &cobra.Command{
+		Use:   "add <kind> [target]",
+		Short: "Set a goal for every week or month.",
+		Long: `Set a goal for every week or month.
+
+  track goals add average 3.5 --per month   average rating of at least 3.5
+  track goals add max-bad 2                 no more than two Bad days a week
+  track goals add every-day                 rate every day of the week`,
+		Args:              UsageArgs(cobra.RangeArgs(1, 2)),
+		ValidArgsFunction: completeGoalKinds,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			kind := goal.Kind(args[0])
+			var target float64
+			switch {
+			case len(args) == 2:
+				var err error
+				if target, err = strconv.ParseFloat(args[1], 64); err != nil {
+					return rating.Errorf(rating.KindValidation, "goal target must be a number, got %q", args[1])
+				}
+			case kind == goal.KindAverage || kind == goal.KindMaxBad:
+				return &usageError{path: cmd.CommandPath(), err: fmt.Errorf("%s goals need a target", kind)}
+			}
+
+			g, err := service.AddGoal(ctx, kind, goal.Period(per), target)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Added goal %s: %s\n", g.ID, g)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&per, "per", string(goal.PeriodWeek), "Period the goal applies to, week or month")
+	_ = cmd.RegisterFlagCompletionFunc("per", completeFixed(string(goal.PeriodWeek), string(goal.PeriodMonth)))
+	return cmd
+}
+
+func newGoalRemoveCmd(service goalsPort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:               "rm <id>",
+		Short:             "Remove a goal.",
+		Args:              UsageArgs(cobra.ExactArgs(1)),
+		ValidArgsFunction: completeGoalIDs(service),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := service.RemoveGoal(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed goal %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+// printGoalsAtRisk lists the goals that need attention this period, nothing when all is well
+func printGoalsAtRisk(ctx context.Context, out io.Writer, service goalsPort.Service) error {
+	progress, err := service.GetProgress(ctx)
+	if err != nil {
+		return fmt.Errorf("getting goal progress: %w", err)
+	}
+
+	var atRisk []goal.Progress
+	for _, p := range progress {
+		if p.Status.NeedsAttention() {
+			atRisk = append(atRisk, p)
+		}
+	}
+	if len(atRisk) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(out, "\nGoals at Risk:\n")
+	fmt.Fprintf(out, "──────────────\n")
+	for _, p := range atRisk {
+		fmt.Fprintf(out, "%s: %s, %s\n", p.Goal, goalValue(p), statusLabel(p.Status))
+	}
+	return nil
+}
+
+// progressBar draws fraction as a bar of width cells
+func progressBar(fraction float64, width int) string {
+	filled := int(fraction*float64(width) + 0.5)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// goalValue is the measured value against the target, in the goal's terms
+func goalValue(p goal.Progress) string {
+	switch p.Goal.Kind {
+	case goal.KindAverage:
+		if p.Rated == 0 {
+			return fmt.Sprintf("none of %g", p.Goal.Target)
+		}
+		return fmt.Sprintf("%.1f of %g", p.Current, p.Goal.Target)
+	case goal.KindMaxBad:
+		return fmt.Sprintf("%d of %d Bad", int(p.Current), int(p.Goal.Target))
+	default:
+		return fmt.Sprintf("%d of %d days", p.Rated, p.Days)
+	}
+}
+
+func statusLabel(s goal.Status) string {
+	switch s {
+	case goal.StatusMet:
+		return "✓ " + string(s)
+	case goal.StatusAtRisk:
+		return "⚠ " + string(s)
+	case goal.StatusMissed:
+		return "✗ " + string(s)
+	}
+	return string(s)
+}
+
+type goalJSON struct {
+	ID          string  `json:"id"`
+	Kind        string  `json:"kind"`
+	Period      string  `json:"period"`
+	Target      float64 `json:"target,omitempty"`
+	Description string  `json:"description"`
+	Start       string  `json:"start"`
+	End         string  `json:"end"`
+	Current     float64 `json:"current"`
+	Fraction    float64 `json:"fraction"`
+	Rated       int     `json:"rated"`
+	Days        int     `json:"days"`
+	Status      string  `json:"status"`
+}
+
+func toGoalJSON(p goal.Progress) goalJSON {
+	return goalJSON{
+		ID:          p.Goal.ID,
+		Kind:        string(p.Goal.Kind),
+		Period:      string(p.Goal.Period),
+		Target:      p.Goal.Target,
+		Description: p.Goal.String(),
+		Start:       p.Start.Format("2006-01-02"),
+		End:         p.End.Format("2006-01-02"),
+		Current:     p.Current,
+		Fraction:    p.Fraction,
+		Rated:       p.Rated,
+		Days:        p.Days,
+		Status:      string(p.Status),
+	}
+}
@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/file"
+	"track/internal/track/adapters/secondary/notify"
+	goalService "track/internal/track/application/goals"
+	promptService "track/internal/track/application/prompt"
+	ratingService "track/internal/track/application/rating"
+	remindService "track/internal/track/application/remind"
+	"track/internal/track/domain/prompt"
+	"track/internal/track/domain/remind"
+	"track/internal/track/ports/secondary"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestMain(m *testing.M) {
+	// Golden output must not depend on the machine's zone
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+// script is a sequence of command lines run against one data file at a fixed time
+type script struct {
+	name  string
+	now   time.Time
+	lines []string
+}
+
+var scripts = []script{
+	{
+		name: "rate_week",
+		now:  time.Date(2025, time.February, 21, 20, 0, 0, 0, time.UTC),
+		lines: []string{
+			"day set 4 -d 1",
+			"day set 2 -d 2 --note 'migration went sideways'",
+			"day set 3 -d 3",
+			"day set 5",
+			"day list",
+			"--json day list",
+			"day get -d 2",
+			"day report",
+		},
+	},
+	{
+		name: "year_boundary",
+		now:  time.Date(2025, time.January, 2, 20, 0, 0, 0, time.UTC),
+		lines: []string{
+			"day set 2 --long 24w52-0",
+			"day set 4 -d 1",
+			"day set 5",
+			"day list",
+			"day report",
+			"--as-of 2024-12-29 day list",
+		},
+	},
+	{
+		name: "gaps_and_stats",
+		now:  time.Date(2025, time.March, 14, 20, 0, 0, 0, time.UTC),
+		lines: []string{
+			"day set 4 --long 25w10-1",
+			"day set 5 --long 25w10-2",
+			"day gaps",
+			"day gaps --fill 3",
+			"day gaps",
+			"day set 4",
+			"day stats -n 14",
+			"day stats -n 14 --exclude-late",
+			"day rm --long 25w10-2",
+		},
+	},
+	{
+		name: "overwrite",
+		now:  time.Date(2025, time.February, 19, 20, 0, 0, 0, time.UTC),
+		lines: []string{
+			"day set 4 --note 'good focus'",
+			"day set 2",
+			"--json day set 2",
+			"day set 2 --update",
+			"day get",
+			"day set 1 --force",
+			"day get",
+			"day set 3 --update --force",
+			"day set 3 --update -d 1",
+		},
+	},
+	{
+		name: "checkins",
+		now:  time.Date(2025, time.February, 19, 20, 0, 0, 0, time.UTC),
+		lines: []string{
+			"day checkin 5 --at 08:30 -n coffee",
+			"day checkin 2 --at 13:00",
+			"day checkin 4",
+			"day checkins",
+			"day set 3 -d 1",
+			"day checkin 4 -d 1 --at 18:00",
+			"--json day checkins -d 1",
+			"day set 1 --update",
+			"day report",
+			"day checkin 3 --at 25:00",
+		},
+	},
+	{
+		name: "goals",
+		now:  time.Date(2025, time.February, 19, 20, 0, 0, 0, time.UTC),
+		lines: []string{
+			"goals",
+			"goals add average 3.5 --per month",
+			"goals add max-bad 1",
+			"goals add every-day",
+			"goals add average",
+			"goals add streak 3",
+			"day set 5 --long 25w06-1",
+			"day set 1 -d 1",
+			"day set 3 -d 2",
+			"goals",
+			"day report",
+			"day set 1",
+			"goals",
+			"--json goals",
+			"goals rm 2",
+			"goals rm 2",
+			"day report",
+		},
+	},
+	{
+		name: "remind",
+		now:  time.Date(2025, time.February, 19, 20, 30, 0, 0, time.UTC),
+		lines: []string{
+			"remind check",
+			"remind snooze 30m",
+			"remind check",
+			"remind snooze soon",
+			"remind snooze -- -1h",
+			"--as-of 2025-02-23 remind check",
+			"day set 4",
+			"remind check",
+		},
+	},
+	{
+		name: "prompt",
+		now:  time.Date(2025, time.February, 19, 20, 0, 0, 0, time.UTC),
+		lines: []string{
+			"prompt",
+			"day set 3 -d 1",
+			"prompt",
+			"prompt --unrated '{{.DayID}}: rate me ({{.Average}} so far)'",
+			"day set 5",
+			"prompt",
+			"prompt --format '{{.Label}}, {{.Days}} days'",
+			"day set 2 --update",
+			"prompt",
+			"--as-of 2025-02-20 prompt",
+			"prompt --format '{{.Mood}}'",
+		},
+	},
+	{
+		name: "completion",
+		now:  time.Date(2025, time.February, 19, 20, 0, 0, 0, time.UTC),
+		lines: []string{
+			"__complete day set ''",
+			"day set 4 -d mon",
+			"day set 2 -d Tuesday",
+			"__complete day get --long ''",
+			"__complete day rm -d ''",
+			"__complete day rm -d t",
+			"day get -d 1",
+			"day get -d someday",
+			"goals add every-day",
+			"__complete goals rm ''",
+			"__complete goals add ''",
+			"__complete day list --as-of 25w08-",
+			"day set 5",
+			"__complete day get --long ''",
+		},
+	},
+	{
+		name: "errors",
+		now:  time.Date(2025, time.February, 19, 20, 0, 0, 0, time.UTC),
+		lines: []string{
+			"day set 9",
+			"day set abc",
+			"day set",
+			"day set 3 --bogus",
+			"day set 3 4",
+			"day set 3 --long 25w99-1",
+			"day set 3 -d 8",
+			"day get --long 25w08-1",
+			"--json day get --long 25w08-1",
+			"--debug day rm",
+			"--as-of yesterday day list",
+		},
+	},
+}
+
+func TestGolden(t *testing.T) {
+	for _, sc := range scripts {
+		t.Run(sc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			dataPath := filepath.Join(dir, "ratings.json")
+			var transcript bytes.Buffer
+
+			for _, line := range sc.lines {
+				// Each line gets a fresh repository, clock and command tree, as separate invocations would
+				repo, err := file.NewFileRepository(dataPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				goalRepo, err := file.NewGoalRepository(filepath.Join(dir, "goals.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				snooze, err := file.NewSnoozeStore(filepath.Join(dir, "remind.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				promptCache, err := file.NewPromptCache(filepath.Join(dir, "prompt.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var stdout, stderr bytes.Buffer
+				wall := clock.NewFixed(sc.now)
+				clk := clock.NewAsOf(wall)
+				ratings := ratingService.NewService(repo, clk, ratingService.WithWallClock(wall))
+				root := NewRootCmd(ratings, goalService.NewService(goalRepo, ratings, clk), nil, clk)
+				// The bell rings into the transcript, quiet on Sundays
+				root.AddCommand(NewRemindCmd(remindService.NewService(ratings, notify.Bell{Out: &stdout}, snooze, clk,
+					remindService.WithSchedule(remind.Schedule{Hour: 20, Quiet: []time.Weekday{time.Sunday}}))))
+				root.AddCommand(NewPromptCmd(promptService.NewService(ratings, repo.(secondary.Versioned), promptCache, clk),
+					prompt.DefaultFormat, prompt.DefaultUnrated))
+
+				root.SetArgs(splitArgs(line))
+				root.SetIn(strings.NewReader(""))
+				root.SetOut(&stdout)
+				root.SetErr(&stderr)
+				code := Execute(root)
+
+				fmt.Fprintf(&transcript, "$ track %s\n", line)
+				transcript.Write(stdout.Bytes())
+				if stderr.Len() > 0 {
+					fmt.Fprintf(&transcript, "[stderr]\n%s", stderr.String())
+				}
+				if code != ExitOK {
+					fmt.Fprintf(&transcript, "[exit %d]\n", code)
+				}
+				transcript.WriteString("\n")
+			}
+
+			data, err := os.ReadFile(dataPath)
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+
+			compareGolden(t, filepath.Join("testdata", sc.name+".golden"), transcript.Bytes())
+			compareGolden(t, filepath.Join("testdata", sc.name+".data.golden"), data)
+		})
+	}
+}
+
+func compareGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file, run go test -update to create it: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs, run go test -update if the change is intended\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+// splitArgs splits a command line on spaces, keeping single-quoted words together
+func splitArgs(line string) []string {
+	var args []string
+	for i, part := range strings.Split(line, "'") {
+		if i%2 == 1 {
+			args = append(args, part)
+			continue
+		}
+		args = append(args, strings.Fields(part)...)
+	}
+	return args
+}
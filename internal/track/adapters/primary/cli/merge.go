@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	replicaPort "track/internal/track/ports/primary/replica"
+)
+
+// NewMergeCmd is added by the composition root, which knows how the ratings are stored
+func NewMergeCmd(service replicaPort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "merge <file>...",
+		Short: "Merge other devices' ratings files into this one.",
+		Long: `Merge other devices' ratings files into this one.
+
+With rating.store = crdt in ~/.track.properties, each device keeps its own
+copy of the ratings, and any copies merge to the same result in any order:
+each day keeps its latest change, deletes included. Devices can exchange
+copies through any shared folder, e.g. with rating.file set to
+~/Sync/track/laptop.json on the laptop:
+
+  track merge ~/Sync/track/*.json
+
+Plain ratings files merge too, each day dated by its last update.`,
+		Args: UsageArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			out := cmd.OutOrStdout()
+			for _, path := range args {
+				changes, err := service.Merge(ctx, path)
+				if err != nil {
+					return err
+				}
+				if len(changes) == 0 {
+					fmt.Fprintf(out, "%s: no changes\n", path)
+					continue
+				}
+				fmt.Fprintf(out, "%s: %s changed\n", path, daysLabel(len(changes)))
+				for _, c := range changes {
+					before := "new"
+					if c.Before != nil {
+						before = conflictSide(c.Before)
+					}
+					fmt.Fprintf(out, "  %s  %s -> %s\n", c.ID, before, conflictSide(c.After))
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func daysLabel(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/adapters/primary/openmetrics"
+	"track/internal/track/domain/rating"
+	ratingPort "track/internal/track/ports/primary/rating"
+)
+
+func newMetricsCmd(service ratingPort.Service) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Print rating metrics in the Prometheus text format",
+		Long: `Print rating metrics in the Prometheus text format.
+
+With --output the metrics are written atomically to a file, ready for
+node_exporter's textfile collector, e.g. from a cron job:
+
+  track metrics -o /var/lib/node_exporter/textfile/track.prom`,
+		Args: UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			metrics, err := service.GetMetrics(ctx)
+			if err != nil {
+				return fmt.Errorf("getting metrics: %w", err)
+			}
+
+			if output == "" {
+				return openmetrics.Write(cmd.OutOrStdout(), metrics)
+			}
+
+			var b bytes.Buffer
+			if err := openmetrics.Write(&b, metrics); err != nil {
+				return err
+			}
+			return writeFileAtomic(output, b.Bytes())
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to this file instead of stdout")
+	return cmd
+}
+
+// writeFileAtomic writes through a temp file and rename so readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return rating.Errorf(rating.KindStorage, "creating temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return rating.Errorf(rating.KindStorage, "writing %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return rating.Errorf(rating.KindStorage, "writing %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return rating.Errorf(rating.KindStorage, "writing %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return rating.Errorf(rating.KindStorage, "writing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"track/internal/track/domain/rating"
+	ratingPort "track/internal/track/ports/primary/rating"
+)
+
+// stdinIsTerminal reports whether stdin is attached to a terminal, swapped out in tests
+var stdinIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// prompter asks questions on out and reads the answers line by line from in
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// ask prints the question and returns the trimmed answer
+func (p *prompter) ask(question string) (string, error) {
+	fmt.Fprint(p.out, question)
+	line, err := p.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		return "", rating.Errorf(rating.KindValidation, "no answer given: input closed")
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// askRating shows the rating scale and asks until a valid rating is entered
+func (p *prompter) askRating(previous *rating.DayRating) (rating.Rating, error) {
+	for r := rating.Awesome; r >= rating.Bad; r-- {
+		fmt.Fprintf(p.out, "  %d  %s %s\n", r, r.Emoji(), r.String())
+	}
+	if previous != nil {
+		fmt.Fprintf(p.out, "Yesterday: %d %s %s\n", previous.Rating, previous.Rating.Emoji(), previous.Rating.String())
+	}
+
+	for {
+		answer, err := p.ask(fmt.Sprintf("Rating (%d-%d): ", rating.Bad, rating.Awesome))
+		if err != nil {
+			return 0, err
+		}
+		value, err := rating.NewRating(parseInt(answer))
+		if err == nil {
+			return value, nil
+		}
+		fmt.Fprintf(p.out, "%v\n", err)
+	}
+}
+
+// confirm asks a yes/no question, defaulting to no
+func (p *prompter) confirm(question string) (bool, error) {
+	answer, err := p.ask(question + " [y/N]: ")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// runSetPrompt guides the user through rating the target day
+func runSetPrompt(ctx context.Context, cmd *cobra.Command, service ratingPort.Service, target time.Time, note string) error {
+	p := newPrompter(cmd.InOrStdin(), cmd.OutOrStdout())
+	ctx = ratingPort.WithSource(ctx, rating.SourceTUI)
+
+	var previous *rating.DayRating
+	yesterday, err := service.GetDayRating(ctx, target.AddDate(0, 0, -1))
+	switch {
+	case err == nil:
+		previous = &yesterday
+	case !errors.Is(err, rating.ErrNotFound):
+		return fmt.Errorf("getting yesterday's rating: %w", err)
+	}
+
+	existing, err := service.GetDayRating(ctx, target)
+	exists := err == nil
+	if err != nil && !errors.Is(err, rating.ErrNotFound) {
+		return fmt.Errorf("getting current rating: %w", err)
+	}
+
+	fmt.Fprintf(p.out, "How was %s (%s)?\n", rating.DayID(target), target.Format("Mon 02 Jan"))
+	value, err := p.askRating(previous)
+	if err != nil {
+		return err
+	}
+
+	if note == "" {
+		if note, err = p.ask("Note (optional): "); err != nil {
+			return err
+		}
+	}
+
+	if exists {
+		ok, err := p.confirm(fmt.Sprintf("Overwrite existing rating %s %s?", existing.Rating.String(), existing.Rating.Emoji()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(p.out, "Kept existing rating")
+			return nil
+		}
+	}
+
+	var saved rating.DayRating
+	if exists {
+		// Fails if the day changed while the prompt was open
+		saved, err = service.UpdateDayRating(ctx, target, value, note, existing.Revision)
+	} else {
+		saved, err = service.CreateDayRating(ctx, target, value, note)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "Saved %s\n", saved)
+	return nil
+}
+
+// confirmOverwrite asks before replacing the rating `day set` found on target
+func confirmOverwrite(ctx context.Context, cmd *cobra.Command, service ratingPort.Service, target time.Time, value rating.Rating, note string) error {
+	p := newPrompter(cmd.InOrStdin(), cmd.OutOrStdout())
+
+	existing, err := service.GetDayRating(ctx, target)
+	if err != nil {
+		return fmt.Errorf("getting current rating: %w", err)
+	}
+
+	ok, err := p.confirm(fmt.Sprintf("%s is already rated %s %s. Overwrite with %s %s?",
+		existing.ID, existing.Rating.String(), existing.Rating.Emoji(), value.String(), value.Emoji()))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Fprintln(p.out, "Kept existing rating")
+		return nil
+	}
+
+	if !cmd.Flags().Changed("note") {
+		note = existing.Note
+	}
+	_, err = service.UpdateDayRating(ctx, target, value, note, existing.Revision)
+	return err
+}
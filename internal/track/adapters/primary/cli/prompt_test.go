@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/spf13/cobra"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/crypt"
+	"track/internal/track/adapters/secondary/memory"
+	"track/internal/track/adapters/secondary/notify"
+	promptService "track/internal/track/application/prompt"
+	ratingService "track/internal/track/application/rating"
+	remindService "track/internal/track/application/remind"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/remind"
+)
+
+func TestSetPrompt(t *testing.T) {
+	repo := memory.NewMemoryRepository()
+	service := ratingService.NewService(repo, clock.NewFixed(time.Date(2025, time.February, 18, 20, 0, 0, 0, time.UTC)))
+	ctx := context.Background()
+	target := time.Date(2025, time.February, 18, 0, 0, 0, 0, time.UTC)
+
+	if _, err := service.CreateDayRating(ctx, target.AddDate(0, 0, -1), rating.Fair, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CreateDayRating(ctx, target, rating.Poor, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetIn(strings.NewReader("9\n4\ngood focus\ny\n"))
+	cmd.SetOut(&out)
+
+	if err := runSetPrompt(ctx, cmd, service, target, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := service.GetDayRating(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, got.Rating, rating.Good)
+	assert.Equal(t, got.Note, "good focus")
+	assert.Matches(t, out.String(), `Yesterday: 3 😐 Fair`)
+	assert.Matches(t, out.String(), `rating must be between 1 and 5, got 9`)
+	assert.Matches(t, out.String(), `Overwrite existing rating Poor`)
+}
+
+func TestSetPromptKeepsExisting(t *testing.T) {
+	repo := memory.NewMemoryRepository()
+	service := ratingService.NewService(repo, clock.NewFixed(time.Date(2025, time.February, 18, 20, 0, 0, 0, time.UTC)))
+	ctx := context.Background()
+	target := time.Date(2025, time.February, 18, 0, 0, 0, 0, time.UTC)
+
+	if _, err := service.CreateDayRating(ctx, target, rating.Poor, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetIn(strings.NewReader("5\n\nn\n"))
+	cmd.SetOut(&bytes.Buffer{})
+
+	if err := runSetPrompt(ctx, cmd, service, target, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := service.GetDayRating(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, got.Rating, rating.Poor)
+}
+
+// lockedCipher seals days but fails to open them, as if nobody were there to type the passphrase
+type lockedCipher struct {
+	opened int
+}
+
+func (c *lockedCipher) Enabled() bool { return true }
+
+func (c *lockedCipher) Seal(_ context.Context, dr rating.DayRating) (rating.DayRating, error) {
+	dr.Rating, dr.Note = 0, "sealed"
+	return dr, nil
+}
+
+func (c *lockedCipher) Open(context.Context, rating.DayRating) (rating.DayRating, error) {
+	c.opened++
+	return rating.DayRating{}, rating.Errorf(rating.KindConfig, "asked for the passphrase")
+}
+
+func (c *lockedCipher) Sealed(dr rating.DayRating) bool { return dr.Rating == 0 }
+
+func TestPromptAndRemindDontOpenEncryptedDays(t *testing.T) {
+	clk := clock.NewFixed(time.Date(2025, time.February, 22, 20, 0, 0, 0, time.UTC))
+	cipher := &lockedCipher{}
+	ratings := ratingService.NewService(crypt.NewRepository(memory.NewMemoryRepository(), cipher), clk)
+	var bell bytes.Buffer
+	reminders := remindService.NewService(ratings, notify.Bell{Out: &bell}, memory.NewSnoozeStore(), clk,
+		remindService.WithSchedule(remind.Schedule{Hour: 19}))
+	prompts := promptService.NewService(ratings, nil, nil, clk, promptService.WithCipher(cipher))
+
+	run := func(cmd *cobra.Command, args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		cmd.SetArgs(args)
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+	const format = "{{.DayID}} rated"
+
+	assert.Equal(t, run(NewPromptCmd(prompts, format, "unrated")), "unrated\n")
+	assert.Equal(t, run(NewRemindCmd(reminders), "check"), "Today is not rated, sent a reminder\n")
+
+	if _, err := ratings.CreateDayRating(context.Background(), clk.Now(), rating.Good, ""); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, run(NewPromptCmd(prompts, format, "unrated")), "25w08-6 rated\n")
+	assert.Equal(t, run(NewRemindCmd(reminders), "check"), "No reminder: already rated\n")
+	assert.Equal(t, cipher.opened, 0)
+}
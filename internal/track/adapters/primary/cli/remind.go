@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/remind"
+	remindPort "track/internal/track/ports/primary/remind"
+)
+
+// NewRemindCmd is added by the composition root, which knows how to notify and schedule
+func NewRemindCmd(service remindPort.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remind",
+		Short: "Get reminded to rate the day.",
+		Long: `Get reminded to rate the day.
+
+remind install sets up a systemd user timer, or a crontab entry with --cron,
+that runs remind check every 15 minutes. The check notifies when today is
+not rated by remind.at, except on remind.quiet days or while snoozed. Set
+these and remind.notifier (notify-send, bell or command with remind.command)
+in ~/.track.properties.`,
+	}
+
+	cmd.AddCommand(
+		newRemindCheckCmd(service),
+		newRemindSnoozeCmd(service),
+		newRemindInstallCmd(service),
+		newRemindUninstallCmd(service),
+	)
+	return cmd
+}
+
+func newRemindCheckCmd(service remindPort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Notify if today is not rated yet, run by the scheduler.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			outcome, err := service.Check(ctx)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			switch outcome {
+			case remind.OutcomeEarly:
+				schedule := service.Schedule()
+				fmt.Fprintf(out, "Not due yet, reminding from %02d:%02d\n", schedule.Hour, schedule.Minute)
+			case remind.OutcomeNotified:
+				fmt.Fprintln(out, "Today is not rated, sent a reminder")
+			default:
+				fmt.Fprintf(out, "No reminder: %s\n", outcome)
+			}
+			return nil
+		},
+	}
+}
+
+func newRemindSnoozeCmd(service remindPort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:               "snooze [duration]",
+		Short:             "Hold back reminders for a while, an hour by default.",
+		Args:              UsageArgs(cobra.MaximumNArgs(1)),
+		ValidArgsFunction: completeFixed("30m", "1h", "2h", "4h"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			d := time.Hour
+			if len(args) == 1 {
+				var err error
+				if d, err = time.ParseDuration(args[0]); err != nil {
+					return rating.Errorf(rating.KindValidation, "invalid duration %q, expected e.g. 30m or 2h", args[0])
+				}
+			}
+
+			until, err := service.Snooze(ctx, d)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Reminders snoozed until %s\n", until.Format("Mon 15:04"))
+			return nil
+		},
+	}
+}
+
+func newRemindInstallCmd(service remindPort.Service) *cobra.Command {
+	var cron bool
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Run the reminder check every 15 minutes with a systemd user timer or cron.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			done, err := service.Install(ctx, schedulerKind(cron))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Reminders %s: %s\n", service.Schedule(), done)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&cron, "cron", false, "Use the crontab instead of a systemd user timer")
+	return cmd
+}
+
+func newRemindUninstallCmd(service remindPort.Service) *cobra.Command {
+	var cron bool
+
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Stop the periodic reminder check.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			done, err := service.Uninstall(ctx, schedulerKind(cron))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Reminders off: %s\n", done)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&cron, "cron", false, "Remove the crontab entry instead of the systemd user timer")
+	return cmd
+}
+
+func schedulerKind(cron bool) remind.SchedulerKind {
+	if cron {
+		return remind.SchedulerCron
+	}
+	return remind.SchedulerSystemd
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	promptPort "track/internal/track/ports/primary/prompt"
+)
+
+// NewPromptCmd is added by the composition root, with the configured formats as flag defaults
+func NewPromptCmd(service promptPort.Service, format, unrated string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Print a short status for shell prompts and status lines.",
+		Long: `Print a short status for shell prompts and status lines, like 🤩 4.2↑ or ⚠ unrated.
+
+The output is cached until the ratings change, so it is cheap to run on
+every prompt. Formats are Go templates over these fields:
+
+  .Rated .DayID .Rating .Label .Emoji .Average .Days .Trend
+
+--unrated is used until today is rated. Set prompt.format and
+prompt.unrated in ~/.track.properties to change the defaults.`,
+		Args: UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			segment, err := service.Segment(ctx, format, unrated)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), segment)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", format, "Template for a rated day")
+	cmd.Flags().StringVar(&unrated, "unrated", unrated, "Template while today is not rated")
+	return cmd
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/domain/rating"
+	storagePort "track/internal/track/ports/primary/storage"
+)
+
+// NewStorageCmd is added by the composition root, which knows where the ratings and their key are
+func NewStorageCmd(service storagePort.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Manage how the ratings are stored.",
+	}
+
+	cmd.AddCommand(
+		newStorageEncryptCmd(service),
+		newStorageDecryptCmd(service),
+		newStorageRekeyCmd(service),
+		newStorageFsckCmd(service),
+	)
+	return cmd
+}
+
+func newStorageEncryptCmd(service storagePort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt the ratings with a passphrase.",
+		Long: `Encrypt the ratings with a passphrase.
+
+Each day's rating, note and check-ins are encrypted with AES-256-GCM under a
+random key, kept in storage.key-file (the ratings file with .key added)
+encrypted with a key derived from the passphrase by Argon2id. Dates and
+revisions stay readable, so sync and merge keep working.
+
+The passphrase comes from $TRACK_PASSPHRASE, storage.passphrase-command in
+~/.track.properties, or is asked for on the terminal. Keep a copy of the key
+file: without it the ratings can't be read.
+
+Snapshots taken before stay unencrypted, encrypt lists them to delete.`,
+		Args: UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			n, err := service.Encrypt(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Encrypted %s\n", daysLabel(n))
+
+			plain, err := service.PlainSnapshots(ctx)
+			if err != nil {
+				return err
+			}
+			if len(plain) > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%s in backup.dir still hold the ratings unencrypted, the oldest %s: take an encrypted one with track backup create, then delete them\n",
+					amount(float64(len(plain)), "snapshots"), plain[len(plain)-1].ID)
+			}
+			return nil
+		},
+	}
+}
+
+func newStorageDecryptCmd(service storagePort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "decrypt",
+		Short: "Store the ratings unencrypted again and remove the key.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			n, err := service.Decrypt(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Decrypted %s\n", daysLabel(n))
+			return nil
+		},
+	}
+}
+
+func newStorageRekeyCmd(service storagePort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rekey",
+		Short: "Change the passphrase, from $TRACK_NEW_PASSPHRASE or the terminal.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			if err := service.Rekey(ctx); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Passphrase changed")
+			return nil
+		},
+	}
+}
+
+func newStorageFsckCmd(service storagePort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "fsck",
+		Short: "Check every stored day for damage and inconsistencies.",
+		Long: `Check every stored day for damage and inconsistencies.
+
+Reports days stored under another day's ID or with an ID that doesn't match
+their date, invalid ratings and check-ins, several days on the same date, and
+encrypted days that fail to decrypt. Exits with status 6 when any are found.`,
+		Args: UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			problems, err := service.Fsck(ctx)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(problems) == 0 {
+				fmt.Fprintln(out, "No problems found")
+				return nil
+			}
+			fmt.Fprintf(out, "Found %s:\n", problemsLabel(len(problems)))
+			printProblems(out, problems)
+			return rating.Errorf(rating.KindStorage, "the ratings have %s", problemsLabel(len(problems)))
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/domain/rating"
+	syncPort "track/internal/track/ports/primary/sync"
+)
+
+// NewSyncCmd is added by the composition root, which knows the data directory and remote
+func NewSyncCmd(service syncPort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync",
+		Short: "Pull and push the ratings through a git remote.",
+		Long: `Pull and push the ratings through a git remote.
+
+The directory of rating.file becomes a git repository on the first sync, and
+every change is committed from then on. Set sync.remote (and sync.branch,
+main by default) in ~/.track.properties; a bare repository on disk will do.
+
+When both sides changed, the ratings are merged day by day. A day changed on
+both sides keeps a change over a delete, combines check-ins, and otherwise
+takes the most recent update.`,
+		Args: UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+
+			report, err := service.Sync(ctx)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			switch {
+			case report.Merged:
+				fmt.Fprintf(out, "Merged remote changes, %s\n", conflictsLabel(len(report.Conflicts)))
+				printConflicts(out, report.Conflicts)
+			case report.Pulled:
+				fmt.Fprintln(out, "Pulled remote changes")
+			case !report.Pushed:
+				fmt.Fprintln(out, "Already up to date")
+			}
+			if report.Pushed {
+				fmt.Fprintln(out, "Pushed local changes")
+			}
+			return nil
+		},
+	}
+}
+
+func printConflicts(out io.Writer, conflicts []rating.MergeConflict) {
+	for _, c := range conflicts {
+		fmt.Fprintf(out, "  %s  ours %s, theirs %s -> %s (%s)\n",
+			c.ID, conflictSide(c.Ours), conflictSide(c.Theirs), conflictSide(c.Result), c.Rule)
+	}
+}
+
+func conflictSide(dr *rating.DayRating) string {
+	if dr == nil {
+		return "deleted"
+	}
+	return dr.Rating.String() + " " + dr.Rating.Emoji()
+}
+
+func conflictsLabel(n int) string {
+	switch n {
+	case 0:
+		return "no conflicts"
+	case 1:
+		return "1 conflict resolved:"
+	}
+	return fmt.Sprintf("%d conflicts resolved:", n)
+}
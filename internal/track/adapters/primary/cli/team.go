@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/team"
+	teamPort "track/internal/track/ports/primary/team"
+)
+
+// NewTeamCmd is added by the composition root, which knows the team directory and member name
+func NewTeamCmd(service teamPort.Service, clk Clock) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "team",
+		Short: "Share ratings with a team and report on everyone's, anonymised.",
+		Long: `Share ratings with a team and report on everyone's, anonymised.
+
+Each member exports their ratings, without notes or check-ins, to team.dir in
+~/.track.properties, a directory the team shares, as team.member (the login
+name by default). The report only shows the average and distribution of a day
+or week that at least team.min-group members (3) rated, so no one's rating can
+be told from a small group. Distributions count each member once, by their
+average rounded halves up.`,
+	}
+
+	cmd.AddCommand(
+		newTeamExportCmd(service),
+		newTeamReportCmd(service, clk),
+	)
+	return cmd
+}
+
+func newTeamExportCmd(service teamPort.Service) *cobra.Command {
+	return &cobra.Command{
+		Use:   "export",
+		Short: "Export your ratings to the team directory.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			n, err := service.Export(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %s\n", daysLabel(n))
+			return nil
+		},
+	}
+}
+
+func newTeamReportCmd(service teamPort.Service, clk Clock) *cobra.Command {
+	var weeks int
+	var days bool
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Show the team's average, distribution, participation and trend by week.",
+		Args:  UsageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if weeks < 1 {
+				return &usageError{path: cmd.CommandPath(), err: fmt.Errorf("--weeks must be at least 1, got %d", weeks)}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			end := clk.Now()
+			report, err := service.Report(ctx, end.AddDate(0, 0, -7*(weeks-1)), end)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOutput(cmd) {
+				return writeJSON(out, toTeamReportJSON(report, days))
+			}
+
+			first, last := report.Weeks[0].Label, report.Weeks[len(report.Weeks)-1].Label
+			fmt.Fprintf(out, "Team report %s to %s, %d members, groups under %d hidden\n", first, last, report.Members, report.MinGroup)
+			fmt.Fprintf(out, "─────────────────────\n")
+			printTeamGroups(out, report.Weeks)
+			if days {
+				fmt.Fprintln(out)
+				printTeamGroups(out, report.Days)
+			}
+
+			fmt.Fprintln(out)
+			if report.TrendWeeks < 2 {
+				fmt.Fprintln(out, "Trend:   not enough weeks shown")
+			} else {
+				fmt.Fprintf(out, "Trend:   %s %+.2f a week over %d weeks\n", trendArrow(report.Trend), report.Trend, report.TrendWeeks)
+			}
+			fmt.Fprintf(out, "Overall: %s\n", teamGroupLine(report.Overall))
+			return nil
+		},
+	}
+	cmd.Flags().IntVarP(&weeks, "weeks", "w", 4, "Number of weeks, up to the current one")
+	cmd.Flags().BoolVar(&days, "days", false, "Show each day too")
+	return cmd
+}
+
+func printTeamGroups(out io.Writer, groups []team.Group) {
+	for _, g := range groups {
+		fmt.Fprintf(out, "%-8s %s\n", g.Label, teamGroupLine(g))
+	}
+}
+
+// teamGroupLine shows a group's average, participation and distribution, or why it has none
+func teamGroupLine(g team.Group) string {
+	if g.Suppressed {
+		return "-     hidden, too few to show"
+	}
+	participation := fmt.Sprintf("%3.0f%% (%d/%d)", 100*g.Participation(), g.Respondents, g.Members)
+	if g.Respondents == 0 {
+		return fmt.Sprintf("-     %s", participation)
+	}
+	var dist []string
+	for r := rating.Bad; r <= rating.Awesome; r++ {
+		dist = append(dist, fmt.Sprintf("%s %d", r.Emoji(), g.Distribution[r]))
+	}
+	return fmt.Sprintf("%.1f   %s  %s", g.Average, participation, strings.Join(dist, "  "))
+}
+
+func trendArrow(slope float64) string {
+	switch {
+	case slope >= 0.05:
+		return "↑"
+	case slope <= -0.05:
+		return "↓"
+	}
+	return "→"
+}
+
+type teamGroupJSON struct {
+	Label         string         `json:"label"`
+	Respondents   *int           `json:"respondents,omitempty"`
+	Members       int            `json:"members"`
+	Participation *float64       `json:"participation,omitempty"`
+	Suppressed    bool           `json:"suppressed,omitempty"`
+	Average       *float64       `json:"average,omitempty"`
+	Distribution  map[string]int `json:"distribution,omitempty"`
+}
+
+type teamReportJSON struct {
+	Members    int             `json:"members"`
+	MinGroup   int             `json:"minGroup"`
+	Weeks      []teamGroupJSON `json:"weeks"`
+	Days       []teamGroupJSON `json:"days,omitempty"`
+	Overall    teamGroupJSON   `json:"overall"`
+	Trend      *float64        `json:"trend,omitempty"`
+	TrendWeeks int             `json:"trendWeeks"`
+}
+
+func toTeamReportJSON(report team.Report, days bool) teamReportJSON {
+	groups := func(gs []team.Group) []teamGroupJSON {
+		out := make([]teamGroupJSON, 0, len(gs))
+		for _, g := range gs {
+			out = append(out, toTeamGroupJSON(g))
+		}
+		return out
+	}
+	r := teamReportJSON{
+		Members:    report.Members,
+		MinGroup:   report.MinGroup,
+		Weeks:      groups(report.Weeks),
+		Overall:    toTeamGroupJSON(report.Overall),
+		TrendWeeks: report.TrendWeeks,
+	}
+	if days {
+		r.Days = groups(report.Days)
+	}
+	if report.TrendWeeks >= 2 {
+		r.Trend = &report.Trend
+	}
+	return r
+}
+
+func toTeamGroupJSON(g team.Group) teamGroupJSON {
+	j := teamGroupJSON{
+		Label:      g.Label,
+		Members:    g.Members,
+		Suppressed: g.Suppressed,
+	}
+	if !g.Suppressed {
+		participation := g.Participation()
+		j.Respondents, j.Participation = &g.Respondents, &participation
+	}
+	if g.Distribution != nil {
+		j.Average = &g.Average
+		j.Distribution = make(map[string]int, len(g.Distribution))
+		for r := rating.Bad; r <= rating.Awesome; r++ {
+			j.Distribution[fmt.Sprint(int(r))] = g.Distribution[r]
+		}
+	}
+	return j
+}
@@ -0,0 +1,286 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"track/internal/track/domain/rating"
+)
+
+//go:embed web
+var webFS embed.FS
+
+var pages = template.Must(template.ParseFS(webFS, "web/*.html"))
+
+const (
+	sessionCookie = "track_session"
+	sessionLength = 90 * 24 * time.Hour
+	trendWeeks    = 13
+	heatmapWeeks  = 53
+	cellSize      = 12
+)
+
+type heatmapCell struct {
+	X, Y  int
+	Class string
+	Title string
+}
+
+type trendPoint struct {
+	X, Y  float64
+	Label string
+	Title string
+}
+
+type weekdayBar struct {
+	Day     string
+	Width   float64
+	Average float64
+	Count   int
+}
+
+type dashboardView struct {
+	Today        string
+	TodayRating  *rating.DayRating
+	Scale        []rating.Rating
+	Heatmap      []heatmapCell
+	HeatmapWidth int
+	Trend        []trendPoint
+	TrendLine    string
+	Weekdays     []weekdayBar
+}
+
+type loginView struct {
+	Failed bool
+}
+
+func (s *Server) dashboardRoutes() {
+	static, _ := fs.Sub(webFS, "web")
+	s.mux.Handle("GET /static/", nethttp.StripPrefix("/static/", nethttp.FileServerFS(static)))
+	s.mux.HandleFunc("GET /login", s.handleLoginForm)
+	s.mux.HandleFunc("POST /login", s.handleLogin)
+	s.mux.Handle("GET /{$}", s.authorizePage(s.handleDashboard))
+	s.mux.Handle("POST /rate", s.authorizePage(s.handleRate))
+}
+
+// authorizePage sends browsers without a session cookie to the login form
+func (s *Server) authorizePage(next nethttp.HandlerFunc) nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		c, err := r.Cookie(sessionCookie)
+		if err != nil || !s.validSession(c.Value, time.Now()) {
+			nethttp.Redirect(w, r, "/login", nethttp.StatusSeeOther)
+			return
+		}
+		next(w, r)
+	})
+}
+
+func (s *Server) handleLoginForm(w nethttp.ResponseWriter, r *nethttp.Request) {
+	render(w, nethttp.StatusOK, "login.html", loginView{})
+}
+
+func (s *Server) handleLogin(w nethttp.ResponseWriter, r *nethttp.Request) {
+	token := r.PostFormValue("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		render(w, nethttp.StatusUnauthorized, "login.html", loginView{Failed: true})
+		return
+	}
+	session, err := s.newSession(time.Now().Add(sessionLength))
+	if err != nil {
+		nethttp.Error(w, err.Error(), nethttp.StatusInternalServerError)
+		return
+	}
+	nethttp.SetCookie(w, &nethttp.Cookie{
+		Name:     sessionCookie,
+		Value:    session,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: nethttp.SameSiteStrictMode,
+		MaxAge:   int(sessionLength.Seconds()),
+	})
+	nethttp.Redirect(w, r, "/", nethttp.StatusSeeOther)
+}
+
+// newSession returns a session lasting until expires, signed with the API token so the
+// server keeps no state and the cookie never carries the token itself
+func (s *Server) newSession(expires time.Time) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("creating a session: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(nonce) + "." + strconv.FormatInt(expires.Unix(), 10)
+	return payload + "." + s.signSession(payload), nil
+}
+
+func (s *Server) validSession(session string, now time.Time) bool {
+	i := strings.LastIndex(session, ".")
+	if i < 0 {
+		return false
+	}
+	payload, signature := session[:i], session[i+1:]
+	if subtle.ConstantTimeCompare([]byte(signature), []byte(s.signSession(payload))) != 1 {
+		return false
+	}
+	_, expires, _ := strings.Cut(payload, ".")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	return err == nil && now.Before(time.Unix(unix, 0))
+}
+
+func (s *Server) signSession(payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.token))
+	mac.Write([]byte("track session " + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Server) handleRate(w nethttp.ResponseWriter, r *nethttp.Request) {
+	value, err := rating.NewRating(atoi(r.PostFormValue("rating")))
+	if err != nil {
+		nethttp.Error(w, err.Error(), nethttp.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	// The form carries the revision of the rating it showed, so a rating made
+	// elsewhere since the page loaded is not silently replaced
+	note := strings.TrimSpace(r.PostFormValue("note"))
+	if revision := atoi(r.PostFormValue("revision")); revision > 0 {
+		_, err = s.service.UpdateDayRating(ctx, s.clock.Now(), value, note, revision)
+	} else {
+		_, err = s.service.CreateDayRating(ctx, s.clock.Now(), value, note)
+	}
+	switch {
+	case errors.Is(err, rating.ErrHasCheckIns):
+		nethttp.Error(w, "Today is rated from check-ins, add a check-in to change it.", nethttp.StatusConflict)
+		return
+	case errors.Is(err, rating.ErrConflict):
+		nethttp.Error(w, "Today's rating changed since this page was loaded, reload and try again.", serviceStatus(err))
+		return
+	case err != nil:
+		nethttp.Error(w, err.Error(), serviceStatus(err))
+		return
+	}
+	nethttp.Redirect(w, r, "/", nethttp.StatusSeeOther)
+}
+
+func (s *Server) handleDashboard(w nethttp.ResponseWriter, r *nethttp.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	view, err := s.buildDashboard(ctx, s.clock.Now())
+	if err != nil {
+		nethttp.Error(w, err.Error(), nethttp.StatusInternalServerError)
+		return
+	}
+	render(w, nethttp.StatusOK, "dashboard.html", view)
+}
+
+func (s *Server) buildDashboard(ctx context.Context, now time.Time) (dashboardView, error) {
+	view := dashboardView{
+		Today: now.Format("Monday 02 January"),
+		Scale: []rating.Rating{rating.Bad, rating.Poor, rating.Fair, rating.Good, rating.Awesome},
+	}
+
+	today, err := s.service.GetDayRating(ctx, now)
+	switch {
+	case err == nil:
+		view.TodayRating = &today
+	case !errors.Is(err, rating.ErrNotFound):
+		return view, err
+	}
+
+	// The heatmap starts on the Monday heatmapWeeks-1 weeks before this one
+	start := rating.DayStart(now)
+	start = start.AddDate(0, 0, -((int(start.Weekday())+6)%7)-7*(heatmapWeeks-1))
+	ratings, err := s.service.GetDateRangeRatings(ctx, start, rating.DayEnd(now))
+	if err != nil {
+		return view, err
+	}
+	byDay := make(map[string]rating.DayRating, len(ratings))
+	for _, dr := range ratings {
+		byDay[dr.Date.Format(DateFormat)] = dr
+	}
+	for day, i := start, 0; !day.After(now); day, i = day.AddDate(0, 0, 1), i+1 {
+		cell := heatmapCell{
+			X:     (i / 7) * (cellSize + 2),
+			Y:     (i % 7) * (cellSize + 2),
+			Class: "r0",
+			Title: day.Format(DateFormat) + ": unrated",
+		}
+		if dr, ok := byDay[day.Format(DateFormat)]; ok {
+			cell.Class = fmt.Sprintf("r%d", dr.Rating)
+			cell.Title = fmt.Sprintf("%s: %s %s", day.Format(DateFormat), dr.Rating.String(), dr.Rating.Emoji())
+		}
+		view.Heatmap = append(view.Heatmap, cell)
+	}
+	view.HeatmapWidth = heatmapWeeks * (cellSize + 2)
+
+	trend, err := s.service.GetWeeklyTrend(ctx, now, trendWeeks)
+	if err != nil {
+		return view, err
+	}
+	var line []string
+	for i, wt := range trend {
+		p := trendPoint{
+			X:     20 + float64(i)*40,
+			Label: fmt.Sprintf("w%02d", wt.Week),
+			Title: fmt.Sprintf("Week %02d: no data", wt.Week),
+		}
+		if wt.Count > 0 {
+			// Ratings 1-5 map onto y 130-10
+			p.Y = 130 - (wt.Average-1)*30
+			p.Title = fmt.Sprintf("Week %02d: %.1f (%d days)", wt.Week, wt.Average, wt.Count)
+			line = append(line, fmt.Sprintf("%.0f,%.0f", p.X, p.Y))
+		}
+		view.Trend = append(view.Trend, p)
+	}
+	view.TrendLine = strings.Join(line, " ")
+
+	weekdays, err := s.service.GetWeekdayBreakdown(ctx, start, now)
+	if err != nil {
+		return view, err
+	}
+	for _, ws := range weekdays {
+		view.Weekdays = append(view.Weekdays, weekdayBar{
+			Day:     ws.Weekday.String()[:3],
+			Width:   ws.Average / float64(rating.Awesome) * 100,
+			Average: ws.Average,
+			Count:   ws.Count,
+		})
+	}
+
+	return view, nil
+}
+
+func render(w nethttp.ResponseWriter, status int, name string, data any) {
+	var b strings.Builder
+	if err := pages.ExecuteTemplate(&b, name, data); err != nil {
+		nethttp.Error(w, err.Error(), nethttp.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write([]byte(b.String()))
+}
+
+func atoi(s string) int {
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"context"
+	"io"
+	nethttp "net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/memory"
+	ratingService "track/internal/track/application/rating"
+	"track/internal/track/domain/rating"
+)
+
+func TestDashboardRequiresLogin(t *testing.T) {
+	ts := newTestServer(t)
+	client := ts.Client()
+	client.CheckRedirect = func(*nethttp.Request, []*nethttp.Request) error { return nethttp.ErrUseLastResponse }
+
+	resp, err := client.Get(ts.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, nethttp.StatusSeeOther)
+	assert.Equal(t, resp.Header.Get("Location"), "/login")
+
+	resp, err = client.PostForm(ts.URL+"/login", url.Values{"token": {"wrong"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, nethttp.StatusUnauthorized)
+
+	// Neither the token itself nor a session with its signature changed opens the dashboard
+	for _, value := range []string{testToken, "bm9uY2U.9999999999.c2lnbmF0dXJl"} {
+		req, _ := nethttp.NewRequest(nethttp.MethodGet, ts.URL+"/", nil)
+		req.AddCookie(&nethttp.Cookie{Name: sessionCookie, Value: value})
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		assert.Equal(t, resp.StatusCode, nethttp.StatusSeeOther)
+	}
+}
+
+func TestLoginSession(t *testing.T) {
+	ts := httptest.NewTLSServer(newTestServer(t).Config.Handler)
+	t.Cleanup(ts.Close)
+	client := ts.Client()
+	client.CheckRedirect = func(*nethttp.Request, []*nethttp.Request) error { return nethttp.ErrUseLastResponse }
+
+	resp, err := client.PostForm(ts.URL+"/login", url.Values{"token": {testToken}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	cookies := resp.Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("login set %d cookies, want the session", len(cookies))
+	}
+	session := cookies[0]
+	if strings.Contains(session.Value, testToken) || !session.Secure || !session.HttpOnly {
+		t.Errorf("session cookie = %+v, want a secure cookie without the token", session)
+	}
+
+	req, _ := nethttp.NewRequest(nethttp.MethodGet, ts.URL+"/", nil)
+	req.AddCookie(&nethttp.Cookie{Name: sessionCookie, Value: session.Value})
+	if resp, err = client.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, nethttp.StatusOK)
+}
+
+func TestDashboardRateAndRender(t *testing.T) {
+	ts := newTestServer(t)
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := ts.Client()
+	client.Jar = jar
+
+	resp, err := client.PostForm(ts.URL+"/login", url.Values{"token": {testToken}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, nethttp.StatusOK)
+
+	resp, err = client.PostForm(ts.URL+"/rate", url.Values{"rating": {"4"}, "note": {"from the browser"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, nethttp.StatusOK)
+
+	page := string(body)
+	assert.Matches(t, page, `Rated 😊 Good &middot; from the browser`)
+	assert.Equal(t, strings.Count(page, `class="r4"`), 1)
+	assert.Matches(t, page, `<polyline points="500,40"/>`)
+	assert.Equal(t, strings.Count(page, "<tr>"), 7)
+	assert.Matches(t, page, `name="revision" value="1"`)
+
+	// A page loaded before that rating still posts revision 0 and must not overwrite it
+	resp, err = client.PostForm(ts.URL+"/rate", url.Values{"rating": {"1"}, "revision": {"0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, nethttp.StatusConflict)
+
+	resp, err = client.Get(ts.URL + "/static/style.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, nethttp.StatusOK)
+}
+
+func TestDashboardRateErrors(t *testing.T) {
+	clk := clock.NewFixed(testNow)
+	service := ratingService.NewService(memory.NewMemoryRepository(), clk)
+	ts := serve(t, service, clk)
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := ts.Client()
+	client.Jar = jar
+	resp, err := client.PostForm(ts.URL+"/login", url.Values{"token": {testToken}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	rate := func(revision string) (int, string) {
+		t.Helper()
+		resp, err := client.PostForm(ts.URL+"/rate", url.Values{"rating": {"4"}, "revision": {revision}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	// A page showing a rating that has since been deleted
+	status, _ := rate("1")
+	assert.Equal(t, status, nethttp.StatusNotFound)
+
+	if _, err := service.AddCheckIn(context.Background(), testNow, rating.Fair, ""); err != nil {
+		t.Fatal(err)
+	}
+	status, body := rate("1")
+	assert.Equal(t, status, nethttp.StatusConflict)
+	assert.Matches(t, body, "rated from check-ins")
+}
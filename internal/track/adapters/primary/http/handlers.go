@@ -0,0 +1,393 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"strconv"
+	"time"
+
+	"track/internal/track/adapters/primary/jsonview"
+	"track/internal/track/adapters/primary/openmetrics"
+	"track/internal/track/domain/rating"
+)
+
+// DateFormat is the layout used for dates in paths, queries and bodies
+const DateFormat = "2006-01-02"
+
+const (
+	maxBodyBytes   = 1 << 20
+	requestTimeout = 5 * time.Second
+)
+
+type createDayRequest struct {
+	Date   string `json:"date"`
+	Rating int    `json:"rating"`
+	Note   string `json:"note"`
+}
+
+type updateDayRequest struct {
+	Rating   int    `json:"rating"`
+	Note     string `json:"note"`
+	Revision int    `json:"revision"`
+}
+
+type weekSummaryJSON struct {
+	Year     int                 `json:"year"`
+	Week     int                 `json:"week"`
+	Average  float64             `json:"average"`
+	DayCount int                 `json:"dayCount"`
+	Best     *jsonview.DayRating `json:"best,omitempty"`
+	Worst    *jsonview.DayRating `json:"worst,omitempty"`
+}
+
+type statsJSON struct {
+	From          string         `json:"from"`
+	To            string         `json:"to"`
+	Count         int            `json:"count"`
+	Average       float64        `json:"average"`
+	Distribution  map[string]int `json:"distribution"`
+	CurrentStreak int            `json:"currentStreak"`
+	LongestStreak int            `json:"longestStreak"`
+	Late          int            `json:"late"`
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+func toWeekSummaryJSON(summary rating.WeekSummary) weekSummaryJSON {
+	out := weekSummaryJSON{
+		Year:     summary.Year,
+		Week:     summary.Week,
+		Average:  summary.Average,
+		DayCount: summary.DayCount,
+	}
+	if summary.DayCount > 0 {
+		best, worst := jsonview.NewDayRating(summary.Best), jsonview.NewDayRating(summary.Worst)
+		out.Best, out.Worst = &best, &worst
+	}
+	return out
+}
+
+func toStatsJSON(stats rating.Stats) statsJSON {
+	out := statsJSON{
+		From:          stats.Start.Format(DateFormat),
+		To:            stats.End.Format(DateFormat),
+		Count:         stats.Count,
+		Average:       stats.Average,
+		Distribution:  make(map[string]int),
+		CurrentStreak: stats.CurrentStreak,
+		LongestStreak: stats.LongestStreak,
+		Late:          stats.Late,
+	}
+	for r := rating.Bad; r <= rating.Awesome; r++ {
+		out.Distribution[strconv.Itoa(int(r))] = stats.Distribution[r]
+	}
+	return out
+}
+
+func (s *Server) handleHealth(w nethttp.ResponseWriter, _ *nethttp.Request) {
+	writeJSON(w, nethttp.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleCreateDay(w nethttp.ResponseWriter, r *nethttp.Request) {
+	var req createDayRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+		return
+	}
+
+	date := s.clock.Now()
+	if req.Date != "" {
+		var err error
+		if date, err = parseDate(req.Date); err != nil {
+			writeError(w, nethttp.StatusBadRequest, err)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	dr, err := s.service.CreateDayRating(ctx, date, rating.Rating(req.Rating), req.Note)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, dr)
+	writeJSON(w, nethttp.StatusCreated, jsonview.NewDayRating(dr))
+}
+
+func (s *Server) handleGetDay(w nethttp.ResponseWriter, r *nethttp.Request) {
+	date, err := parseDate(r.PathValue("date"))
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	dr, err := s.service.GetDayRating(ctx, date)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, dr)
+	writeJSON(w, nethttp.StatusOK, jsonview.NewDayRating(dr))
+}
+
+func (s *Server) handleUpdateDay(w nethttp.ResponseWriter, r *nethttp.Request) {
+	date, err := parseDate(r.PathValue("date"))
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+		return
+	}
+	var req updateDayRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+		return
+	}
+	revision, err := parseIfMatch(r, req.Revision)
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	dr, err := s.service.UpdateDayRating(ctx, date, rating.Rating(req.Rating), req.Note, revision)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	setETag(w, dr)
+	writeJSON(w, nethttp.StatusOK, jsonview.NewDayRating(dr))
+}
+
+func (s *Server) handleDeleteDay(w nethttp.ResponseWriter, r *nethttp.Request) {
+	date, err := parseDate(r.PathValue("date"))
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	if err := s.service.DeleteDayRating(ctx, date); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(nethttp.StatusNoContent)
+}
+
+func (s *Server) handleListRange(w nethttp.ResponseWriter, r *nethttp.Request) {
+	from, to, err := parseRange(r, s.clock.Now(), 7)
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	ratings, err := s.service.GetDateRangeRatings(ctx, rating.DayStart(from), rating.DayEnd(to))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, nethttp.StatusOK, jsonview.NewDayRatings(ratings))
+}
+
+func (s *Server) handleListWeek(w nethttp.ResponseWriter, r *nethttp.Request) {
+	year, week, err := parseWeek(r)
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	ratings, err := s.service.GetWeekRatings(ctx, year, week)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, nethttp.StatusOK, jsonview.NewDayRatings(ratings))
+}
+
+func (s *Server) handleWeekSummary(w nethttp.ResponseWriter, r *nethttp.Request) {
+	year, week, err := parseWeek(r)
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	summary, err := s.service.GetWeekSummary(ctx, year, week)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, nethttp.StatusOK, toWeekSummaryJSON(summary))
+}
+
+func (s *Server) handleStats(w nethttp.ResponseWriter, r *nethttp.Request) {
+	from, to, err := parseRange(r, s.clock.Now(), 30)
+	if err != nil {
+		writeError(w, nethttp.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	opts := rating.StatsOptions{ExcludeLate: r.URL.Query().Get("excludeLate") == "true"}
+	stats, err := s.service.GetStats(ctx, from, to, opts)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, nethttp.StatusOK, toStatsJSON(stats))
+}
+
+func (s *Server) handleMetrics(w nethttp.ResponseWriter, r *nethttp.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	metrics, err := s.service.GetMetrics(ctx)
+	if err != nil {
+		nethttp.Error(w, err.Error(), nethttp.StatusInternalServerError)
+		return
+	}
+	// Rendered first, so a failure is still reported as an error rather than a cut-off body
+	var b bytes.Buffer
+	if err := openmetrics.Write(&b, metrics); err != nil {
+		nethttp.Error(w, err.Error(), nethttp.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", openmetrics.ContentType)
+	w.Write(b.Bytes())
+}
+
+func (s *Server) handleSchema(w nethttp.ResponseWriter, r *nethttp.Request) {
+	schema, ok := schemas[r.PathValue("name")]
+	if !ok {
+		writeError(w, nethttp.StatusNotFound, fmt.Errorf("unknown schema %q", r.PathValue("name")))
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write([]byte(schema))
+}
+
+// parseDate reads a day as local midnight, the day ratings are stamped in
+func parseDate(value string) (time.Time, error) {
+	date, err := time.ParseInLocation(DateFormat, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: expected YYYY-MM-DD, got %q", rating.ErrInvalidDate, value)
+	}
+	return date, nil
+}
+
+// parseRange reads the from/to query, defaulting to the last days up to now
+func parseRange(r *nethttp.Request, now time.Time, days int) (time.Time, time.Time, error) {
+	to := now
+	if v := r.URL.Query().Get("to"); v != "" {
+		var err error
+		if to, err = parseDate(v); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	from := to.AddDate(0, 0, -(days - 1))
+	if v := r.URL.Query().Get("from"); v != "" {
+		var err error
+		if from, err = parseDate(v); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to is before from", rating.ErrInvalidDate)
+	}
+	return from, to, nil
+}
+
+// setETag exposes the rating's revision for conditional updates with If-Match
+func setETag(w nethttp.ResponseWriter, dr rating.DayRating) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(dr.Revision)))
+}
+
+// parseIfMatch returns the revision a PUT expects to replace, from If-Match or the
+// body's revision. 0 means the client did not ask for a check.
+func parseIfMatch(r *nethttp.Request, bodyRevision int) (int, error) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return bodyRevision, nil
+	}
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		unquoted = header
+	}
+	revision, err := strconv.Atoi(unquoted)
+	if err != nil || revision < 1 {
+		return 0, fmt.Errorf("invalid If-Match %q, expected a revision ETag", header)
+	}
+	if bodyRevision != 0 && bodyRevision != revision {
+		return 0, fmt.Errorf("If-Match revision %d does not match body revision %d", revision, bodyRevision)
+	}
+	return revision, nil
+}
+
+func parseWeek(r *nethttp.Request) (int, int, error) {
+	year, err := strconv.Atoi(r.PathValue("year"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid year %q", r.PathValue("year"))
+	}
+	week, err := strconv.Atoi(r.PathValue("week"))
+	if err != nil || week < 1 || week > 53 {
+		return 0, 0, fmt.Errorf("invalid week %q, expected 1-53", r.PathValue("week"))
+	}
+	return year, week, nil
+}
+
+func decodeJSON(w nethttp.ResponseWriter, r *nethttp.Request, v any) error {
+	dec := json.NewDecoder(nethttp.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w nethttp.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w nethttp.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorJSON{Error: err.Error()})
+}
+
+// writeServiceError answers with the status of err's kind
+func writeServiceError(w nethttp.ResponseWriter, err error) {
+	writeError(w, serviceStatus(err), err)
+}
+
+// serviceStatus maps domain error kinds onto status codes
+func serviceStatus(err error) int {
+	if errors.Is(err, rating.ErrStaleRevision) {
+		return nethttp.StatusPreconditionFailed
+	}
+	switch rating.KindOf(err) {
+	case rating.KindValidation:
+		return nethttp.StatusBadRequest
+	case rating.KindNotFound:
+		return nethttp.StatusNotFound
+	case rating.KindConflict:
+		return nethttp.StatusConflict
+	case rating.KindStorage:
+		return nethttp.StatusServiceUnavailable
+	default:
+		return nethttp.StatusInternalServerError
+	}
+}
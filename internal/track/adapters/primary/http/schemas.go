@@ -0,0 +1,113 @@
+package http
+
+// schemas are the JSON Schema documents for the API's request and response bodies,
+// served from /api/v1/schemas/{name}
+var schemas = map[string]string{
+	"day-rating": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "/api/v1/schemas/day-rating",
+  "title": "DayRating",
+  "type": "object",
+  "required": ["id", "date", "rating", "label", "emoji", "revision"],
+  "properties": {
+    "id": {"type": "string", "pattern": "^[0-9]{2}w[0-9]{2}-[0-7]$"},
+    "date": {"type": "string", "format": "date"},
+    "rating": {"type": "integer", "minimum": 1, "maximum": 5},
+    "label": {"type": "string", "enum": ["Bad", "Poor", "Fair", "Good", "Awesome"]},
+    "emoji": {"type": "string"},
+    "note": {"type": "string"},
+    "revision": {"type": "integer", "minimum": 1, "description": "Also sent as the ETag"},
+    "createdAt": {"type": "string", "format": "date-time"},
+    "updatedAt": {"type": "string", "format": "date-time"},
+    "source": {"type": "string", "enum": ["cli", "tui", "http", "mcp", "fill"]},
+    "host": {"type": "string"},
+    "late": {"type": "boolean", "description": "Entered after the day it rates"},
+    "checkIns": {
+      "type": "array",
+      "description": "The ratings given through the day, rating is their aggregate",
+      "items": {
+        "type": "object",
+        "required": ["at", "rating", "label"],
+        "properties": {
+          "at": {"type": "string", "format": "date-time"},
+          "rating": {"type": "integer", "minimum": 1, "maximum": 5},
+          "label": {"type": "string", "enum": ["Bad", "Poor", "Fair", "Good", "Awesome"]},
+          "note": {"type": "string"}
+        }
+      }
+    }
+  }
+}`,
+	"create-day": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "/api/v1/schemas/create-day",
+  "title": "CreateDayRequest",
+  "type": "object",
+  "required": ["rating"],
+  "additionalProperties": false,
+  "properties": {
+    "date": {"type": "string", "format": "date", "description": "Defaults to today"},
+    "rating": {"type": "integer", "minimum": 1, "maximum": 5},
+    "note": {"type": "string"}
+  }
+}`,
+	"update-day": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "/api/v1/schemas/update-day",
+  "title": "UpdateDayRequest",
+  "type": "object",
+  "required": ["rating"],
+  "additionalProperties": false,
+  "properties": {
+    "rating": {"type": "integer", "minimum": 1, "maximum": 5},
+    "note": {"type": "string"},
+    "revision": {"type": "integer", "minimum": 1, "description": "Fail with 412 unless this is the stored revision, like If-Match"}
+  }
+}`,
+	"week-summary": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "/api/v1/schemas/week-summary",
+  "title": "WeekSummary",
+  "type": "object",
+  "required": ["year", "week", "average", "dayCount"],
+  "properties": {
+    "year": {"type": "integer"},
+    "week": {"type": "integer", "minimum": 1, "maximum": 53},
+    "average": {"type": "number"},
+    "dayCount": {"type": "integer", "minimum": 0, "maximum": 7},
+    "best": {"$ref": "/api/v1/schemas/day-rating"},
+    "worst": {"$ref": "/api/v1/schemas/day-rating"}
+  }
+}`,
+	"stats": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "/api/v1/schemas/stats",
+  "title": "Stats",
+  "type": "object",
+  "required": ["from", "to", "count", "average", "distribution", "currentStreak", "longestStreak", "late"],
+  "properties": {
+    "from": {"type": "string", "format": "date"},
+    "to": {"type": "string", "format": "date"},
+    "count": {"type": "integer", "minimum": 0},
+    "average": {"type": "number"},
+    "distribution": {
+      "type": "object",
+      "propertyNames": {"enum": ["1", "2", "3", "4", "5"]},
+      "additionalProperties": {"type": "integer", "minimum": 0}
+    },
+    "currentStreak": {"type": "integer", "minimum": 0},
+    "longestStreak": {"type": "integer", "minimum": 0},
+    "late": {"type": "integer", "minimum": 0, "description": "Ratings entered after their day, left out of the rest with ?excludeLate=true"}
+  }
+}`,
+	"error": `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "/api/v1/schemas/error",
+  "title": "Error",
+  "type": "object",
+  "required": ["error"],
+  "properties": {
+    "error": {"type": "string"}
+  }
+}`,
+}
@@ -0,0 +1,119 @@
+// internal/adapters/primary/http/server.go
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"track/internal/track/domain/rating"
+	ratingPort "track/internal/track/ports/primary/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// ShutdownTimeout bounds how long in-flight requests get to finish once the server is stopping
+const ShutdownTimeout = 5 * time.Second
+
+// Server exposes the rating service as a JSON API
+type Server struct {
+	service ratingPort.Service
+	clock   secondary.Clock
+	token   string
+	mux     *nethttp.ServeMux
+}
+
+// NewServer creates a server that requires the bearer token on every API call
+func NewServer(service ratingPort.Service, clock secondary.Clock, token string) *Server {
+	s := &Server{
+		service: service,
+		clock:   clock,
+		token:   token,
+		mux:     nethttp.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v1/health", s.handleHealth)
+	s.mux.HandleFunc("GET /api/v1/schemas/{name}", s.handleSchema)
+
+	s.mux.Handle("POST /api/v1/days", s.authorize(s.handleCreateDay))
+	s.mux.Handle("GET /api/v1/days", s.authorize(s.handleListRange))
+	s.mux.Handle("GET /api/v1/days/{date}", s.authorize(s.handleGetDay))
+	s.mux.Handle("PUT /api/v1/days/{date}", s.authorize(s.handleUpdateDay))
+	s.mux.Handle("DELETE /api/v1/days/{date}", s.authorize(s.handleDeleteDay))
+	s.mux.Handle("GET /api/v1/weeks/{year}/{week}", s.authorize(s.handleListWeek))
+	s.mux.Handle("GET /api/v1/weeks/{year}/{week}/summary", s.authorize(s.handleWeekSummary))
+	s.mux.Handle("GET /api/v1/stats", s.authorize(s.handleStats))
+	s.mux.Handle("GET /metrics", s.authorize(s.handleMetrics))
+
+	s.dashboardRoutes()
+}
+
+// Handler returns the root handler, useful for tests and embedding
+func (s *Server) Handler() nethttp.Handler {
+	// Ratings created through the API or the dashboard record where they came from
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		s.mux.ServeHTTP(w, r.WithContext(ratingPort.WithSource(r.Context(), rating.SourceHTTP)))
+	})
+}
+
+// authorize rejects requests without the server's bearer token
+func (s *Server) authorize(next nethttp.HandlerFunc) nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="track"`)
+			writeError(w, nethttp.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		next(w, r)
+	})
+}
+
+// ListenAndServe serves on addr until ctx is cancelled, then drains in-flight requests
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", addr, err)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is cancelled
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &nethttp.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down: %w", err)
+	}
+	if err := <-errCh; !errors.Is(err, nethttp.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
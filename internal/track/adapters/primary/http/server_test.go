@@ -0,0 +1,312 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"track/internal/track/adapters/primary/jsonview"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/memory"
+	ratingService "track/internal/track/application/rating"
+	"track/internal/track/domain/rating"
+)
+
+const testToken = "secret"
+
+// testNow is a Wednesday, the day after the ratings the tests create
+var testNow = time.Date(2025, time.February, 19, 9, 0, 0, 0, time.UTC)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	clk := clock.NewFixed(testNow)
+	return serve(t, ratingService.NewService(memory.NewMemoryRepository(), clk), clk)
+}
+
+// serve runs a server on service, for tests that rate through the service first
+func serve(t *testing.T, service *ratingService.Service, clk *clock.Fixed) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(NewServer(service, clk, testToken).Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func do(t *testing.T, ts *httptest.Server, method, path, body string) (*nethttp.Response, map[string]any) {
+	t.Helper()
+	return doWithHeader(t, ts, method, path, body, nil)
+}
+
+func doWithHeader(t *testing.T, ts *httptest.Server, method, path, body string, header nethttp.Header) (*nethttp.Response, map[string]any) {
+	t.Helper()
+	req, err := nethttp.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	if resp.StatusCode != nethttp.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+			t.Fatalf("%s %s: decoding body: %v", method, path, err)
+		}
+	}
+	return resp, decoded
+}
+
+func TestAuthRequired(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := ts.Client().Get(ts.URL + "/api/v1/days/2025-02-17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, nethttp.StatusUnauthorized)
+
+	resp, err = ts.Client().Get(ts.URL + "/api/v1/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, nethttp.StatusOK)
+}
+
+func TestDayLifecycle(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, body := do(t, ts, "POST", "/api/v1/days", `{"date":"2025-02-17","rating":4,"note":"good"}`)
+	assert.Equal(t, resp.StatusCode, nethttp.StatusCreated)
+	assert.Equal(t, body["id"], "25w08-1")
+	assert.Equal(t, body["label"], "Good")
+	assert.Equal(t, body["source"], "http")
+	assert.Equal(t, body["late"], true)
+
+	resp, body = do(t, ts, "PUT", "/api/v1/days/2025-02-17", `{"rating":5}`)
+	assert.Equal(t, resp.StatusCode, nethttp.StatusOK)
+	assert.Equal(t, body["rating"], float64(5))
+
+	resp, body = do(t, ts, "GET", "/api/v1/days/2025-02-17", "")
+	assert.Equal(t, resp.StatusCode, nethttp.StatusOK)
+	assert.Equal(t, body["emoji"], "🤩")
+
+	resp, _ = do(t, ts, "DELETE", "/api/v1/days/2025-02-17", "")
+	assert.Equal(t, resp.StatusCode, nethttp.StatusNoContent)
+
+	resp, _ = do(t, ts, "GET", "/api/v1/days/2025-02-17", "")
+	assert.Equal(t, resp.StatusCode, nethttp.StatusNotFound)
+
+	resp, _ = do(t, ts, "PUT", "/api/v1/days/2025-02-17", `{"rating":3}`)
+	assert.Equal(t, resp.StatusCode, nethttp.StatusNotFound)
+}
+
+func TestOverwriteProtection(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, body := do(t, ts, "POST", "/api/v1/days", `{"date":"2025-02-17","rating":4}`)
+	assert.Equal(t, resp.StatusCode, nethttp.StatusCreated)
+	assert.Equal(t, resp.Header.Get("ETag"), `"1"`)
+	assert.Equal(t, body["revision"], float64(1))
+
+	// Creating again must not replace the rating
+	resp, _ = do(t, ts, "POST", "/api/v1/days", `{"date":"2025-02-17","rating":1}`)
+	assert.Equal(t, resp.StatusCode, nethttp.StatusConflict)
+
+	ifMatch := nethttp.Header{"If-Match": {`"1"`}}
+	resp, body = doWithHeader(t, ts, "PUT", "/api/v1/days/2025-02-17", `{"rating":5}`, ifMatch)
+	assert.Equal(t, resp.StatusCode, nethttp.StatusOK)
+	assert.Equal(t, resp.Header.Get("ETag"), `"2"`)
+
+	// A second writer that also read revision 1 loses, by header or body
+	resp, _ = doWithHeader(t, ts, "PUT", "/api/v1/days/2025-02-17", `{"rating":2}`, ifMatch)
+	assert.Equal(t, resp.StatusCode, nethttp.StatusPreconditionFailed)
+	resp, _ = do(t, ts, "PUT", "/api/v1/days/2025-02-17", `{"rating":2,"revision":1}`)
+	assert.Equal(t, resp.StatusCode, nethttp.StatusPreconditionFailed)
+
+	resp, body = do(t, ts, "GET", "/api/v1/days/2025-02-17", "")
+	assert.Equal(t, body["rating"], float64(5))
+	assert.Equal(t, resp.Header.Get("ETag"), `"2"`)
+}
+
+func TestValidation(t *testing.T) {
+	ts := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"rating out of range", "POST", "/api/v1/days", `{"date":"2025-02-17","rating":6}`},
+		{"bad date", "POST", "/api/v1/days", `{"date":"17/02/2025","rating":3}`},
+		{"unknown field", "POST", "/api/v1/days", `{"rating":3,"mood":"meh"}`},
+		{"bad path date", "GET", "/api/v1/days/25w08-1", ""},
+		{"bad week", "GET", "/api/v1/weeks/2025/60/summary", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := do(t, ts, tt.method, tt.path, tt.body)
+			assert.Equal(t, resp.StatusCode, nethttp.StatusBadRequest)
+			if body["error"] == "" {
+				t.Errorf("expected an error message")
+			}
+		})
+	}
+}
+
+func TestListsAndSummaries(t *testing.T) {
+	ts := newTestServer(t)
+	for _, body := range []string{
+		`{"date":"2025-02-16","rating":1}`,
+		`{"date":"2025-02-17","rating":4}`,
+		`{"date":"2025-02-18","rating":2}`,
+	} {
+		if resp, _ := do(t, ts, "POST", "/api/v1/days", body); resp.StatusCode != nethttp.StatusCreated {
+			t.Fatalf("creating %s: %d", body, resp.StatusCode)
+		}
+	}
+
+	var week []jsonview.DayRating
+	getJSON(t, ts, "/api/v1/weeks/2025/8", &week)
+	assert.Equal(t, len(week), 2)
+
+	var days []jsonview.DayRating
+	getJSON(t, ts, "/api/v1/days?from=2025-02-16&to=2025-02-18", &days)
+	assert.Equal(t, len(days), 3)
+	assert.Equal(t, days[0].Date, "2025-02-16")
+	assert.Equal(t, days[2].Date, "2025-02-18")
+
+	var summary weekSummaryJSON
+	getJSON(t, ts, "/api/v1/weeks/2025/8/summary", &summary)
+	assert.Equal(t, summary.DayCount, 2)
+	assert.Equal(t, summary.Average, 3.0)
+	assert.Equal(t, summary.Best.ID, "25w08-1")
+
+	var stats statsJSON
+	getJSON(t, ts, "/api/v1/stats?from=2025-02-01&to=2025-02-19", &stats)
+	assert.Equal(t, stats.Count, 3)
+	assert.Equal(t, stats.LongestStreak, 3)
+	assert.Equal(t, stats.CurrentStreak, 3)
+	assert.Equal(t, stats.Distribution["4"], 1)
+}
+
+func TestRangeIsInLocalDays(t *testing.T) {
+	saved := time.Local
+	time.Local = time.FixedZone("EET", 2*60*60)
+	t.Cleanup(func() { time.Local = saved })
+
+	ctx := context.Background()
+	clk := clock.NewFixed(testNow)
+	service := ratingService.NewService(memory.NewMemoryRepository(), clk)
+	// Late on the 16th and early on the 17th, both the 16th in UTC
+	for _, at := range []time.Time{
+		time.Date(2025, time.February, 16, 23, 0, 0, 0, time.Local),
+		time.Date(2025, time.February, 17, 1, 0, 0, 0, time.Local),
+	} {
+		if _, err := service.CreateDayRating(ctx, at, rating.Good, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ts := serve(t, service, clk)
+
+	var days []jsonview.DayRating
+	getJSON(t, ts, "/api/v1/days?from=2025-02-17&to=2025-02-17", &days)
+	if len(days) != 1 || days[0].Date != "2025-02-17" {
+		t.Errorf("days on the 17th = %+v, want only the one rated that morning", days)
+	}
+}
+
+func TestSchemasAreJSON(t *testing.T) {
+	for name, schema := range schemas {
+		if !json.Valid([]byte(schema)) {
+			t.Errorf("schema %s is not valid JSON", name)
+		}
+	}
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	repo := memory.NewMemoryRepository()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		clk := clock.NewFixed(testNow)
+		done <- NewServer(ratingService.NewService(repo, clk), clk, testToken).Serve(ctx, ln)
+	}()
+
+	resp, err := nethttp.Get("http://" + ln.Addr().String() + "/api/v1/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Serve returned %v", err)
+		}
+	case <-time.After(ShutdownTimeout + time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func getJSON(t *testing.T, ts *httptest.Server, path string, v any) {
+	t.Helper()
+	req, err := nethttp.NewRequest("GET", ts.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("GET %s: %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	ts := newTestServer(t)
+	do(t, ts, "POST", "/api/v1/days", `{"rating":5}`)
+
+	req, err := nethttp.NewRequest("GET", ts.URL+"/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, resp.StatusCode, nethttp.StatusOK)
+	assert.Matches(t, string(body), "(?m)^track_day_rating 5$")
+	assert.Matches(t, string(body), "(?m)^track_streak_days 1$")
+	assert.Matches(t, string(body), "(?m)^track_days_since_last_rating 0$")
+}
@@ -0,0 +1,78 @@
+// internal/adapters/primary/jsonview/jsonview.go
+package jsonview
+
+import (
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+// DayRating is a day's rating as the CLI prints it with --json and the HTTP API and MCP server serve it
+type DayRating struct {
+	ID        string    `json:"id"`
+	Date      string    `json:"date"`
+	Rating    int       `json:"rating"`
+	Label     string    `json:"label"`
+	Emoji     string    `json:"emoji"`
+	Note      string    `json:"note,omitempty"`
+	Revision  int       `json:"revision"`
+	CreatedAt string    `json:"createdAt,omitempty"`
+	UpdatedAt string    `json:"updatedAt,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	Host      string    `json:"host,omitempty"`
+	Late      bool      `json:"late,omitempty"`
+	CheckIns  []CheckIn `json:"checkIns,omitempty"`
+}
+
+type CheckIn struct {
+	At     string `json:"at"`
+	Rating int    `json:"rating"`
+	Label  string `json:"label"`
+	Note   string `json:"note,omitempty"`
+}
+
+func NewDayRating(dr rating.DayRating) DayRating {
+	return DayRating{
+		ID:        dr.ID,
+		Date:      dr.Date.Format("2006-01-02"),
+		Rating:    int(dr.Rating),
+		Label:     dr.Rating.String(),
+		Emoji:     dr.Rating.Emoji(),
+		Note:      dr.Note,
+		Revision:  dr.Revision,
+		CreatedAt: Timestamp(dr.CreatedAt),
+		UpdatedAt: Timestamp(dr.UpdatedAt),
+		Source:    string(dr.Source),
+		Host:      dr.Host,
+		Late:      dr.RatedLate(),
+		CheckIns:  newCheckIns(dr.CheckIns),
+	}
+}
+
+func NewDayRatings(ratings []rating.DayRating) []DayRating {
+	out := make([]DayRating, 0, len(ratings))
+	for _, dr := range ratings {
+		out = append(out, NewDayRating(dr))
+	}
+	return out
+}
+
+func newCheckIns(checkIns []rating.CheckIn) []CheckIn {
+	var out []CheckIn
+	for _, c := range checkIns {
+		out = append(out, CheckIn{
+			At:     Timestamp(c.At),
+			Rating: int(c.Rating),
+			Label:  c.Rating.String(),
+			Note:   c.Note,
+		})
+	}
+	return out
+}
+
+// Timestamp formats t as RFC 3339, empty when it is unknown
+func Timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
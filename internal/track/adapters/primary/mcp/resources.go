@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"track/internal/track/adapters/primary/jsonview"
+	"track/internal/track/domain/rating"
+)
+
+const (
+	// RecentURI identifies the recent history resource
+	RecentURI = "track://ratings/recent"
+	// RecentDays is how far back the recent history resource reaches
+	RecentDays = 30
+)
+
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+}
+
+type resourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+func (s *Server) listResources() map[string]any {
+	return map[string]any{"resources": []resource{{
+		URI:         RecentURI,
+		Name:        "Recent day ratings",
+		Description: "Day ratings from the last 30 days, oldest first",
+		MimeType:    "application/json",
+	}}}
+}
+
+func (s *Server) readResource(ctx context.Context, params json.RawMessage) (any, error) {
+	var args struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, invalidParams("invalid resources/read params: %v", err)
+	}
+	if args.URI != RecentURI {
+		return nil, invalidParams("unknown resource: %s", args.URI)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, toolTimeout)
+	defer cancel()
+
+	now := s.clock.Now()
+	ratings, err := s.service.GetDateRangeRatings(ctx, rating.DayStart(now.AddDate(0, 0, -(RecentDays-1))), rating.DayEnd(now))
+	if err != nil {
+		return nil, err
+	}
+	text, err := json.MarshalIndent(jsonview.NewDayRatings(ratings), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"contents": []resourceContents{{
+		URI:      RecentURI,
+		MimeType: "application/json",
+		Text:     string(text),
+	}}}, nil
+}
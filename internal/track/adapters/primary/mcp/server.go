@@ -0,0 +1,164 @@
+// internal/adapters/primary/mcp/server.go
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	ratingPort "track/internal/track/ports/primary/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// ProtocolVersion is the Model Context Protocol revision this server speaks
+const ProtocolVersion = "2024-11-05"
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+const maxMessageBytes = 4 << 20
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// Server answers MCP requests using the rating service
+type Server struct {
+	service ratingPort.Service
+	clock   secondary.Clock
+	version string
+	tools   map[string]tool
+}
+
+// NewServer creates a server reporting version in its handshake
+func NewServer(service ratingPort.Service, clock secondary.Clock, version string) *Server {
+	s := &Server{
+		service: service,
+		clock:   clock,
+		version: version,
+	}
+	s.tools = s.registerTools()
+	return s
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes replies to w
+// until r is exhausted or ctx is cancelled
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageBytes)
+
+	enc := json.NewEncoder(w)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		resp, ok := s.handle(ctx, line)
+		if !ok {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("writing response: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading requests: %w", err)
+	}
+	return nil
+}
+
+// handle dispatches one message, reporting false for notifications that get no reply
+func (s *Server) handle(ctx context.Context, msg []byte) (response, bool) {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), &rpcError{Code: codeParseError, Message: "parse error"}), true
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, &rpcError{Code: codeInvalidRequest, Message: "invalid request"}), req.ID != nil
+	}
+	if req.ID == nil {
+		// Notifications such as notifications/initialized need no answer
+		return response{}, false
+	}
+
+	result, err := s.dispatch(ctx, req)
+	if err != nil {
+		var rpcErr *rpcError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &rpcError{Code: codeInternalError, Message: err.Error()}
+		}
+		return errorResponse(req.ID, rpcErr), true
+	}
+	return response{JSONRPC: "2.0", ID: req.ID, Result: result}, true
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (any, error) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities": map[string]any{
+				"tools":     map[string]any{},
+				"resources": map[string]any{},
+			},
+			"serverInfo": map[string]any{
+				"name":    "track",
+				"version": s.version,
+			},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	case "resources/list":
+		return s.listResources(), nil
+	case "resources/read":
+		return s.readResource(ctx, req.Params)
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+}
+
+func errorResponse(id json.RawMessage, err *rpcError) response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return response{JSONRPC: "2.0", ID: id, Error: err}
+}
+
+func invalidParams(format string, args ...any) error {
+	return &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
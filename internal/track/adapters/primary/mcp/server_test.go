@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"track/internal/track/adapters/primary/jsonview"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/memory"
+	ratingService "track/internal/track/application/rating"
+)
+
+// testClient speaks newline-delimited JSON-RPC to a server running over pipes
+type testClient struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Scanner
+	nextID int
+	done   chan error
+}
+
+func newTestClient(t *testing.T) *testClient {
+	t.Helper()
+	return newTestClientAt(t, time.Date(2025, time.February, 19, 9, 0, 0, 0, time.UTC))
+}
+
+// newTestClientAt serves tools whose clock stands at now
+func newTestClientAt(t *testing.T, now time.Time) *testClient {
+	t.Helper()
+	repo := memory.NewMemoryRepository()
+	clk := clock.NewFixed(now)
+	server := NewServer(ratingService.NewService(repo, clk), clk, "test")
+
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+	c := &testClient{t: t, in: reqW, out: bufio.NewScanner(respR), done: make(chan error, 1)}
+	go func() {
+		c.done <- server.Serve(context.Background(), reqR, respW)
+		respW.Close()
+	}()
+	t.Cleanup(func() {
+		reqW.Close()
+		if err := <-c.done; err != nil {
+			t.Errorf("Serve returned %v", err)
+		}
+	})
+	return c
+}
+
+func (c *testClient) send(line string) {
+	c.t.Helper()
+	if _, err := io.WriteString(c.in, line+"\n"); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) receive() response {
+	c.t.Helper()
+	if !c.out.Scan() {
+		c.t.Fatalf("no response: %v", c.out.Err())
+	}
+	var resp response
+	if err := json.Unmarshal(c.out.Bytes(), &resp); err != nil {
+		c.t.Fatalf("decoding %s: %v", c.out.Text(), err)
+	}
+	return resp
+}
+
+// call sends a request and decodes its result into v, failing on protocol errors
+func (c *testClient) call(method string, params any, v any) {
+	c.t.Helper()
+	c.nextID++
+	msg, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.send(string(msg))
+
+	resp := c.receive()
+	if resp.Error != nil {
+		c.t.Fatalf("%s: %s", method, resp.Error.Message)
+	}
+	raw, _ := json.Marshal(resp.Result)
+	if err := json.Unmarshal(raw, v); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) callTool(name string, args map[string]any) toolResult {
+	c.t.Helper()
+	var result toolResult
+	c.call("tools/call", map[string]any{"name": name, "arguments": args}, &result)
+	return result
+}
+
+func TestHandshake(t *testing.T) {
+	c := newTestClient(t)
+
+	var init struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	c.call("initialize", map[string]any{"protocolVersion": ProtocolVersion, "capabilities": map[string]any{}}, &init)
+	assert.Equal(t, init.ProtocolVersion, ProtocolVersion)
+	assert.Equal(t, init.ServerInfo.Name, "track")
+
+	// The initialized notification gets no reply, so the next line answers the ping
+	c.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	var pong map[string]any
+	c.call("ping", nil, &pong)
+
+	var list struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	c.call("tools/list", nil, &list)
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	assert.Equal(t, strings.Join(names, ","), "get_summary,get_week_ratings,set_day_rating")
+}
+
+func TestProtocolErrors(t *testing.T) {
+	c := newTestClient(t)
+
+	c.send(`{not json`)
+	assert.Equal(t, c.receive().Error.Code, codeParseError)
+
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"tools/unknown"}`)
+	assert.Equal(t, c.receive().Error.Code, codeMethodNotFound)
+
+	c.send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"nope"}}`)
+	assert.Equal(t, c.receive().Error.Code, codeInvalidParams)
+}
+
+func TestRatingTools(t *testing.T) {
+	c := newTestClient(t)
+
+	result := c.callTool("set_day_rating", map[string]any{"rating": 4, "date": "2025-02-17", "note": "pairing went well"})
+	assert.Equal(t, result.IsError, false)
+	assert.Equal(t, result.Content[0].Text, "Saved 25w08-1: Good 😊")
+	c.callTool("set_day_rating", map[string]any{"rating": 2, "date": "2025-02-18"})
+
+	result = c.callTool("set_day_rating", map[string]any{"rating": 7})
+	assert.Equal(t, result.IsError, true)
+	assert.Matches(t, result.Content[0].Text, "between 1 and 5")
+
+	result = c.callTool("set_day_rating", map[string]any{"rating": 1, "date": "2025-02-17"})
+	assert.Equal(t, result.IsError, true)
+	assert.Matches(t, result.Content[0].Text, "already rated Good")
+	result = c.callTool("set_day_rating", map[string]any{"rating": 1, "date": "2025-02-17", "overwrite": true})
+	assert.Equal(t, result.IsError, false)
+	c.callTool("set_day_rating", map[string]any{"rating": 4, "date": "2025-02-17", "note": "pairing went well", "overwrite": true})
+
+	result = c.callTool("get_week_ratings", map[string]any{"year": 2025, "week": 8})
+	assert.Equal(t, result.Content[0].Text, "Week 8, 2025:\nMon 2025-02-17: 4 Good (pairing went well)\nTue 2025-02-18: 2 Poor\n")
+
+	result = c.callTool("get_summary", map[string]any{"year": 2025, "week": 8})
+	assert.Equal(t, result.Content[0].Text, "Week 8, 2025: 2 days rated, average 3.0, best Mon Good (2025-02-17), worst Tue Poor (2025-02-18)")
+}
+
+func TestRecentResource(t *testing.T) {
+	c := newTestClient(t)
+	c.callTool("set_day_rating", map[string]any{"rating": 5})
+
+	var list struct {
+		Resources []resource `json:"resources"`
+	}
+	c.call("resources/list", nil, &list)
+	assert.Equal(t, list.Resources[0].URI, RecentURI)
+
+	var read struct {
+		Contents []resourceContents `json:"contents"`
+	}
+	c.call("resources/read", map[string]any{"uri": RecentURI}, &read)
+	var days []jsonview.DayRating
+	if err := json.Unmarshal([]byte(read.Contents[0].Text), &days); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(days), 1)
+	assert.Equal(t, days[0].Label, "Awesome")
+}
+
+func TestDatesAreLocalDays(t *testing.T) {
+	saved := time.Local
+	time.Local = time.FixedZone("HST", -10*60*60)
+	t.Cleanup(func() { time.Local = saved })
+
+	c := newTestClientAt(t, time.Date(2025, time.February, 19, 9, 0, 0, 0, time.Local))
+	// The first day the recent resource reaches back to
+	c.callTool("set_day_rating", map[string]any{"rating": 4, "date": "2025-01-21"})
+
+	var read struct {
+		Contents []resourceContents `json:"contents"`
+	}
+	c.call("resources/read", map[string]any{"uri": RecentURI}, &read)
+	var days []jsonview.DayRating
+	if err := json.Unmarshal([]byte(read.Contents[0].Text), &days); err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 1 || days[0].Date != "2025-01-21" {
+		t.Errorf("recent days = %+v, want the 21st of January", days)
+	}
+}
@@ -0,0 +1,243 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"track/internal/track/domain/rating"
+	ratingPort "track/internal/track/ports/primary/rating"
+)
+
+// DateFormat is the layout tools accept and return dates in
+const DateFormat = "2006-01-02"
+
+const toolTimeout = 5 * time.Second
+
+type tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+	call        func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+func (s *Server) registerTools() map[string]tool {
+	tools := []tool{
+		{
+			Name:        "set_day_rating",
+			Description: "Record how a day went on a 1-5 scale: 1 Bad, 2 Poor, 3 Fair, 4 Good, 5 Awesome. Fails if the day is already rated, unless overwrite is true; only overwrite when the user asked to change the rating.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "required": ["rating"],
+  "properties": {
+    "rating": {"type": "integer", "minimum": 1, "maximum": 5},
+    "date": {"type": "string", "format": "date", "description": "YYYY-MM-DD, defaults to today"},
+    "note": {"type": "string", "description": "Optional short note about the day"},
+    "overwrite": {"type": "boolean", "description": "Replace an existing rating for that day"}
+  }
+}`),
+			call: s.setDayRating,
+		},
+		{
+			Name:        "get_week_ratings",
+			Description: "List the day ratings recorded in an ISO week, defaulting to the current week.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "year": {"type": "integer", "description": "ISO year, defaults to this year"},
+    "week": {"type": "integer", "minimum": 1, "maximum": 53, "description": "ISO week, defaults to this week"}
+  }
+}`),
+			call: s.getWeekRatings,
+		},
+		{
+			Name:        "get_summary",
+			Description: "Summarise an ISO week: days rated, average, best and worst day. Defaults to the current week.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "year": {"type": "integer", "description": "ISO year, defaults to this year"},
+    "week": {"type": "integer", "minimum": 1, "maximum": 53, "description": "ISO week, defaults to this week"}
+  }
+}`),
+			call: s.getSummary,
+		},
+	}
+
+	byName := make(map[string]tool, len(tools))
+	for _, t := range tools {
+		byName[t.Name] = t
+	}
+	return byName
+}
+
+func (s *Server) listTools() map[string]any {
+	list := make([]tool, 0, len(s.tools))
+	for _, t := range s.tools {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return map[string]any{"tools": list}
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, error) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, invalidParams("invalid tools/call params: %v", err)
+	}
+	t, ok := s.tools[call.Name]
+	if !ok {
+		return nil, invalidParams("unknown tool: %s", call.Name)
+	}
+	if len(call.Arguments) == 0 {
+		call.Arguments = json.RawMessage("{}")
+	}
+
+	ctx, cancel := context.WithTimeout(ratingPort.WithSource(ctx, rating.SourceMCP), toolTimeout)
+	defer cancel()
+
+	// Failures inside a tool are results the model can read and react to, not protocol errors
+	text, err := t.call(ctx, call.Arguments)
+	if err != nil {
+		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return toolResult{Content: []textContent{{Type: "text", Text: text}}}, nil
+}
+
+func (s *Server) setDayRating(ctx context.Context, raw json.RawMessage) (string, error) {
+	var args struct {
+		Rating    int    `json:"rating"`
+		Date      string `json:"date"`
+		Note      string `json:"note"`
+		Overwrite bool   `json:"overwrite"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+	value, err := rating.NewRating(args.Rating)
+	if err != nil {
+		return "", err
+	}
+	date, err := parseDate(args.Date, s.clock.Now())
+	if err != nil {
+		return "", err
+	}
+
+	var dr rating.DayRating
+	if args.Overwrite {
+		dr, err = s.service.SetDayRating(ctx, date, value, args.Note)
+	} else {
+		dr, err = s.service.CreateDayRating(ctx, date, value, args.Note)
+	}
+	if errors.Is(err, rating.ErrAlreadyRated) {
+		return "", fmt.Errorf("%w. Ask the user before calling again with overwrite", err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Saved %s", dr), nil
+}
+
+func (s *Server) getWeekRatings(ctx context.Context, raw json.RawMessage) (string, error) {
+	year, week, err := weekArgs(raw, s.clock.Now())
+	if err != nil {
+		return "", err
+	}
+	ratings, err := s.service.GetWeekRatings(ctx, year, week)
+	if err != nil {
+		return "", err
+	}
+	if len(ratings) == 0 {
+		return fmt.Sprintf("No ratings recorded in week %d of %d", week, year), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Week %d, %d:\n", week, year)
+	for _, dr := range ratings {
+		fmt.Fprintf(&b, "%s %s: %d %s", dr.Date.Format("Mon"), dr.Date.Format(DateFormat), dr.Rating, dr.Rating.String())
+		if dr.Note != "" {
+			fmt.Fprintf(&b, " (%s)", dr.Note)
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+func (s *Server) getSummary(ctx context.Context, raw json.RawMessage) (string, error) {
+	year, week, err := weekArgs(raw, s.clock.Now())
+	if err != nil {
+		return "", err
+	}
+	summary, err := s.service.GetWeekSummary(ctx, year, week)
+	if err != nil {
+		return "", err
+	}
+	if summary.DayCount == 0 {
+		return fmt.Sprintf("No ratings recorded in week %d of %d", week, year), nil
+	}
+	return fmt.Sprintf("Week %d, %d: %d days rated, average %.1f, best %s %s (%s), worst %s %s (%s)",
+		week, year, summary.DayCount, summary.Average,
+		summary.Best.Date.Format("Mon"), summary.Best.Rating.String(), summary.Best.Date.Format(DateFormat),
+		summary.Worst.Date.Format("Mon"), summary.Worst.Rating.String(), summary.Worst.Date.Format(DateFormat),
+	), nil
+}
+
+func decodeArgs(raw json.RawMessage, v any) error {
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// weekArgs reads optional year/week arguments, defaulting to now's ISO week
+func weekArgs(raw json.RawMessage, now time.Time) (int, int, error) {
+	var args struct {
+		Year int `json:"year"`
+		Week int `json:"week"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return 0, 0, err
+	}
+	year, week := now.ISOWeek()
+	if args.Year != 0 {
+		year = args.Year
+	}
+	if args.Week != 0 {
+		week = args.Week
+	}
+	if week < 1 || week > 53 {
+		return 0, 0, fmt.Errorf("week must be between 1 and 53, got %d", week)
+	}
+	return year, week, nil
+}
+
+// parseDate reads an optional date argument as local midnight, defaulting to now
+func parseDate(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return now, nil
+	}
+	date, err := time.ParseInLocation(DateFormat, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: expected YYYY-MM-DD, got %q", rating.ErrInvalidDate, value)
+	}
+	return date, nil
+}
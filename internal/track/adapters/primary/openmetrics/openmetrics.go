@@ -0,0 +1,60 @@
+// internal/adapters/primary/openmetrics/openmetrics.go
+package openmetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"track/internal/track/domain/rating"
+)
+
+// ContentType is the Prometheus text exposition format served by /metrics
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Write renders the metrics snapshot in the Prometheus text exposition format,
+// which is also what node_exporter's textfile collector reads
+func Write(w io.Writer, m rating.Metrics) error {
+	b := bufio.NewWriter(w)
+
+	family(b, "track_day_rating", "gauge", "Today's day rating from 1 to 5, 0 when today is unrated.")
+	sample(b, "track_day_rating", "", float64(m.Today))
+
+	family(b, "track_day_rating_average", "gauge", "Average day rating over a trailing window of days.")
+	if m.Count7d > 0 {
+		sample(b, "track_day_rating_average", `window="7d"`, m.Average7d)
+	}
+	if m.Count30d > 0 {
+		sample(b, "track_day_rating_average", `window="30d"`, m.Average30d)
+	}
+
+	family(b, "track_days_rated", "gauge", "Days rated in a trailing window of days.")
+	sample(b, "track_days_rated", `window="7d"`, float64(m.Count7d))
+	sample(b, "track_days_rated", `window="30d"`, float64(m.Count30d))
+
+	family(b, "track_streak_days", "gauge", "Consecutive days rated up to today or yesterday.")
+	sample(b, "track_streak_days", "", float64(m.Streak))
+
+	if m.DaysSinceLast >= 0 {
+		family(b, "track_days_since_last_rating", "gauge", "Days since the most recent rating.")
+		sample(b, "track_days_since_last_rating", "", float64(m.DaysSinceLast))
+	}
+
+	family(b, "track_ratings", "gauge", "Days recorded with each rating.")
+	for r := rating.Bad; r <= rating.Awesome; r++ {
+		sample(b, "track_ratings", fmt.Sprintf(`rating="%d",label="%s"`, r, r.String()), float64(m.Counts[r]))
+	}
+
+	return b.Flush()
+}
+
+func family(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func sample(w io.Writer, name, labels string, value float64) {
+	if labels != "" {
+		name += "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s %s\n", name, strconv.FormatFloat(value, 'g', -1, 64))
+}
@@ -0,0 +1,51 @@
+package openmetrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"track/internal/track/domain/rating"
+)
+
+func TestWrite(t *testing.T) {
+	var b strings.Builder
+	err := Write(&b, rating.Metrics{
+		Today:         rating.Good,
+		Average7d:     3.5,
+		Count7d:       4,
+		Streak:        2,
+		DaysSinceLast: 0,
+		Total:         4,
+		Counts:        map[rating.Rating]int{rating.Fair: 2, rating.Good: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP track_day_rating Today's day rating from 1 to 5, 0 when today is unrated.
+# TYPE track_day_rating gauge
+track_day_rating 4
+# HELP track_day_rating_average Average day rating over a trailing window of days.
+# TYPE track_day_rating_average gauge
+track_day_rating_average{window="7d"} 3.5
+# HELP track_days_rated Days rated in a trailing window of days.
+# TYPE track_days_rated gauge
+track_days_rated{window="7d"} 4
+track_days_rated{window="30d"} 0
+# HELP track_streak_days Consecutive days rated up to today or yesterday.
+# TYPE track_streak_days gauge
+track_streak_days 2
+# HELP track_days_since_last_rating Days since the most recent rating.
+# TYPE track_days_since_last_rating gauge
+track_days_since_last_rating 0
+# HELP track_ratings Days recorded with each rating.
+# TYPE track_ratings gauge
+track_ratings{rating="1",label="Bad"} 0
+track_ratings{rating="2",label="Poor"} 0
+track_ratings{rating="3",label="Fair"} 2
+track_ratings{rating="4",label="Good"} 2
+track_ratings{rating="5",label="Awesome"} 0
+`
+	assert.Equal(t, b.String(), want)
+}
@@ -0,0 +1,75 @@
+// internal/adapters/secondary/clock/clock.go
+package clock
+
+import (
+	"sync"
+	"time"
+	"track/internal/track/ports/secondary"
+)
+
+// System is the wall clock
+type System struct{}
+
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// Fixed is a clock that only moves when told to, for tests
+type Fixed struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFixed(now time.Time) *Fixed {
+	return &Fixed{now: now}
+}
+
+func (c *Fixed) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to now
+func (c *Fixed) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by d
+func (c *Fixed) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// AsOf follows a base clock but can be pinned to another date, keeping the base's time of day
+type AsOf struct {
+	mu   sync.RWMutex
+	base secondary.Clock
+	date time.Time
+}
+
+func NewAsOf(base secondary.Clock) *AsOf {
+	return &AsOf{base: base}
+}
+
+func (c *AsOf) Now() time.Time {
+	now := c.base.Now()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.date.IsZero() {
+		return now
+	}
+	y, m, d := c.date.Date()
+	return time.Date(y, m, d, now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), now.Location())
+}
+
+// SetAsOf pins the clock to date, a zero date follows the base clock again
+func (c *AsOf) SetAsOf(date time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.date = date
+}
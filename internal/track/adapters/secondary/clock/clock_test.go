@@ -0,0 +1,25 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAsOfKeepsTimeOfDay(t *testing.T) {
+	base := NewFixed(time.Date(2025, time.March, 10, 21, 15, 0, 0, time.UTC))
+	clk := NewAsOf(base)
+
+	if got := clk.Now(); !got.Equal(base.Now()) {
+		t.Errorf("unpinned Now = %v, want %v", got, base.Now())
+	}
+
+	clk.SetAsOf(time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2025, time.February, 17, 21, 15, 0, 0, time.UTC); !clk.Now().Equal(want) {
+		t.Errorf("pinned Now = %v, want %v", clk.Now(), want)
+	}
+
+	clk.SetAsOf(time.Time{})
+	if got := clk.Now(); !got.Equal(base.Now()) {
+		t.Errorf("reset Now = %v, want %v", got, base.Now())
+	}
+}
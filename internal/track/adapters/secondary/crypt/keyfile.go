@@ -0,0 +1,308 @@
+// internal/adapters/secondary/crypt/keyfile.go
+package crypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// sealPrefix starts the Note of a sealed day, the rest is its encrypted content
+const sealPrefix = "track-sealed:v1:"
+
+// Params tune Argon2id, the memory-hard function that turns the passphrase into a key
+type Params struct {
+	Time uint32 `json:"time"`
+	// Memory is in KiB
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// DefaultParams are the second recommendation of RFC 9106, 64 MiB for one pass
+var DefaultParams = Params{Time: 1, Memory: 64 * 1024, Threads: 4}
+
+// KeyFile keeps a random key that seals the days with AES-256-GCM. The key itself
+// is sealed with a key derived from the passphrase, so changing the passphrase
+// rewrites only the key file.
+type KeyFile struct {
+	path        string
+	passphrases Passphrases
+	params      Params
+
+	mu  sync.Mutex
+	key []byte
+}
+
+// keyFile is the stored form
+type keyFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Params
+	Salt []byte `json:"salt"`
+	// Key is the sealed key, its nonce first
+	Key []byte `json:"key"`
+}
+
+var (
+	_ secondary.Cipher   = (*KeyFile)(nil)
+	_ secondary.KeyStore = (*KeyFile)(nil)
+)
+
+// NewKeyFile keeps the key at path, new keys being derived with params.
+// Until a key is created there, the ratings are stored in the clear.
+func NewKeyFile(path string, passphrases Passphrases, params Params) *KeyFile {
+	return &KeyFile{path: path, passphrases: passphrases, params: params}
+}
+
+func (k *KeyFile) Enabled() bool {
+	_, err := os.Stat(k.path)
+	return err == nil
+}
+
+func (k *KeyFile) Create(ctx context.Context) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.Enabled() {
+		return rating.Errorf(rating.KindConflict, "%s exists already", k.path)
+	}
+	passphrase, err := k.passphrases.New(ctx, false)
+	if err != nil {
+		return err
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return rating.Errorf(rating.KindInternal, "generating a key: %w", err)
+	}
+	if err := k.write(key, passphrase); err != nil {
+		return err
+	}
+	k.key = key
+	return nil
+}
+
+func (k *KeyFile) Remove(_ context.Context) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := os.Remove(k.path); err != nil && !os.IsNotExist(err) {
+		return rating.Errorf(rating.KindStorage, "removing the key: %w", err)
+	}
+	k.key = nil
+	return nil
+}
+
+func (k *KeyFile) Rekey(ctx context.Context) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	key, err := k.unlock(ctx)
+	if err != nil {
+		return err
+	}
+	passphrase, err := k.passphrases.New(ctx, true)
+	if err != nil {
+		return err
+	}
+	return k.write(key, passphrase)
+}
+
+func (k *KeyFile) Sealed(dr rating.DayRating) bool {
+	return dr.Rating == 0 && strings.HasPrefix(dr.Note, sealPrefix)
+}
+
+// sealed is the part of a day that is encrypted
+type sealed struct {
+	Rating   rating.Rating
+	Note     string           `json:",omitempty"`
+	Source   rating.Source    `json:",omitempty"`
+	Host     string           `json:",omitempty"`
+	CheckIns []rating.CheckIn `json:",omitempty"`
+}
+
+// Seal encrypts the day's rating, note, source and check-ins. They are bound to its
+// ID, date, revision and timestamps, so a sealed day copied over another, or an older
+// sealing of the same day put back, fails to open.
+func (k *KeyFile) Seal(ctx context.Context, dr rating.DayRating) (rating.DayRating, error) {
+	aead, err := k.aead(ctx)
+	if err != nil {
+		return rating.DayRating{}, err
+	}
+	plain, err := json.Marshal(sealed{Rating: dr.Rating, Note: dr.Note, Source: dr.Source, Host: dr.Host, CheckIns: dr.CheckIns})
+	if err != nil {
+		return rating.DayRating{}, rating.Errorf(rating.KindInternal, "marshaling %s: %w", dr.ID, err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return rating.DayRating{}, rating.Errorf(rating.KindInternal, "generating a nonce: %w", err)
+	}
+	box := aead.Seal(nonce, nonce, plain, boundTo(dr))
+
+	return rating.DayRating{
+		ID:        dr.ID,
+		Date:      dr.Date,
+		Note:      sealPrefix + base64.StdEncoding.EncodeToString(box),
+		Revision:  dr.Revision,
+		CreatedAt: dr.CreatedAt,
+		UpdatedAt: dr.UpdatedAt,
+	}, nil
+}
+
+func (k *KeyFile) Open(ctx context.Context, dr rating.DayRating) (rating.DayRating, error) {
+	if !k.Sealed(dr) {
+		return rating.DayRating{}, rating.Errorf(rating.KindStorage, "%s is stored unencrypted, it may have been tampered with", dr.ID)
+	}
+	aead, err := k.aead(ctx)
+	if err != nil {
+		return rating.DayRating{}, err
+	}
+	box, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(dr.Note, sealPrefix))
+	if err != nil || len(box) < aead.NonceSize() {
+		return rating.DayRating{}, rating.Errorf(rating.KindStorage, "%s is damaged", dr.ID)
+	}
+	plain, err := aead.Open(nil, box[:aead.NonceSize()], box[aead.NonceSize():], boundTo(dr))
+	if err != nil {
+		return rating.DayRating{}, rating.Errorf(rating.KindStorage, "%s fails to decrypt, it was changed or belongs to another day", dr.ID)
+	}
+	var s sealed
+	if err := json.Unmarshal(plain, &s); err != nil {
+		return rating.DayRating{}, rating.Errorf(rating.KindStorage, "reading %s: %w", dr.ID, err)
+	}
+
+	dr.Rating, dr.Note, dr.Source, dr.Host, dr.CheckIns = s.Rating, s.Note, s.Source, s.Host, s.CheckIns
+	return dr, nil
+}
+
+// boundTo is the additional data that ties a sealed day to its place and version
+func boundTo(dr rating.DayRating) []byte {
+	return []byte(strings.Join([]string{
+		dr.ID,
+		dr.Date.UTC().Format(time.RFC3339Nano),
+		strconv.Itoa(dr.Revision),
+		dr.CreatedAt.UTC().Format(time.RFC3339Nano),
+		dr.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}, "\x00"))
+}
+
+func (k *KeyFile) aead(ctx context.Context) (cipher.AEAD, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	key, err := k.unlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newAEAD(key)
+}
+
+// unlock returns the key, asking for the passphrase the first time. The caller must hold the lock.
+func (k *KeyFile) unlock(ctx context.Context) ([]byte, error) {
+	if k.key != nil {
+		return k.key, nil
+	}
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reading the key: %w", err)
+	}
+	var f keyFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reading the key from %s: %w", k.path, err)
+	}
+	if f.Version != 1 || f.KDF != "argon2id" {
+		return nil, rating.Errorf(rating.KindStorage, "%s is a version %d %s key, which this track can't read", k.path, f.Version, f.KDF)
+	}
+
+	passphrase, err := k.passphrases.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(derive(passphrase, f.Salt, f.Params))
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Key) < aead.NonceSize() {
+		return nil, rating.Errorf(rating.KindStorage, "%s is damaged", k.path)
+	}
+	key, err := aead.Open(nil, f.Key[:aead.NonceSize()], f.Key[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindValidation, "wrong passphrase")
+	}
+	k.key = key
+	return key, nil
+}
+
+// write stores key sealed under passphrase, with a new salt
+func (k *KeyFile) write(key []byte, passphrase string) error {
+	f := keyFile{Version: 1, KDF: "argon2id", Params: k.params, Salt: make([]byte, 16)}
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(f.Salt); err != nil {
+		return rating.Errorf(rating.KindInternal, "generating a salt: %w", err)
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return rating.Errorf(rating.KindInternal, "generating a nonce: %w", err)
+	}
+	aead, err := newAEAD(derive(passphrase, f.Salt, f.Params))
+	if err != nil {
+		return err
+	}
+	f.Key = aead.Seal(nonce, nonce, key, nil)
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return rating.Errorf(rating.KindInternal, "marshaling the key: %w", err)
+	}
+	if err := writePrivate(k.path, data); err != nil {
+		return rating.Errorf(rating.KindStorage, "writing the key: %w", err)
+	}
+	return nil
+}
+
+func derive(passphrase string, salt []byte, p Params) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, p.Time, p.Memory, p.Threads, 32)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindInternal, "creating the cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindInternal, "creating the cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// writePrivate replaces the file at path, readable by the user only, through a temp file and rename
+func writePrivate(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
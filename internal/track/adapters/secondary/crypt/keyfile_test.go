@@ -0,0 +1,149 @@
+package crypt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/file"
+	"track/internal/track/adapters/secondary/memory"
+	"track/internal/track/adapters/secondary/repotest"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// testParams keep the tests fast, the key derivation is not what they check
+var testParams = Params{Time: 1, Memory: 64, Threads: 1}
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func newKeyFile(t *testing.T, passphrase string) *KeyFile {
+	t.Helper()
+	keys := NewKeyFile(filepath.Join(t.TempDir(), "ratings.key"), Passphrases{Getenv: env(map[string]string{EnvPassphrase: passphrase})}, testParams)
+	if err := keys.Create(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) secondary.RatingRepository {
+		return NewRepository(memory.NewMemoryRepository(), newKeyFile(t, "correct horse"))
+	})
+}
+
+func TestSealedOnDisk(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	stored, err := file.NewFileRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(stored, newKeyFile(t, "correct horse"))
+
+	dr := repotest.Day(2025, time.February, 17, rating.Awesome)
+	dr.Note = "got the job"
+	if err := repo.Save(ctx, dr); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "got the job") || strings.Contains(string(data), `"Rating": 5`) {
+		t.Errorf("the ratings file shows the day:\n%s", data)
+	}
+	if got, err := repo.GetByID(ctx, dr.ID); err != nil || got.Note != dr.Note || got.Rating != dr.Rating {
+		t.Errorf("GetByID = %+v, %v", got, err)
+	}
+}
+
+func TestTamperDetection(t *testing.T) {
+	ctx := context.Background()
+	keys := newKeyFile(t, "correct horse")
+	monday := repotest.Day(2025, time.February, 17, rating.Good)
+	tuesday := repotest.Day(2025, time.February, 18, rating.Bad)
+	sealedMonday, err := keys.Seal(ctx, monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flipped := sealedMonday
+	i, c := len(sealPrefix)+20, "A"
+	if flipped.Note[i] == 'A' {
+		c = "B"
+	}
+	flipped.Note = flipped.Note[:i] + c + flipped.Note[i+1:]
+	moved := sealedMonday
+	moved.ID, moved.Date = tuesday.ID, tuesday.Date
+	// Monday rated again later, then its first sealing put back in place of the second
+	updated := monday
+	updated.Rating, updated.Revision, updated.UpdatedAt = rating.Bad, 2, monday.UpdatedAt.Add(time.Hour)
+	sealedUpdate, err := keys.Seal(ctx, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := sealedUpdate
+	replayed.Note = sealedMonday.Note
+	bumped := sealedMonday
+	bumped.Revision++
+
+	tests := map[string]rating.DayRating{
+		"changed content":        flipped,
+		"moved to another day":   moved,
+		"older sealing put back": replayed,
+		"revision changed":       bumped,
+		"replaced in clear":      tuesday,
+		"truncated":              {ID: monday.ID, Date: monday.Date, Note: sealPrefix + "AAAA"},
+	}
+	for name, dr := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := keys.Open(ctx, dr); rating.KindOf(err) != rating.KindStorage {
+				t.Errorf("Open = %v, want a storage error", err)
+			}
+		})
+	}
+
+	if got, err := keys.Open(ctx, sealedMonday); err != nil || got.Rating != rating.Good {
+		t.Errorf("Open of the untouched day = %v, %v", got.Rating, err)
+	}
+}
+
+func TestPassphrases(t *testing.T) {
+	ctx := context.Background()
+	keys := newKeyFile(t, "correct horse")
+	sealed, err := keys.Seal(ctx, repotest.Day(2025, time.February, 17, rating.Fair))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopen := func(vars map[string]string) *KeyFile {
+		return NewKeyFile(keys.path, Passphrases{Getenv: env(vars)}, testParams)
+	}
+	if _, err := reopen(map[string]string{EnvPassphrase: "battery staple"}).Open(ctx, sealed); rating.KindOf(err) != rating.KindValidation {
+		t.Errorf("Open with the wrong passphrase = %v, want a validation error", err)
+	}
+	if _, err := reopen(nil).Open(ctx, sealed); rating.KindOf(err) != rating.KindConfig {
+		t.Errorf("Open without a passphrase = %v, want a config error", err)
+	}
+
+	rekeyed := reopen(map[string]string{EnvPassphrase: "correct horse", EnvNewPassphrase: "battery staple"})
+	if err := rekeyed.Rekey(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reopen(map[string]string{EnvPassphrase: "correct horse"}).Open(ctx, sealed); err == nil {
+		t.Error("the old passphrase still opens days after a rekey")
+	}
+	if got, err := reopen(map[string]string{EnvPassphrase: "battery staple"}).Open(ctx, sealed); err != nil || got.Rating != rating.Fair {
+		t.Errorf("Open with the new passphrase = %v, %v", got.Rating, err)
+	}
+
+	command := NewKeyFile(keys.path, Passphrases{Getenv: env(nil), Command: "printf 'battery staple\\nnext line'"}, testParams)
+	if _, err := command.Open(ctx, sealed); err != nil {
+		t.Errorf("Open with a passphrase command = %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+// internal/adapters/secondary/crypt/passphrase.go
+package crypt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+	"track/internal/track/domain/rating"
+)
+
+// The environment variables read for the passphrase, and for the new one on a rekey
+const (
+	EnvPassphrase    = "TRACK_PASSPHRASE"
+	EnvNewPassphrase = "TRACK_NEW_PASSPHRASE"
+)
+
+// Prompt asks the user for a passphrase without echoing it
+type Prompt func(label string) (string, error)
+
+// Passphrases finds the passphrase in the environment, from a command such as a
+// password manager's, or by asking on the terminal, in that order
+type Passphrases struct {
+	Getenv func(string) string
+	// Command is run by the shell and prints the passphrase, key storage.passphrase-command
+	Command string
+	// Prompt asks last, it may be nil to never ask
+	Prompt Prompt
+}
+
+// Current returns the passphrase the key is encrypted with
+func (p Passphrases) Current(ctx context.Context) (string, error) {
+	if passphrase := p.Getenv(EnvPassphrase); passphrase != "" {
+		return passphrase, nil
+	}
+	if p.Command != "" {
+		return p.run(ctx)
+	}
+	if p.Prompt == nil {
+		return "", rating.Errorf(rating.KindConfig,
+			"the ratings are encrypted, set %s or storage.passphrase-command, or run in a terminal", EnvPassphrase)
+	}
+	return p.Prompt("Passphrase: ")
+}
+
+// New returns a passphrase to encrypt the key with. When replacing one, the current
+// passphrase's environment variable and command don't apply, so it comes from
+// $TRACK_NEW_PASSPHRASE or the terminal.
+func (p Passphrases) New(ctx context.Context, replacing bool) (string, error) {
+	if replacing {
+		if passphrase := p.Getenv(EnvNewPassphrase); passphrase != "" {
+			return passphrase, nil
+		}
+	} else {
+		if passphrase := p.Getenv(EnvPassphrase); passphrase != "" {
+			return passphrase, nil
+		}
+		if p.Command != "" {
+			return p.run(ctx)
+		}
+	}
+	if p.Prompt == nil {
+		env := EnvPassphrase
+		if replacing {
+			env = EnvNewPassphrase
+		}
+		return "", rating.Errorf(rating.KindConfig, "no new passphrase, set %s or run in a terminal", env)
+	}
+
+	passphrase, err := p.Prompt("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", rating.Errorf(rating.KindValidation, "the passphrase is empty")
+	}
+	again, err := p.Prompt("Repeat the passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", rating.Errorf(rating.KindValidation, "the passphrases don't match")
+	}
+	return passphrase, nil
+}
+
+func (p Passphrases) run(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", p.Command)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", rating.Errorf(rating.KindConfig, "storage.passphrase-command: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	// Like password managers' output, only the first line is the passphrase
+	passphrase, _, _ := strings.Cut(stdout.String(), "\n")
+	if passphrase == "" {
+		return "", rating.Errorf(rating.KindConfig, "storage.passphrase-command printed no passphrase")
+	}
+	return passphrase, nil
+}
+
+// Terminal prompts on the controlling terminal, so it works with stdin and stdout redirected
+func Terminal(label string) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", rating.Errorf(rating.KindConfig,
+			"no terminal to ask for the passphrase on, set %s or storage.passphrase-command", EnvPassphrase)
+	}
+	defer tty.Close()
+
+	fmt.Fprint(tty, label)
+	passphrase, err := term.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(tty)
+	if err != nil {
+		return "", rating.Errorf(rating.KindConfig, "reading the passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
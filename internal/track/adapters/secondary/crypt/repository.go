@@ -0,0 +1,91 @@
+// internal/adapters/secondary/crypt/repository.go
+package crypt
+
+import (
+	"context"
+	"errors"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// Repository seals days on their way into another repository and opens them on the
+// way out. While encryption is off it passes them through as they are.
+type Repository struct {
+	stored secondary.RatingRepository
+	cipher secondary.Cipher
+}
+
+var (
+	_ secondary.RatingRepository = (*Repository)(nil)
+	_ secondary.Prober           = (*Repository)(nil)
+)
+
+func NewRepository(stored secondary.RatingRepository, cipher secondary.Cipher) *Repository {
+	return &Repository{stored: stored, cipher: cipher}
+}
+
+func (r *Repository) Save(ctx context.Context, dr rating.DayRating) error {
+	if r.cipher.Enabled() {
+		var err error
+		if dr, err = r.cipher.Seal(ctx, dr); err != nil {
+			return err
+		}
+	}
+	return r.stored.Save(ctx, dr)
+}
+
+func (r *Repository) GetByID(ctx context.Context, id string) (rating.DayRating, error) {
+	dr, err := r.stored.GetByID(ctx, id)
+	if err != nil || !r.cipher.Enabled() {
+		return dr, err
+	}
+	return r.cipher.Open(ctx, dr)
+}
+
+// Exists looks the day up without opening it, so it never asks for the passphrase
+func (r *Repository) Exists(ctx context.Context, id string) (bool, error) {
+	_, err := r.stored.GetByID(ctx, id)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, rating.ErrNotFound):
+		return false, nil
+	}
+	return false, err
+}
+
+func (r *Repository) GetByDateRange(ctx context.Context, start, end time.Time) ([]rating.DayRating, error) {
+	ratings, err := r.stored.GetByDateRange(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(ctx, ratings)
+}
+
+func (r *Repository) GetByWeek(ctx context.Context, year, week int) ([]rating.DayRating, error) {
+	ratings, err := r.stored.GetByWeek(ctx, year, week)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(ctx, ratings)
+}
+
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	return r.stored.Delete(ctx, id)
+}
+
+func (r *Repository) open(ctx context.Context, ratings []rating.DayRating) ([]rating.DayRating, error) {
+	if !r.cipher.Enabled() {
+		return ratings, nil
+	}
+	opened := make([]rating.DayRating, 0, len(ratings))
+	for _, dr := range ratings {
+		day, err := r.cipher.Open(ctx, dr)
+		if err != nil {
+			return nil, err
+		}
+		opened = append(opened, day)
+	}
+	return opened, nil
+}
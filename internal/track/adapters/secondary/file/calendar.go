@@ -0,0 +1,547 @@
+// internal/adapters/secondary/file/calendar.go
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"track/internal/track/domain/activity"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// CalendarFiles reads iCalendar (.ics) files, as calendar apps export them. All-day,
+// cancelled and free (TRANSP:TRANSPARENT) events are left out, as are those an attendee
+// with one of Emails declined. Recurring events are expanded for FREQ=DAILY, WEEKLY,
+// MONTHLY and YEARLY with INTERVAL, COUNT, UNTIL and BYDAY, and BYMONTHDAY, BYMONTH
+// and WKST where they don't change the days, less their EXDATEs. An occurrence moved or
+// cancelled is taken from the event with its RECURRENCE-ID. Other frequencies and rule
+// parts, such as BYSETPOS, are an error rather than counted wrong. Events come back in
+// the local time zone, so a meeting's day is the user's.
+type CalendarFiles struct {
+	// Emails are the user's addresses, telling their attendance from other attendees'
+	Emails []string
+}
+
+var _ secondary.CalendarReader = CalendarFiles{}
+
+func (c CalendarFiles) Events(_ context.Context, path string, start, end time.Time) ([]activity.Event, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reading %s: %w", path, err)
+	}
+	vevents, err := parseCalendar(data)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindValidation, "%s: %w", path, err)
+	}
+	events, err := expandEvents(vevents, c.Emails, start, end)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindValidation, "%s: %w", path, err)
+	}
+	return events, nil
+}
+
+// vevent is the part of a VEVENT that tells when it happens
+type vevent struct {
+	uid          string
+	start, end   time.Time
+	allDay       bool
+	rule         string
+	exdates      []time.Time
+	recurrenceID time.Time
+	cancelled    bool
+	transparent  bool
+	// declinedBy are the addresses of the attendees who declined
+	declinedBy []string
+}
+
+func parseCalendar(data []byte) ([]vevent, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	// Long lines are folded onto lines starting with a space or a tab
+	text = strings.NewReplacer("\n ", "", "\n\t", "").Replace(text)
+
+	var (
+		events   []vevent
+		calendar bool
+		stack    []string
+		e        vevent
+		dtend    bool
+		duration time.Duration
+	)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, params, value, ok := parseContentLine(line)
+		if !ok {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		switch name {
+		case "BEGIN":
+			if len(stack) == 0 && !strings.EqualFold(value, "VCALENDAR") {
+				return nil, fmt.Errorf("not an iCalendar file, it begins with %s", value)
+			}
+			calendar = true
+			stack = append(stack, strings.ToUpper(value))
+			if len(stack) == 2 && stack[1] == "VEVENT" {
+				e, dtend, duration = vevent{}, false, 0
+			}
+			continue
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != strings.ToUpper(value) {
+				return nil, fmt.Errorf("END:%s without its BEGIN", value)
+			}
+			if len(stack) == 2 && stack[1] == "VEVENT" {
+				if e.start.IsZero() {
+					return nil, fmt.Errorf("event %q has no DTSTART", e.uid)
+				}
+				if !dtend {
+					e.end = e.start.Add(duration)
+				}
+				events = append(events, e)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		// Only the event's own properties, not those of its alarms
+		if len(stack) != 2 || stack[1] != "VEVENT" {
+			continue
+		}
+
+		var err error
+		switch name {
+		case "UID":
+			e.uid = value
+		case "DTSTART":
+			e.start, e.allDay, err = parseCalendarTime(value, params)
+		case "DTEND":
+			e.end, _, err = parseCalendarTime(value, params)
+			dtend = true
+		case "DURATION":
+			duration, err = parseCalendarDuration(value)
+		case "RRULE":
+			e.rule = value
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				var t time.Time
+				if t, _, err = parseCalendarTime(v, params); err != nil {
+					break
+				}
+				e.exdates = append(e.exdates, t)
+			}
+		case "RECURRENCE-ID":
+			e.recurrenceID, _, err = parseCalendarTime(value, params)
+		case "STATUS":
+			e.cancelled = strings.EqualFold(value, "CANCELLED")
+		case "TRANSP":
+			e.transparent = strings.EqualFold(value, "TRANSPARENT")
+		case "ATTENDEE":
+			if strings.EqualFold(params["PARTSTAT"], "DECLINED") {
+				e.declinedBy = append(e.declinedBy, attendeeEmail(value))
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("event %q: %s: %w", e.uid, name, err)
+		}
+	}
+	if !calendar {
+		return nil, fmt.Errorf("not an iCalendar file")
+	}
+	return events, nil
+}
+
+// parseContentLine splits NAME;PARAM=value:VALUE, a colon in a quoted parameter not ending the name
+func parseContentLine(line string) (name string, params map[string]string, value string, ok bool) {
+	quoted, colon := false, -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 1 {
+		return "", nil, "", false
+	}
+	parts := strings.Split(line[:colon], ";")
+	params = make(map[string]string)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+// attendeeEmail is the address in an attendee's mailto: URI
+func attendeeEmail(value string) string {
+	if len(value) > len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		return value[len("mailto:"):]
+	}
+	return value
+}
+
+// parseCalendarTime reads a date, a UTC time ending in Z, a time in the zone named by TZID,
+// or a floating time, taken as local
+func parseCalendarTime(value string, params map[string]string) (t time.Time, allDay bool, err error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err = time.ParseInLocation("20060102", value, time.Local)
+		return t, true, err
+	}
+	loc := time.Local
+	if v, ok := strings.CutSuffix(value, "Z"); ok {
+		value, loc = v, time.UTC
+	} else if tzid := params["TZID"]; tzid != "" {
+		// Zones outside the tz database, such as Windows' names, are taken as local time
+		if l, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = l
+		}
+	}
+	t, err = time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseCalendarDuration reads a duration such as PT1H30M or P1D
+func parseCalendarDuration(value string) (time.Duration, error) {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(value, "+"), "P")
+	if !ok {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	var d time.Duration
+	inTime, digits := false, ""
+	for _, r := range rest {
+		if r >= '0' && r <= '9' {
+			digits += string(r)
+			continue
+		}
+		if r == 'T' {
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		digits = ""
+		var unit time.Duration
+		switch {
+		case r == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case r == 'H' && inTime:
+			unit = time.Hour
+		case r == 'M' && inTime:
+			unit = time.Minute
+		case r == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d += time.Duration(n) * unit
+	}
+	if digits != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// expandEvents returns the occurrences of the events that overlap start to end, less
+// those one of emails declined
+func expandEvents(vevents []vevent, emails []string, start, end time.Time) ([]activity.Event, error) {
+	// Occurrences moved or cancelled are events of their own, with the recurring event's UID
+	overridden := make(map[string]map[int64]bool)
+	for _, e := range vevents {
+		if e.recurrenceID.IsZero() {
+			continue
+		}
+		if overridden[e.uid] == nil {
+			overridden[e.uid] = make(map[int64]bool)
+		}
+		overridden[e.uid][e.recurrenceID.Unix()] = true
+	}
+
+	var events []activity.Event
+	for _, e := range vevents {
+		if e.allDay || e.cancelled || e.transparent || declined(e.declinedBy, emails) {
+			continue
+		}
+		starts := []time.Time{e.start}
+		if e.rule != "" && e.recurrenceID.IsZero() {
+			var err error
+			if starts, err = occurrences(e.start, e.rule, end); err != nil {
+				return nil, fmt.Errorf("event %q: %w", e.uid, err)
+			}
+		}
+		length := e.end.Sub(e.start)
+		for _, s := range starts {
+			if e.recurrenceID.IsZero() && (overridden[e.uid][s.Unix()] || excluded(e.exdates, s)) {
+				continue
+			}
+			if s.After(end) || (!s.Add(length).After(start) && s.Before(start)) {
+				continue
+			}
+			events = append(events, activity.Event{UID: e.uid, Start: s.In(time.Local), End: s.Add(length).In(time.Local)})
+		}
+	}
+	return events, nil
+}
+
+func declined(declinedBy, emails []string) bool {
+	for _, d := range declinedBy {
+		for _, email := range emails {
+			if strings.EqualFold(d, email) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func excluded(exdates []time.Time, t time.Time) bool {
+	for _, x := range exdates {
+		if x.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// weekday is a BYDAY entry, such as MO, 2TU or -1FR, the nth such day of the month or every one when n is 0
+type weekday struct {
+	n   int
+	day time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseWeekday(v string) (weekday, error) {
+	if len(v) < 2 {
+		return weekday{}, fmt.Errorf("invalid day %q", v)
+	}
+	day, ok := weekdays[strings.ToUpper(v[len(v)-2:])]
+	if !ok {
+		return weekday{}, fmt.Errorf("invalid day %q", v)
+	}
+	wd := weekday{day: day}
+	if prefix := v[:len(v)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil {
+			return weekday{}, fmt.Errorf("invalid day %q", v)
+		}
+		wd.n = n
+	}
+	return wd, nil
+}
+
+// maxPeriods bounds the expansion of a rule that would otherwise run for ever
+const maxPeriods = 100000
+
+// occurrences expands the RRULE rule from dtstart up to limit, keeping the time of day in dtstart's zone.
+// A frequency or rule part other than those CalendarFiles lists is an error.
+func occurrences(dtstart time.Time, rule string, limit time.Time) ([]time.Time, error) {
+	var (
+		freq     string
+		interval = 1
+		count    int
+		byday    []weekday
+		// Exports repeat dtstart's day and month in these, other values are not supported
+		bymonthday, bymonth, wkst string
+	)
+	for _, part := range strings.Split(rule, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			freq = strings.ToUpper(v)
+		case "INTERVAL":
+			if interval, err = strconv.Atoi(v); err == nil && interval < 1 {
+				err = fmt.Errorf("must be 1 or more")
+			}
+		case "COUNT":
+			count, err = strconv.Atoi(v)
+		case "UNTIL":
+			var until time.Time
+			var allDay bool
+			if until, allDay, err = parseCalendarTime(v, nil); allDay {
+				until = until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			if err == nil && until.Before(limit) {
+				limit = until
+			}
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				var wd weekday
+				if wd, err = parseWeekday(d); err != nil {
+					break
+				}
+				byday = append(byday, wd)
+			}
+		case "BYMONTHDAY":
+			bymonthday = v
+		case "BYMONTH":
+			bymonth = v
+		case "WKST":
+			wkst = strings.ToUpper(v)
+		default:
+			return nil, fmt.Errorf("RRULE %s is not supported", part)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("RRULE %s: %w", part, err)
+		}
+	}
+
+	switch freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, fmt.Errorf("RRULE %s has no FREQ", rule)
+	default:
+		return nil, fmt.Errorf("RRULE FREQ=%s is not supported", freq)
+	}
+	switch {
+	case bymonthday != "" && (bymonthday != strconv.Itoa(dtstart.Day()) || len(byday) > 0 || freq == "DAILY" || freq == "WEEKLY"):
+		return nil, fmt.Errorf("RRULE BYMONTHDAY=%s is not supported", bymonthday)
+	case bymonth != "" && (bymonth != strconv.Itoa(int(dtstart.Month())) || freq != "YEARLY"):
+		return nil, fmt.Errorf("RRULE BYMONTH=%s is not supported", bymonth)
+	case len(byday) > 0 && freq == "YEARLY":
+		return nil, fmt.Errorf("RRULE BYDAY with FREQ=YEARLY is not supported")
+	// The day a week starts on only matters to several days every few weeks
+	case wkst != "" && wkst != "MO" && freq == "WEEKLY" && interval > 1 && len(byday) > 1:
+		return nil, fmt.Errorf("RRULE WKST=%s is not supported", wkst)
+	}
+	for _, wd := range byday {
+		if wd.n != 0 && freq != "MONTHLY" {
+			return nil, fmt.Errorf("RRULE BYDAY with a numbered day is only supported with FREQ=MONTHLY")
+		}
+	}
+
+	var starts []time.Time
+	// add keeps t, returning false once the rule is done
+	add := func(t time.Time) bool {
+		if t.After(limit) || (count > 0 && len(starts) >= count) {
+			return false
+		}
+		if !t.Before(dtstart) {
+			starts = append(starts, t)
+		}
+		return true
+	}
+	y, m, d := dtstart.Date()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+	}
+
+	for i := 0; i < maxPeriods; i++ {
+		switch freq {
+		case "DAILY":
+			t := at(y, m, d+i*interval)
+			if len(byday) > 0 && !onDay(byday, t.Weekday()) {
+				if t.After(limit) {
+					return starts, nil
+				}
+				continue
+			}
+			if !add(t) {
+				return starts, nil
+			}
+		case "WEEKLY":
+			// Weeks start on Monday
+			monday := d - (int(dtstart.Weekday())+6)%7 + 7*i*interval
+			days := byday
+			if len(days) == 0 {
+				days = []weekday{{day: dtstart.Weekday()}}
+			}
+			offsets := make([]int, 0, len(days))
+			for _, wd := range days {
+				offsets = append(offsets, (int(wd.day)+6)%7)
+			}
+			sort.Ints(offsets)
+			for _, off := range offsets {
+				if !add(at(y, m, monday+off)) {
+					return starts, nil
+				}
+			}
+		case "MONTHLY":
+			first := at(y, m+time.Month(i*interval), 1)
+			if first.After(limit) {
+				return starts, nil
+			}
+			var days []int
+			if len(byday) == 0 {
+				days = []int{d}
+			} else {
+				days = monthDays(first.Year(), first.Month(), byday)
+			}
+			for _, day := range days {
+				t := at(first.Year(), first.Month(), day)
+				// The 31st is skipped in shorter months
+				if t.Month() != first.Month() {
+					continue
+				}
+				if !add(t) {
+					return starts, nil
+				}
+			}
+		case "YEARLY":
+			t := at(y+i*interval, m, d)
+			if t.After(limit) {
+				return starts, nil
+			}
+			if t.Month() != m {
+				// The 29th of February outside leap years
+				continue
+			}
+			if !add(t) {
+				return starts, nil
+			}
+		}
+	}
+	return starts, nil
+}
+
+func onDay(byday []weekday, day time.Weekday) bool {
+	for _, wd := range byday {
+		if wd.day == day {
+			return true
+		}
+	}
+	return false
+}
+
+// monthDays returns the days of the month that byday picks, in order
+func monthDays(year int, month time.Month, byday []weekday) []int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	picked := make(map[int]bool)
+	for _, wd := range byday {
+		var matching []int
+		for day := 1; day <= last; day++ {
+			if time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() == wd.day {
+				matching = append(matching, day)
+			}
+		}
+		switch {
+		case wd.n == 0:
+			for _, day := range matching {
+				picked[day] = true
+			}
+		case wd.n > 0 && wd.n <= len(matching):
+			picked[matching[wd.n-1]] = true
+		case wd.n < 0 && -wd.n <= len(matching):
+			picked[matching[len(matching)+wd.n]] = true
+		}
+	}
+	days := make([]int, 0, len(picked))
+	for day := range picked {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days
+}
@@ -0,0 +1,191 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"track/internal/track/domain/activity"
+	"track/internal/track/domain/rating"
+)
+
+const testCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Example//Calendar//EN
+BEGIN:VEVENT
+UID:standup
+DTSTART;TZID=Europe/Berlin:20250210T093000
+DURATION:PT15M
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;UNTIL=20250228T235959Z
+EXDATE;TZID=Europe/Berlin:20250219T093000
+SUMMARY:Standup
+BEGIN:VALARM
+TRIGGER:-PT10M
+ACTION:DISPLAY
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID;TZID=Europe/Berlin:20250217T093000
+DTSTART;TZID=Europe/Berlin:20250217T110000
+DTEND;TZID=Europe/Berlin:20250217T113000
+SUMMARY:Standup, moved
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID;TZID=Europe/Berlin:20250221T093000
+DTSTART;TZID=Europe/Berlin:20250221T093000
+STATUS:CANCELLED
+END:VEVENT
+BEGIN:VEVENT
+UID:planning
+DTSTART:20250217T130000Z
+DTEND:20250217T143000Z
+SUMMARY:Planning with a description folded
+  onto the next line
+END:VEVENT
+BEGIN:VEVENT
+UID:holiday
+DTSTART;VALUE=DATE:20250218
+DTEND;VALUE=DATE:20250219
+END:VEVENT
+BEGIN:VEVENT
+UID:retro
+DTSTART;TZID=Europe/Berlin:20250113T150000
+DTEND;TZID=Europe/Berlin:20250113T160000
+RRULE:FREQ=MONTHLY;BYDAY=-1TH;COUNT=3
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestCalendarFiles(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	saved := time.Local
+	time.Local = berlin
+	t.Cleanup(func() { time.Local = saved })
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "work.ics")
+	if err := os.WriteFile(path, []byte(strings.ReplaceAll(testCalendar, "\n", "\r\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, time.February, 17, 0, 0, 0, 0, berlin)
+	events, err := CalendarFiles{}.Events(ctx, path, start, time.Date(2025, time.March, 31, 23, 59, 59, 0, berlin))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range events {
+		got = append(got, e.UID+" "+e.Start.Format("01-02 15:04")+" "+e.End.Sub(e.Start).String())
+	}
+	want := []string{
+		// The 17th moved, the 19th excluded and the 21st cancelled
+		"standup 02-24 09:30 15m0s",
+		"standup 02-26 09:30 15m0s",
+		"standup 02-28 09:30 15m0s",
+		"standup 02-17 11:00 30m0s",
+		"planning 02-17 14:00 1h30m0s",
+		// The last Thursday of each month, three times from January
+		"retro 02-27 15:00 1h0m0s",
+		"retro 03-27 15:00 1h0m0s",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Events =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	csv := filepath.Join(t.TempDir(), "sleep.csv")
+	if err := os.WriteFile(csv, []byte("date,sleep\n2025-02-17,7\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (CalendarFiles{}).Events(ctx, csv, start, start); rating.KindOf(err) != rating.KindValidation {
+		t.Errorf("Events of a CSV file = %v, want a validation error", err)
+	}
+}
+
+func TestExpandEventsLeavesOut(t *testing.T) {
+	start := time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.February, 23, 23, 59, 59, 0, time.UTC)
+	// event is the calendar with a meeting from 10 to 11 on the 17th, its properties
+	// added to and followed by more events
+	event := func(props string, more ...string) string {
+		return "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:sync\nDTSTART:20250217T100000Z\nDTEND:20250217T110000Z\n" + props +
+			"END:VEVENT\n" + strings.Join(more, "") + "END:VCALENDAR\n"
+	}
+	tests := []struct {
+		name     string
+		calendar string
+		want     int
+	}{
+		{"busy", event("TRANSP:OPAQUE\n"), 1},
+		{"free", event("TRANSP:TRANSPARENT\n"), 0},
+		{"declined by the user", event("ATTENDEE;CN=Ann;PARTSTAT=DECLINED:mailto:Ann@Example.com\n"), 0},
+		{"declined by someone else", event("ATTENDEE;PARTSTAT=DECLINED:mailto:bob@example.com\nATTENDEE;PARTSTAT=ACCEPTED:mailto:ann@example.com\n"), 1},
+		{"one occurrence declined", event("RRULE:FREQ=DAILY;COUNT=3\n",
+			"BEGIN:VEVENT\nUID:sync\nRECURRENCE-ID:20250218T100000Z\nDTSTART:20250218T100000Z\nDTEND:20250218T110000Z\n"+
+				"ATTENDEE;PARTSTAT=DECLINED:mailto:ann@example.com\nEND:VEVENT\n"), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vevents, err := parseCalendar([]byte(tt.calendar))
+			if err != nil {
+				t.Fatal(err)
+			}
+			events, err := expandEvents(vevents, []string{"ann@example.com"}, start, end)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != tt.want {
+				t.Errorf("expandEvents = %+v, want %d events", events, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandEventsRules(t *testing.T) {
+	start := time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC)
+	expand := func(rule string) ([]activity.Event, error) {
+		t.Helper()
+		// A Monday, the 17th of February
+		vevents, err := parseCalendar([]byte("BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:sync\nDTSTART:20250217T100000Z\nDURATION:PT30M\nRRULE:" + rule + "\nEND:VEVENT\nEND:VCALENDAR\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return expandEvents(vevents, nil, start, start.AddDate(1, 0, 0))
+	}
+
+	for _, rule := range []string{
+		"FREQ=HOURLY;COUNT=3",
+		"FREQ=MINUTELY",
+		"COUNT=3",
+		// The last weekday of the month, not every weekday
+		"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"FREQ=MONTHLY;BYMONTHDAY=1,15",
+		"FREQ=YEARLY;BYMONTH=3",
+		"FREQ=YEARLY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;WKST=SU",
+		"FREQ=DAILY;BYHOUR=9,15",
+	} {
+		if _, err := expand(rule); err == nil {
+			t.Errorf("RRULE:%s = nil error, want it unsupported", rule)
+		}
+	}
+
+	// Parts that only repeat what DTSTART says
+	for rule, want := range map[string]int{
+		"FREQ=MONTHLY;BYMONTHDAY=17;COUNT=3":          3,
+		"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=17;COUNT=2": 1,
+		"FREQ=WEEKLY;BYDAY=MO;WKST=SU;COUNT=4":        4,
+	} {
+		events, err := expand(rule)
+		if err != nil || len(events) != want {
+			t.Errorf("RRULE:%s = %d events, %v, want %d", rule, len(events), err, want)
+		}
+	}
+}
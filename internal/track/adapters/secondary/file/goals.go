@@ -0,0 +1,102 @@
+// internal/adapters/secondary/file/goals.go
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"track/internal/track/domain/goal"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// GoalRepository keeps goals in a small JSON file that is read on every call,
+// so edits from another process are always seen
+type GoalRepository struct {
+	mu       sync.Mutex
+	filepath string
+}
+
+// goalsFile is the file layout, LastID keeps removed goals' IDs from being reused
+type goalsFile struct {
+	LastID int         `json:"lastId"`
+	Goals  []goal.Goal `json:"goals"`
+}
+
+func NewGoalRepository(path string) (secondary.GoalRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "creating directory: %w", err)
+	}
+	return &GoalRepository{filepath: path}, nil
+}
+
+func (r *GoalRepository) read() (goalsFile, error) {
+	var f goalsFile
+	data, err := os.ReadFile(r.filepath)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return f, rating.Errorf(rating.KindStorage, "reading goals: %w", err)
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, rating.Errorf(rating.KindStorage, "reading goals from %s: %w", r.filepath, err)
+	}
+	return f, nil
+}
+
+func (r *GoalRepository) write(f goalsFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return rating.Errorf(rating.KindStorage, "marshaling goals: %w", err)
+	}
+	if err := writeAtomic(r.filepath, data); err != nil {
+		return rating.Errorf(rating.KindStorage, "writing goals: %w", err)
+	}
+	return nil
+}
+
+func (r *GoalRepository) List(_ context.Context) ([]goal.Goal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := r.read()
+	return f.Goals, err
+}
+
+func (r *GoalRepository) Add(_ context.Context, g goal.Goal) (goal.Goal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := r.read()
+	if err != nil {
+		return goal.Goal{}, err
+	}
+	f.LastID++
+	g.ID = strconv.Itoa(f.LastID)
+	f.Goals = append(f.Goals, g)
+	if err := r.write(f); err != nil {
+		return goal.Goal{}, err
+	}
+	return g, nil
+}
+
+func (r *GoalRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := r.read()
+	if err != nil {
+		return err
+	}
+	for i, g := range f.Goals {
+		if g.ID == id {
+			f.Goals = append(f.Goals[:i], f.Goals[i+1:]...)
+			return r.write(f)
+		}
+	}
+	return goal.ErrNotFound
+}
@@ -0,0 +1,53 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"track/internal/track/domain/goal"
+)
+
+func TestGoalRepository(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "goals.json")
+	repo, err := NewGoalRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if goals, err := repo.List(ctx); err != nil || len(goals) != 0 {
+		t.Fatalf("List before any goal = %v, %v", goals, err)
+	}
+	for _, kind := range []goal.Kind{goal.KindAverage, goal.KindMaxBad} {
+		if _, err := repo.Add(ctx, goal.Goal{Kind: kind, Period: goal.PeriodWeek, Target: 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Delete(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	added, err := repo.Add(ctx, goal.Goal{Kind: goal.KindEveryDay, Period: goal.PeriodMonth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.ID != "3" {
+		t.Errorf("ID after a removal = %s, want 3 so IDs are not reused", added.ID)
+	}
+
+	reopened, err := NewGoalRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goals, err := reopened.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(goals) != 2 || goals[0].Kind != goal.KindMaxBad || goals[1].Kind != goal.KindEveryDay {
+		t.Errorf("reopened goals = %+v, want max-bad then every-day", goals)
+	}
+
+	if err := reopened.Delete(ctx, "1"); !errors.Is(err, goal.ErrNotFound) {
+		t.Errorf("Delete removed goal = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,10 @@
+// internal/adapters/secondary/file/lock_other.go
+//go:build !unix
+
+package file
+
+// lockFile locks nothing where flock is not available, leaving writers from
+// other processes to the revision check alone
+func lockFile(string) (unlock func(), err error) {
+	return func() {}, nil
+}
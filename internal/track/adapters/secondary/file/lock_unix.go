@@ -0,0 +1,24 @@
+// internal/adapters/secondary/file/lock_unix.go
+//go:build unix
+
+package file
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile holds an exclusive lock on a sidecar path.lock until unlock is called, so
+// processes sharing a file take turns reading it and writing it back
+func lockFile(path string) (unlock func(), err error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	// Closing the file releases the lock
+	return func() { f.Close() }, nil
+}
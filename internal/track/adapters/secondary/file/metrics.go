@@ -0,0 +1,118 @@
+// internal/adapters/secondary/file/metrics.go
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/series"
+	"track/internal/track/ports/secondary"
+)
+
+// MetricStore keeps metrics in a JSON file of values by date for each metric,
+// {"commits": {"2025-02-17": 4}}, read on every call like the goals
+type MetricStore struct {
+	mu       sync.Mutex
+	filepath string
+}
+
+var _ secondary.MetricStore = (*MetricStore)(nil)
+
+func NewMetricStore(path string) *MetricStore {
+	return &MetricStore{filepath: path}
+}
+
+type metricsFile map[string]map[string]float64
+
+func (s *MetricStore) read() (metricsFile, error) {
+	f := make(metricsFile)
+	data, err := os.ReadFile(s.filepath)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reading metrics: %w", err)
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reading metrics from %s: %w", s.filepath, err)
+	}
+	return f, nil
+}
+
+func (s *MetricStore) write(f metricsFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return rating.Errorf(rating.KindStorage, "marshaling metrics: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.filepath), 0755); err != nil {
+		return rating.Errorf(rating.KindStorage, "creating directory: %w", err)
+	}
+	if err := writeAtomic(s.filepath, data); err != nil {
+		return rating.Errorf(rating.KindStorage, "writing metrics: %w", err)
+	}
+	return nil
+}
+
+func (s *MetricStore) Put(_ context.Context, name string, start, end time.Time, values map[time.Time]float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+	days := f[name]
+	if days == nil {
+		days = make(map[string]float64)
+		f[name] = days
+	}
+	first, last := series.Day(start), series.Day(end)
+	for date := range days {
+		if day, err := time.Parse(time.DateOnly, date); err == nil && !day.Before(first) && !day.After(last) {
+			delete(days, date)
+		}
+	}
+	for day, v := range values {
+		if day = series.Day(day); !day.Before(first) && !day.After(last) {
+			days[day.Format(time.DateOnly)] = v
+		}
+	}
+	return s.write(f)
+}
+
+func (s *MetricStore) Get(_ context.Context, start, end time.Time) ([]series.Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	first, last := series.Day(start), series.Day(end)
+	var found []series.Series
+	for _, name := range names {
+		metric := series.Series{Name: name, Values: make(map[time.Time]float64)}
+		for date, v := range f[name] {
+			day, err := time.Parse(time.DateOnly, date)
+			if err != nil {
+				return nil, rating.Errorf(rating.KindStorage, "%s: %s has an invalid date %q", s.filepath, name, date)
+			}
+			if !day.Before(first) && !day.After(last) {
+				metric.Values[day] = v
+			}
+		}
+		found = append(found, metric)
+	}
+	return found, nil
+}
@@ -0,0 +1,41 @@
+package file
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// day is midnight UTC on the dth of February 2025
+func day(d int) time.Time {
+	return time.Date(2025, time.February, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestMetricStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMetricStore(filepath.Join(t.TempDir(), "metrics", "metrics.json"))
+
+	if err := store.Put(ctx, "commits", day(17), day(19), map[time.Time]float64{day(17): 4, day(18): 0, day(19): 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, "meetings", day(17), day(17), map[time.Time]float64{day(17): 3}); err != nil {
+		t.Fatal(err)
+	}
+	// A later import of the 18th on replaces those days and keeps the 17th
+	if err := store.Put(ctx, "commits", day(18), day(20), map[time.Time]float64{day(18): 5, day(20): 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := store.Get(ctx, day(17), day(19))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found[0].Name != "commits" || found[1].Name != "meetings" {
+		t.Fatalf("Get = %+v, want commits then meetings", found)
+	}
+	commits := found[0].Values
+	if len(commits) != 2 || commits[day(17)] != 4 || commits[day(18)] != 5 {
+		t.Errorf("commits = %v, want 4 on the 17th and 5 on the 18th, the 19th removed and the 20th out of range", commits)
+	}
+}
@@ -0,0 +1,73 @@
+// internal/adapters/secondary/file/promptcache.go
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// PromptCache keeps the segments rendered for one version of the ratings in a small JSON file
+type PromptCache struct {
+	filepath string
+}
+
+type promptCacheFile struct {
+	Version  string            `json:"version"`
+	Segments map[string]string `json:"segments"`
+}
+
+func NewPromptCache(path string) (secondary.PromptCache, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "creating directory: %w", err)
+	}
+	return &PromptCache{filepath: path}, nil
+}
+
+func (c *PromptCache) read() (promptCacheFile, error) {
+	var f promptCacheFile
+	data, err := os.ReadFile(c.filepath)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return f, rating.Errorf(rating.KindStorage, "reading prompt cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, rating.Errorf(rating.KindStorage, "reading prompt cache from %s: %w", c.filepath, err)
+	}
+	return f, nil
+}
+
+func (c *PromptCache) Get(_ context.Context, version, format string) (string, bool, error) {
+	f, err := c.read()
+	if err != nil || f.Version != version {
+		return "", false, err
+	}
+	segment, ok := f.Segments[format]
+	return segment, ok, nil
+}
+
+func (c *PromptCache) Put(_ context.Context, version, format, segment string) error {
+	f, err := c.read()
+	if err != nil || f.Version != version {
+		// Unreadable or stale, start over
+		f = promptCacheFile{Version: version}
+	}
+	if f.Segments == nil {
+		f.Segments = make(map[string]string)
+	}
+	f.Segments[format] = segment
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return rating.Errorf(rating.KindStorage, "marshaling prompt cache: %w", err)
+	}
+	if err := writeAtomic(c.filepath, data); err != nil {
+		return rating.Errorf(rating.KindStorage, "writing prompt cache: %w", err)
+	}
+	return nil
+}
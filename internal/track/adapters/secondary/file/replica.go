@@ -0,0 +1,274 @@
+// internal/adapters/secondary/file/replica.go
+package file
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	"track/internal/track/domain/crdt"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// ReplicaRepository stores the ratings as one device's copy of a last-writer-wins map,
+// so copies written on different devices merge without conflicts. The file is read on
+// every call, as other processes and merges may replace it.
+type ReplicaRepository struct {
+	mu       sync.Mutex
+	filepath string
+	now      func() time.Time
+}
+
+// replicaFile is the stored form. Clock is the latest stamp the device made or
+// merged in, so its next write is later than everything it has seen.
+type replicaFile struct {
+	Device  string         `json:"device"`
+	Clock   crdt.Timestamp `json:"clock"`
+	Entries crdt.Map       `json:"entries"`
+}
+
+var (
+	_ secondary.RatingRepository = (*ReplicaRepository)(nil)
+	_ secondary.Versioned        = (*ReplicaRepository)(nil)
+	_ secondary.Replica          = (*ReplicaRepository)(nil)
+	_ secondary.RecordReader     = (*ReplicaRepository)(nil)
+)
+
+// NewReplicaRepository stores ratings at path. A plain ratings file there is taken over
+// on the first write, each day stamped with when it was last updated.
+func NewReplicaRepository(path string) (*ReplicaRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "creating directory: %w", err)
+	}
+	return &ReplicaRepository{filepath: path, now: time.Now}, nil
+}
+
+// Version changes whenever the file is replaced
+func (r *ReplicaRepository) Version(_ context.Context) (string, error) {
+	return fileVersion(r.filepath)
+}
+
+func (r *ReplicaRepository) Save(_ context.Context, dr rating.DayRating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replica, err := r.read()
+	if err != nil {
+		return err
+	}
+	var stored int
+	if e, ok := replica.Entries[dr.ID]; ok && e.Day != nil {
+		stored = e.Day.Revision
+	}
+	if dr.Revision != stored+1 {
+		return fmt.Errorf("saving %s at revision %d over %d: %w", dr.ID, dr.Revision, stored, rating.ErrStaleRevision)
+	}
+	return r.write(replica, dr.ID, &dr)
+}
+
+// Delete leaves a tombstone, so merges don't bring the day back from other devices
+func (r *ReplicaRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replica, err := r.read()
+	if err != nil {
+		return err
+	}
+	if e, ok := replica.Entries[id]; !ok || e.Day == nil {
+		return rating.ErrNotFound
+	}
+	return r.write(replica, id, nil)
+}
+
+func (r *ReplicaRepository) GetByID(_ context.Context, id string) (rating.DayRating, error) {
+	ratings, err := r.ratings()
+	if err != nil {
+		return rating.DayRating{}, err
+	}
+	dr, ok := ratings[id]
+	if !ok {
+		return rating.DayRating{}, rating.ErrNotFound
+	}
+	return dr, nil
+}
+
+// Records returns the days that are not deleted, by the key each is stored under
+func (r *ReplicaRepository) Records(_ context.Context) (map[string]rating.DayRating, error) {
+	return r.ratings()
+}
+
+func (r *ReplicaRepository) GetByDateRange(_ context.Context, start, end time.Time) ([]rating.DayRating, error) {
+	ratings, err := r.ratings()
+	if err != nil {
+		return nil, err
+	}
+	var results []rating.DayRating
+	for _, dr := range ratings {
+		if !dr.Date.Before(start) && !dr.Date.After(end) {
+			results = append(results, dr)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Date.Before(results[j].Date) })
+	return results, nil
+}
+
+func (r *ReplicaRepository) GetByWeek(_ context.Context, year, week int) ([]rating.DayRating, error) {
+	ratings, err := r.ratings()
+	if err != nil {
+		return nil, err
+	}
+	var results []rating.DayRating
+	for _, dr := range ratings {
+		if y, w := dr.Date.ISOWeek(); y == year && w == week {
+			results = append(results, dr)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Date.Before(results[j].Date) })
+	return results, nil
+}
+
+// MergeFile merges another device's ratings file, or a plain ratings file, into this one
+func (r *ReplicaRepository) MergeFile(_ context.Context, path string) ([]crdt.Change, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reading %s: %w", path, err)
+	}
+	other, err := decodeReplica(data)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindValidation, "%s is not a ratings file: %w", path, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replica, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	merged := crdt.Merge(replica.Entries, other.Entries)
+	changes := crdt.Changes(replica.Entries, merged)
+	clock := crdt.Latest(replica.Clock, merged.Latest())
+	if len(changes) == 0 && clock == replica.Clock {
+		return nil, nil
+	}
+	replica.Entries, replica.Clock = merged, clock
+	if err := r.save(replica); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (r *ReplicaRepository) ratings() (map[string]rating.DayRating, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replica, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	return replica.Entries.Ratings(), nil
+}
+
+// read loads the file, starting a new device's copy when there is none yet.
+// The caller must hold the lock.
+func (r *ReplicaRepository) read() (replicaFile, error) {
+	data, err := os.ReadFile(r.filepath)
+	if os.IsNotExist(err) {
+		return replicaFile{Entries: make(crdt.Map)}, nil
+	}
+	if err != nil {
+		return replicaFile{}, rating.Errorf(rating.KindStorage, "reading ratings: %w", err)
+	}
+	replica, err := decodeReplica(data)
+	if err != nil {
+		return replicaFile{}, rating.Errorf(rating.KindStorage, "loading ratings from %s: %w", r.filepath, err)
+	}
+	return replica, nil
+}
+
+// write records a save or, with a nil day, a delete of id, the caller must hold the lock
+func (r *ReplicaRepository) write(replica replicaFile, id string, day *rating.DayRating) error {
+	if replica.Device == "" {
+		device, err := newDeviceID()
+		if err != nil {
+			return rating.Errorf(rating.KindStorage, "creating a device ID: %w", err)
+		}
+		replica.Device = device
+	}
+	replica.Clock = crdt.Latest(replica.Clock, replica.Entries.Latest()).Next(r.now(), replica.Device)
+	replica.Entries.Set(id, replica.Clock, day)
+	return r.save(replica)
+}
+
+func (r *ReplicaRepository) save(replica replicaFile) error {
+	data, err := json.MarshalIndent(replica, "", "  ")
+	if err != nil {
+		return rating.Errorf(rating.KindStorage, "marshaling ratings: %w", err)
+	}
+	if err := writeAtomic(r.filepath, data); err != nil {
+		return rating.Errorf(rating.KindStorage, "writing ratings: %w", err)
+	}
+	return nil
+}
+
+// decodeReplica reads a replica file, or a plain ratings file as a replica with no device
+// yet, whose days are stamped with their update time
+func decodeReplica(data []byte) (replicaFile, error) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return replicaFile{}, err
+	}
+	if _, ok := keys["entries"]; ok {
+		var replica replicaFile
+		if err := json.Unmarshal(data, &replica); err != nil {
+			return replicaFile{}, err
+		}
+		if replica.Entries == nil {
+			replica.Entries = make(crdt.Map)
+		}
+		return replica, nil
+	}
+
+	ratings, err := DecodeRatings(data)
+	if err != nil {
+		return replicaFile{}, err
+	}
+	replica := replicaFile{Entries: make(crdt.Map, len(ratings))}
+	for id, dr := range ratings {
+		var wall int64
+		if !dr.UpdatedAt.IsZero() {
+			wall = dr.UpdatedAt.UnixNano()
+		}
+		replica.Entries.Set(id, crdt.Timestamp{Wall: wall}, &dr)
+	}
+	return replica, nil
+}
+
+// newDeviceID names a device in the stamps of its writes
+func newDeviceID() (string, error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// fileVersion identifies a file's current content without reading it, writes replacing the file
+func fileVersion(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "empty", nil
+	}
+	if err != nil {
+		return "", rating.Errorf(rating.KindStorage, "checking ratings file: %w", err)
+	}
+	return fmt.Sprintf("%d-%d", fi.Size(), fi.ModTime().UnixNano()), nil
+}
@@ -0,0 +1,104 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/repotest"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+func TestReplicaRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) secondary.RatingRepository {
+		repo, err := NewReplicaRepository(filepath.Join(t.TempDir(), "ratings.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	})
+}
+
+func TestReplicaMergeFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	newReplica := func(name string, now time.Time) *ReplicaRepository {
+		t.Helper()
+		repo, err := NewReplicaRepository(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo.now = func() time.Time { return now }
+		return repo
+	}
+	// The phone's clock is ahead, the laptop's writes must still win once it has seen the phone's
+	phone := newReplica("phone.json", time.Date(2025, time.February, 18, 12, 0, 0, 0, time.UTC))
+	laptop := newReplica("laptop.json", time.Date(2025, time.February, 18, 9, 0, 0, 0, time.UTC))
+
+	monday := repotest.Day(2025, time.February, 17, rating.Good)
+	tuesday := repotest.Day(2025, time.February, 18, rating.Fair)
+	if err := phone.Save(ctx, monday); err != nil {
+		t.Fatal(err)
+	}
+	if err := phone.Save(ctx, tuesday); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := laptop.MergeFile(ctx, phone.filepath)
+	if err != nil || len(changes) != 2 || changes[0].Before != nil {
+		t.Fatalf("first merge = %+v, %v, want both days added", changes, err)
+	}
+	bad := monday
+	bad.Rating, bad.Revision = rating.Bad, 2
+	if err := laptop.Save(ctx, bad); err != nil {
+		t.Fatal(err)
+	}
+	if err := laptop.Delete(ctx, tuesday.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := phone.MergeFile(ctx, laptop.filepath); err != nil {
+		t.Fatal(err)
+	}
+	for name, repo := range map[string]*ReplicaRepository{"phone": phone, "laptop": laptop} {
+		if got, err := repo.GetByID(ctx, monday.ID); err != nil || got.Rating != rating.Bad {
+			t.Errorf("%s's Monday = %v, %v, want the laptop's later Bad", name, got.Rating, err)
+		}
+		if _, err := repo.GetByID(ctx, tuesday.ID); !errors.Is(err, rating.ErrNotFound) {
+			t.Errorf("%s's Tuesday = %v, want deleted", name, err)
+		}
+	}
+	if changes, err := laptop.MergeFile(ctx, phone.filepath); err != nil || len(changes) != 0 {
+		t.Errorf("merging an already merged copy = %+v, %v, want no changes", changes, err)
+	}
+}
+
+func TestReplicaTakesOverPlainFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	plain, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	monday := repotest.Day(2025, time.February, 17, rating.Good)
+	if err := plain.Save(ctx, monday); err != nil {
+		t.Fatal(err)
+	}
+
+	replica, err := NewReplicaRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := replica.GetByID(ctx, monday.ID); err != nil || got.Rating != rating.Good {
+		t.Fatalf("plain rating read as %v, %v", got.Rating, err)
+	}
+	tuesday := repotest.Day(2025, time.February, 18, rating.Fair)
+	if err := replica.Save(ctx, tuesday); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := replica.GetByWeek(ctx, 2025, 8); err != nil || len(got) != 2 {
+		t.Errorf("week after the first replica write = %v, %v, want both days", got, err)
+	}
+}
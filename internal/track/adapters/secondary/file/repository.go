@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"sort"
@@ -14,17 +15,23 @@ import (
 	"track/internal/track/ports/secondary"
 )
 
+// FileRepository keeps the ratings in memory and writes them all back on every change.
+// Other processes (a CLI next to `track serve`) may replace the file at any time, so
+// each call first reloads the file if it is not the one last read or written. Writers
+// hold a lock on path.lock from that reload until they have written, so another
+// process's change in between is never lost.
 type FileRepository struct {
 	mu       sync.RWMutex
 	filepath string
 	ratings  map[string]rating.DayRating
+	loaded   os.FileInfo
 }
 
 func NewFileRepository(path string) (secondary.RatingRepository, error) {
 	// Ensure directory exists
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("creating directory: %w", err)
+		return nil, rating.Errorf(rating.KindStorage, "creating directory: %w", err)
 	}
 
 	repo := &FileRepository{
@@ -33,46 +40,236 @@ func NewFileRepository(path string) (secondary.RatingRepository, error) {
 	}
 
 	// Load existing data if file exists
-	if err := repo.load(); err != nil && !os.IsNotExist(err) {
-		return nil, fmt.Errorf("loading ratings: %w", err)
+	if err := repo.load(); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "loading ratings from %s: %w", path, err)
 	}
 
 	return repo, nil
 }
 
+// OpenFileRepository is NewFileRepository reading the file on first use rather than now,
+// for commands that can often answer without it
+func OpenFileRepository(path string) (*FileRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "creating directory: %w", err)
+	}
+	return &FileRepository{
+		filepath: path,
+		ratings:  make(map[string]rating.DayRating),
+	}, nil
+}
+
+// Version identifies the file's current content without reading it: it changes on
+// every write, ours or another process's, since writes replace the file
+func (r *FileRepository) Version(_ context.Context) (string, error) {
+	return fileVersion(r.filepath)
+}
+
 func (r *FileRepository) load() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	// Reloading may write the file back re-keyed
+	unlock, err := lockFile(r.filepath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return r.reload()
+}
+
+// reload reads the file if it changed since it was last read or written, the caller must hold the write lock
+func (r *FileRepository) reload() error {
+	fi, err := os.Stat(r.filepath)
+	if os.IsNotExist(err) {
+		// Not written yet, or removed behind our back
+		if r.loaded != nil {
+			r.ratings = make(map[string]rating.DayRating)
+			r.loaded = nil
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if unchanged(fi, r.loaded) {
+		return nil
+	}
+
 	data, err := os.ReadFile(r.filepath)
 	if err != nil {
 		return err
 	}
+	ratings := make(map[string]rating.DayRating)
+	if err := json.Unmarshal(data, &ratings); err != nil {
+		return err
+	}
+	r.ratings = ratings
+	r.loaded = fi
+	if rekeyLegacyIDs(ratings) {
+		// Written once, files saved since are keyed by the current IDs
+		return r.save()
+	}
+	return nil
+}
+
+// refresh reloads the file for readers when another process has replaced it
+func (r *FileRepository) refresh() error {
+	r.mu.RLock()
+	loaded := r.loaded
+	r.mu.RUnlock()
+
+	fi, err := os.Stat(r.filepath)
+	if (err == nil && unchanged(fi, loaded)) || (os.IsNotExist(err) && loaded == nil) {
+		return nil
+	}
+	return r.load()
+}
 
-	return json.Unmarshal(data, &r.ratings)
+// unchanged reports whether fi is still the file last read or written. Writes replace
+// the file by rename, so a new inode or timestamp means someone else wrote it.
+func unchanged(fi, loaded os.FileInfo) bool {
+	return loaded != nil && os.SameFile(fi, loaded) && fi.ModTime().Equal(loaded.ModTime()) && fi.Size() == loaded.Size()
 }
 
+// save writes the ratings back, the caller must hold the write lock
 func (r *FileRepository) save() error {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	if err := WriteRatings(r.filepath, r.ratings); err != nil {
+		return err
+	}
+	if fi, err := os.Stat(r.filepath); err == nil {
+		r.loaded = fi
+	}
+	return nil
+}
+
+// DecodeRatings reads the content of a ratings file, re-keying days stored under their legacy ID
+func DecodeRatings(data []byte) (map[string]rating.DayRating, error) {
+	ratings := make(map[string]rating.DayRating)
+	if err := json.Unmarshal(data, &ratings); err != nil {
+		return nil, err
+	}
+	rekeyLegacyIDs(ratings)
+	return ratings, nil
+}
+
+// legacyDayID is the ID days were keyed by before DayID used the ISO year: the calendar
+// year with the ISO week, so 2024-12-30 was 24w01-1 like 2024-01-01
+func legacyDayID(date time.Time) string {
+	_, week := date.ISOWeek()
+	return fmt.Sprintf("%sw%02d-%d", date.Format("06"), week, date.Weekday())
+}
+
+// rekeyLegacyIDs moves days stored under their legacy ID to their current one and
+// reports whether it moved any. Only days around the new year differ, and a legacy ID
+// can be another day's current one (2025-12-29 was 25w01-1 like 2024-12-30 is now),
+// so it goes round until nothing moves rather than overwrite a day.
+func rekeyLegacyIDs(ratings map[string]rating.DayRating) bool {
+	moved := false
+	for {
+		var legacy []string
+		for key, dr := range ratings {
+			if key == dr.ID && key != rating.DayID(dr.Date) && key == legacyDayID(dr.Date) {
+				legacy = append(legacy, key)
+			}
+		}
+
+		progress := false
+		for _, key := range legacy {
+			dr := ratings[key]
+			dr.ID = rating.DayID(dr.Date)
+			if _, taken := ratings[dr.ID]; taken {
+				continue
+			}
+			delete(ratings, key)
+			ratings[dr.ID] = dr
+			progress = true
+		}
+		if !progress {
+			return moved
+		}
+		moved = true
+	}
+}
 
-	data, err := json.MarshalIndent(r.ratings, "", "  ")
+// WriteRatings replaces the ratings file at path, for this repository and for tools
+// that rewrite the whole file, such as a sync merge
+func WriteRatings(path string, ratings map[string]rating.DayRating) error {
+	data, err := json.MarshalIndent(ratings, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshaling ratings: %w", err)
+		return rating.Errorf(rating.KindStorage, "marshaling ratings: %w", err)
 	}
+	if err := writeAtomic(path, data); err != nil {
+		return rating.Errorf(rating.KindStorage, "writing ratings: %w", err)
+	}
+	return nil
+}
 
-	return os.WriteFile(r.filepath, data, 0644)
+// writeAtomic replaces the file at path through a temp file and rename, so readers never see half of it
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
 func (r *FileRepository) Save(_ context.Context, dr rating.DayRating) error {
 	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unlock, err := lockFile(r.filepath)
+	if err != nil {
+		return rating.Errorf(rating.KindStorage, "locking ratings: %w", err)
+	}
+	defer unlock()
+	if err := r.reload(); err != nil {
+		return rating.Errorf(rating.KindStorage, "reloading ratings: %w", err)
+	}
+	if stored := r.ratings[dr.ID].Revision; dr.Revision != stored+1 {
+		return fmt.Errorf("saving %s at revision %d over %d: %w", dr.ID, dr.Revision, stored, rating.ErrStaleRevision)
+	}
 	r.ratings[dr.ID] = dr
-	r.mu.Unlock()
+	return r.save()
+}
+
+func (r *FileRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
+	unlock, err := lockFile(r.filepath)
+	if err != nil {
+		return rating.Errorf(rating.KindStorage, "locking ratings: %w", err)
+	}
+	defer unlock()
+	if err := r.reload(); err != nil {
+		return rating.Errorf(rating.KindStorage, "reloading ratings: %w", err)
+	}
+	if _, exists := r.ratings[id]; !exists {
+		return rating.ErrNotFound
+	}
+	delete(r.ratings, id)
 	return r.save()
 }
 
 func (r *FileRepository) GetByID(_ context.Context, id string) (rating.DayRating, error) {
+	if err := r.refresh(); err != nil {
+		return rating.DayRating{}, rating.Errorf(rating.KindStorage, "reloading ratings: %w", err)
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -84,7 +281,23 @@ func (r *FileRepository) GetByID(_ context.Context, id string) (rating.DayRating
 	return dr, nil
 }
 
+// Records returns the ratings as stored, by the key each is stored under
+func (r *FileRepository) Records(_ context.Context) (map[string]rating.DayRating, error) {
+	if err := r.refresh(); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reloading ratings: %w", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return maps.Clone(r.ratings), nil
+}
+
 func (r *FileRepository) GetByDateRange(_ context.Context, start, end time.Time) ([]rating.DayRating, error) {
+	if err := r.refresh(); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reloading ratings: %w", err)
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -95,11 +308,16 @@ func (r *FileRepository) GetByDateRange(_ context.Context, start, end time.Time)
 			results = append(results, dr)
 		}
 	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Date.Before(results[j].Date) })
 
 	return results, nil
 }
 
 func (r *FileRepository) GetByWeek(_ context.Context, year, week int) ([]rating.DayRating, error) {
+	if err := r.refresh(); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reloading ratings: %w", err)
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -110,7 +328,7 @@ func (r *FileRepository) GetByWeek(_ context.Context, year, week int) ([]rating.
 			results = append(results, dr)
 		}
 	}
-	sort.Slice(results, func(i, j int) bool { return results[i].Label() < results[j].Label() })
+	sort.Slice(results, func(i, j int) bool { return results[i].Date.Before(results[j].Date) })
 
 	return results, nil
 }
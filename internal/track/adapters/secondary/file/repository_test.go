@@ -0,0 +1,194 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/repotest"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+func TestFileRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) secondary.RatingRepository {
+		repo, err := NewFileRepository(filepath.Join(t.TempDir(), "ratings.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	})
+}
+
+func TestFileRepositoryReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "ratings.json")
+	repo, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := repotest.Day(2025, time.February, 17, rating.Good)
+	if err := repo.Save(context.Background(), want); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reopened.GetByID(context.Background(), want.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Date.Equal(want.Date) || got.Rating != want.Rating {
+		t.Errorf("reloaded %+v, want %+v", got, want)
+	}
+}
+
+func TestFileRepositorySharedFile(t *testing.T) {
+	// Two repositories on one file stand in for the CLI and a running server
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	cli, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	first := repotest.Day(2025, time.February, 17, rating.Good)
+	if err := cli.Save(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	seen, err := server.GetByID(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("server does not see the CLI's write: %v", err)
+	}
+
+	update := seen
+	update.Rating, update.Revision = rating.Bad, seen.Revision+1
+	if err := cli.Save(ctx, update); err != nil {
+		t.Fatal(err)
+	}
+
+	// The server still holds revision 1, its write must not clobber the CLI's
+	stale := seen
+	stale.Rating, stale.Revision = rating.Awesome, seen.Revision+1
+	if err := server.Save(ctx, stale); !errors.Is(err, rating.ErrStaleRevision) {
+		t.Errorf("stale Save = %v, want ErrStaleRevision", err)
+	}
+	got, err := server.GetByID(ctx, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Rating != rating.Bad || got.Revision != 2 {
+		t.Errorf("server reads %v revision %d, want Bad revision 2", got.Rating, got.Revision)
+	}
+}
+
+func TestFileRepositoryConcurrentWriters(t *testing.T) {
+	// Each rates the same days first, as the CLI and a server might at once
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	var repos []secondary.RatingRepository
+	for range 2 {
+		repo, err := NewFileRepository(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repos = append(repos, repo)
+	}
+	ctx := context.Background()
+
+	const days = 20
+	saved := make([][]rating.DayRating, len(repos))
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := 1; d <= days; d++ {
+				dr := repotest.Day(2025, time.February, d, rating.Rating(i+1))
+				if err := repo.Save(ctx, dr); err == nil {
+					saved[i] = append(saved[i], dr)
+				} else if !errors.Is(err, rating.ErrStaleRevision) {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every day has one first revision, the one whose Save succeeded
+	if n := len(saved[0]) + len(saved[1]); n != days {
+		t.Fatalf("%d first revisions saved for %d days, want one each", n, days)
+	}
+	for _, dr := range append(saved[0], saved[1]...) {
+		got, err := repos[0].GetByID(ctx, dr.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Rating != dr.Rating {
+			t.Errorf("%s is %v, want %v as saved", dr.ID, got.Rating, dr.Rating)
+		}
+	}
+}
+
+func TestFileRepositoryCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileRepository(path); err == nil {
+		t.Error("expected an error loading a corrupt file")
+	}
+}
+
+func TestFileRepositoryRekeysLegacyIDs(t *testing.T) {
+	// Written before DayID used the ISO year: both days are keyed by the calendar year
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	december := repotest.Day(2024, time.December, 30, rating.Good)
+	december.ID = "24w01-1"
+	nextDecember := repotest.Day(2025, time.December, 29, rating.Bad)
+	nextDecember.ID = "25w01-1"
+	if err := WriteRatings(path, map[string]rating.DayRating{december.ID: december, nextDecember.ID: nextDecember}); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := NewFileRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for id, want := range map[string]time.Time{"25w01-1": december.Date, "26w01-1": nextDecember.Date} {
+		got, err := repo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("%s: %v", id, err)
+		}
+		if got.ID != id || !got.Date.Equal(want) {
+			t.Errorf("%s holds %s (%s), want %s", id, got.ID, got.Date.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+	}
+	if _, err := repo.GetByID(ctx, "24w01-1"); !errors.Is(err, rating.ErrNotFound) {
+		t.Errorf("the legacy ID is still stored: %v", err)
+	}
+
+	// The file was rewritten, so 2024-01-01 can take its ID back
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := DecodeRatings(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stored["24w01-1"]; ok {
+		t.Error("the file still keys 2024-12-30 by its legacy ID")
+	}
+	if err := repo.Save(ctx, repotest.Day(2024, time.January, 1, rating.Fair)); err != nil {
+		t.Fatal(err)
+	}
+}
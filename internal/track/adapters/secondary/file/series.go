@@ -0,0 +1,201 @@
+// internal/adapters/secondary/file/series.go
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/series"
+	"track/internal/track/ports/secondary"
+)
+
+// SeriesFiles reads series from CSV files, with a header naming the columns, and from
+// JSON files, either an array of objects or an object of values by date. The date is
+// the column or field named date or day, or the first column, as YYYY-MM-DD or
+// RFC 3339. Every other column whose values are all numbers is a series, and values
+// on the same day are added up.
+type SeriesFiles struct{}
+
+var _ secondary.SeriesReader = SeriesFiles{}
+
+func (SeriesFiles) Read(_ context.Context, path string) ([]series.Series, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reading %s: %w", path, err)
+	}
+	var found []series.Series
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		found, err = decodeSeriesJSON(data, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	default:
+		found, err = decodeSeriesCSV(data)
+	}
+	if err != nil {
+		return nil, rating.Errorf(rating.KindValidation, "%s: %w", path, err)
+	}
+	if len(found) == 0 {
+		return nil, rating.Errorf(rating.KindValidation, "%s has no numeric columns", path)
+	}
+	return found, nil
+}
+
+// table collects the cells of each column by date, before deciding which columns are series
+type table struct {
+	names   []string
+	columns map[string]*column
+}
+
+type column struct {
+	values  map[time.Time]float64
+	numeric bool
+}
+
+func newTable() *table {
+	return &table{columns: make(map[string]*column)}
+}
+
+func (t *table) add(name string, day time.Time, value any) {
+	c, ok := t.columns[name]
+	if !ok {
+		c = &column{values: make(map[time.Time]float64), numeric: true}
+		t.columns[name] = c
+		t.names = append(t.names, name)
+	}
+	switch v := value.(type) {
+	case float64:
+		c.values[day] += v
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			c.numeric = false
+			return
+		}
+		c.values[day] += f
+	case nil:
+	default:
+		c.numeric = false
+	}
+}
+
+func (t *table) series() []series.Series {
+	var found []series.Series
+	for _, name := range t.names {
+		if c := t.columns[name]; c.numeric && len(c.values) > 0 {
+			found = append(found, series.Series{Name: name, Values: c.values})
+		}
+	}
+	return found
+}
+
+func decodeSeriesCSV(data []byte) ([]series.Series, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, rating.Errorf(rating.KindValidation, "reading the header: %w", err)
+	}
+	dateColumn := 0
+	for i, name := range header {
+		if isDateName(name) {
+			dateColumn = i
+			break
+		}
+	}
+
+	t := newTable()
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if dateColumn >= len(record) || strings.TrimSpace(record[dateColumn]) == "" {
+			continue
+		}
+		line, _ := r.FieldPos(dateColumn)
+		day, err := parseSeriesDate(record[dateColumn])
+		if err != nil {
+			return nil, rating.Errorf(rating.KindValidation, "line %d: %w", line, err)
+		}
+		for i, cell := range record {
+			if i != dateColumn && i < len(header) {
+				t.add(strings.TrimSpace(header[i]), day, cell)
+			}
+		}
+	}
+	return t.series(), nil
+}
+
+func decodeSeriesJSON(data []byte, name string) ([]series.Series, error) {
+	var rows []map[string]any
+	if err := json.Unmarshal(data, &rows); err == nil {
+		t := newTable()
+		for i, row := range rows {
+			var date string
+			for key, v := range row {
+				if s, ok := v.(string); ok && isDateName(key) {
+					date = s
+				}
+			}
+			if date == "" {
+				return nil, rating.Errorf(rating.KindValidation, "item %d has no date", i+1)
+			}
+			day, err := parseSeriesDate(date)
+			if err != nil {
+				return nil, rating.Errorf(rating.KindValidation, "item %d: %w", i+1, err)
+			}
+			for _, key := range slices.Sorted(maps.Keys(row)) {
+				if !isDateName(key) {
+					t.add(key, day, row[key])
+				}
+			}
+		}
+		return t.series(), nil
+	}
+
+	var byDate map[string]float64
+	if err := json.Unmarshal(data, &byDate); err != nil {
+		return nil, rating.Errorf(rating.KindValidation, "expected an array of objects or an object of numbers by date")
+	}
+	s := series.Series{Name: name, Values: make(map[time.Time]float64, len(byDate))}
+	for date, v := range byDate {
+		day, err := parseSeriesDate(date)
+		if err != nil {
+			return nil, err
+		}
+		s.Values[day] += v
+	}
+	return []series.Series{s}, nil
+}
+
+func isDateName(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return name == "date" || name == "day"
+}
+
+// parseSeriesDate reads a date, or a time whose date in its own zone is the day
+func parseSeriesDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.DateOnly, time.RFC3339, time.DateTime} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return series.Day(t), nil
+		}
+	}
+	return time.Time{}, rating.Errorf(rating.KindValidation, "invalid date %q, expected YYYY-MM-DD", value)
+}
@@ -0,0 +1,53 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+func TestSeriesFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	monday := time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC)
+
+	// The date column found by name, a text column left out, two commits on one day added up
+	csv := write("log.csv", "commits,day,message\n1,2025-02-17,fix\n1,2025-02-17T18:30:00+01:00,docs\n,2025-02-18,\n")
+	found, err := SeriesFiles{}.Read(ctx, csv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Name != "commits" || found[0].Values[monday] != 2 || len(found[0].Values) != 1 {
+		t.Errorf("Read(csv) = %+v, want commits of 2 on the 17th", found)
+	}
+
+	rows := write("health.json", `[{"date": "2025-02-17", "sleep": 7.5, "steps": 8200, "source": "watch"}]`)
+	if found, err := (SeriesFiles{}).Read(ctx, rows); err != nil || len(found) != 2 || found[0].Name != "sleep" || found[1].Values[monday] != 8200 {
+		t.Errorf("Read(array) = %+v, %v, want sleep and steps", found, err)
+	}
+	byDate := write("mood.json", `{"2025-02-17": 3, "2025-02-18": 4}`)
+	if found, err := (SeriesFiles{}).Read(ctx, byDate); err != nil || len(found) != 1 || found[0].Name != "mood" || len(found[0].Values) != 2 {
+		t.Errorf("Read(object) = %+v, %v, want mood named after the file", found, err)
+	}
+
+	for name, content := range map[string]string{
+		"bad-date.csv": "date,sleep\nyesterday,7\n",
+		"text.csv":     "date,note\n2025-02-17,tired\n",
+		"bad.json":     `"sleep"`,
+	} {
+		if _, err := (SeriesFiles{}).Read(ctx, write(name, content)); rating.KindOf(err) != rating.KindValidation {
+			t.Errorf("Read(%s) = %v, want a validation error", name, err)
+		}
+	}
+}
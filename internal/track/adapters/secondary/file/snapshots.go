@@ -0,0 +1,217 @@
+// internal/adapters/secondary/file/snapshots.go
+package file
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// Snapshots copies the ratings file into a directory, as <id>.json next to a
+// <id>.json.sha256 checksum that sha256sum -c can check too
+type Snapshots struct {
+	dir    string
+	source string
+	policy backup.Policy
+	now    func() time.Time
+}
+
+var _ secondary.SnapshotStore = (*Snapshots)(nil)
+
+// NewSnapshots keeps snapshots of the ratings file at source in dir, pruned by policy.
+// The directory is created with the first snapshot.
+func NewSnapshots(dir, source string, policy backup.Policy) *Snapshots {
+	return &Snapshots{dir: dir, source: source, policy: policy, now: time.Now}
+}
+
+func (s *Snapshots) Create(ctx context.Context) (backup.Snapshot, error) {
+	data, err := os.ReadFile(s.source)
+	if os.IsNotExist(err) {
+		return backup.Snapshot{}, rating.Errorf(rating.KindNotFound, "nothing to back up, %s doesn't exist yet", s.source)
+	}
+	if err != nil {
+		return backup.Snapshot{}, rating.Errorf(rating.KindStorage, "reading ratings: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return backup.Snapshot{}, rating.Errorf(rating.KindStorage, "creating backup directory: %w", err)
+	}
+	taken := s.now().UTC().Truncate(time.Second)
+	// Snapshots taken within the same second are one apart
+	for s.exists(taken.Format(backup.IDLayout)) {
+		taken = taken.Add(time.Second)
+	}
+	id := taken.Format(backup.IDLayout)
+	sum := sha256.Sum256(data)
+	if err := writeAtomic(s.path(id), data); err != nil {
+		return backup.Snapshot{}, rating.Errorf(rating.KindStorage, "writing snapshot: %w", err)
+	}
+	if err := writeAtomic(s.path(id)+".sha256", []byte(hex.EncodeToString(sum[:])+"  "+id+".json\n")); err != nil {
+		return backup.Snapshot{}, rating.Errorf(rating.KindStorage, "writing snapshot checksum: %w", err)
+	}
+
+	if err := s.prune(ctx); err != nil {
+		return backup.Snapshot{}, err
+	}
+	return backup.Snapshot{ID: id, Taken: taken, Size: int64(len(data))}, nil
+}
+
+func (s *Snapshots) List(_ context.Context) ([]backup.Snapshot, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "listing snapshots: %w", err)
+	}
+	var snapshots []backup.Snapshot
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		taken, err := time.Parse(backup.IDLayout, id)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, backup.Snapshot{ID: id, Taken: taken, Size: info.Size()})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Taken.After(snapshots[j].Taken) })
+	return snapshots, nil
+}
+
+func (s *Snapshots) Records(_ context.Context, id string) (map[string]rating.DayRating, error) {
+	data, err := s.read(id)
+	if err != nil {
+		return nil, err
+	}
+	replica, err := decodeReplica(data)
+	if err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reading snapshot %s: %w", id, err)
+	}
+	return replica.Entries.Ratings(), nil
+}
+
+func (s *Snapshots) Verify(_ context.Context, id string) error {
+	data, err := s.read(id)
+	if err != nil {
+		return err
+	}
+	line, err := os.ReadFile(s.path(id) + ".sha256")
+	if err != nil {
+		return rating.Errorf(rating.KindStorage, "snapshot %s has no checksum: %w", id, err)
+	}
+	sum := sha256.Sum256(data)
+	if want, _, _ := strings.Cut(string(line), " "); want != hex.EncodeToString(sum[:]) {
+		return rating.Errorf(rating.KindStorage, "snapshot %s doesn't match its checksum", id)
+	}
+	if _, err := decodeReplica(data); err != nil {
+		return rating.Errorf(rating.KindStorage, "reading snapshot %s: %w", id, err)
+	}
+	return nil
+}
+
+func (s *Snapshots) Restore(ctx context.Context, id string) (backup.Snapshot, error) {
+	data, err := s.read(id)
+	if err != nil {
+		return backup.Snapshot{}, err
+	}
+	undo, err := s.Create(ctx)
+	if err != nil && rating.KindOf(err) != rating.KindNotFound {
+		return backup.Snapshot{}, fmt.Errorf("taking a snapshot before restoring: %w", err)
+	}
+	if err := writeAtomic(s.source, data); err != nil {
+		return backup.Snapshot{}, rating.Errorf(rating.KindStorage, "restoring snapshot %s: %w", id, err)
+	}
+	return undo, nil
+}
+
+// Due takes a snapshot unless one was taken today, before the day's first change
+func (s *Snapshots) Due(ctx context.Context) error {
+	snapshots, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) > 0 && !snapshots[0].Taken.Before(rating.DayStart(s.now())) {
+		return nil
+	}
+	if _, err := s.Create(ctx); err != nil && rating.KindOf(err) != rating.KindNotFound {
+		return err
+	}
+	return nil
+}
+
+// prune deletes the snapshots the policy doesn't keep
+func (s *Snapshots) prune(ctx context.Context) error {
+	snapshots, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+	_, drop := backup.Keep(snapshots, s.policy, s.now().Location())
+	for _, snapshot := range drop {
+		for _, path := range []string{s.path(snapshot.ID), s.path(snapshot.ID) + ".sha256"} {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return rating.Errorf(rating.KindStorage, "pruning snapshot %s: %w", snapshot.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Snapshots) read(id string) ([]byte, error) {
+	if _, err := time.Parse(backup.IDLayout, id); err != nil {
+		return nil, backup.ErrNotFound
+	}
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, backup.ErrNotFound
+	}
+	if err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reading snapshot %s: %w", id, err)
+	}
+	return data, nil
+}
+
+func (s *Snapshots) exists(id string) bool {
+	_, err := os.Stat(s.path(id))
+	return err == nil
+}
+
+func (s *Snapshots) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// SnapshotRepository takes the day's first snapshot before the day's first change.
+// Snapshots are a safety net, so failing to take one doesn't stop the change.
+type SnapshotRepository struct {
+	secondary.RatingRepository
+	snapshots *Snapshots
+}
+
+func NewSnapshotRepository(repo secondary.RatingRepository, snapshots *Snapshots) *SnapshotRepository {
+	return &SnapshotRepository{RatingRepository: repo, snapshots: snapshots}
+}
+
+func (r *SnapshotRepository) Save(ctx context.Context, dr rating.DayRating) error {
+	_ = r.snapshots.Due(ctx)
+	return r.RatingRepository.Save(ctx, dr)
+}
+
+func (r *SnapshotRepository) Delete(ctx context.Context, id string) error {
+	_ = r.snapshots.Due(ctx)
+	return r.RatingRepository.Delete(ctx, id)
+}
@@ -0,0 +1,115 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/repotest"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/rating"
+)
+
+func TestSnapshots(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	source := filepath.Join(dir, "ratings.json")
+	repo, err := NewFileRepository(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots := NewSnapshots(filepath.Join(dir, "backups"), source, backup.Policy{Daily: 2})
+	if _, err := snapshots.Create(ctx); rating.KindOf(err) != rating.KindNotFound {
+		t.Errorf("Create without a ratings file = %v, want not found", err)
+	}
+
+	monday := repotest.Day(2025, time.February, 17, rating.Good)
+	if err := repo.Save(ctx, monday); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, time.February, 17, 21, 0, 0, 0, time.UTC)
+	snapshots.now = func() time.Time { return now }
+	first, err := snapshots.Create(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, err := snapshots.Create(ctx)
+	if err != nil || latest.ID == first.ID {
+		t.Fatalf("Create in the same second = %+v, %v, want another ID", latest, err)
+	}
+	// Only the newest of the day is kept
+	if list, _ := snapshots.List(ctx); len(list) != 1 || list[0].ID != latest.ID {
+		t.Errorf("List = %+v, want only %s", list, latest.ID)
+	}
+	if err := snapshots.Verify(ctx, latest.ID); err != nil {
+		t.Errorf("Verify = %v", err)
+	}
+	if records, err := snapshots.Records(ctx, latest.ID); err != nil || records[monday.ID].Rating != rating.Good {
+		t.Errorf("Records = %v, %v", records, err)
+	}
+
+	// Two days on, the policy keeps the last two days' snapshots
+	for range 2 {
+		now = now.AddDate(0, 0, 1)
+		if _, err := snapshots.Create(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if list, _ := snapshots.List(ctx); len(list) != 2 || list[1].ID == latest.ID {
+		t.Errorf("List after pruning = %+v, want one snapshot of each of the last two days", list)
+	}
+
+	if err := repo.Delete(ctx, monday.ID); err != nil {
+		t.Fatal(err)
+	}
+	list, _ := snapshots.List(ctx)
+	if _, err := snapshots.Restore(ctx, list[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := NewFileRepository(source)
+	if _, err := restored.GetByID(ctx, monday.ID); err != nil {
+		t.Errorf("GetByID after Restore = %v", err)
+	}
+
+	if err := os.WriteFile(snapshots.path(list[0].ID), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := snapshots.Verify(ctx, list[0].ID); rating.KindOf(err) != rating.KindStorage {
+		t.Errorf("Verify of a changed snapshot = %v, want a storage error", err)
+	}
+	if _, err := snapshots.Restore(ctx, "../ratings"); err != backup.ErrNotFound {
+		t.Errorf("Restore of a path = %v, want not found", err)
+	}
+}
+
+func TestSnapshotRepository(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	source := filepath.Join(dir, "ratings.json")
+	stored, err := NewFileRepository(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots := NewSnapshots(filepath.Join(dir, "backups"), source, backup.DefaultPolicy())
+	repo := NewSnapshotRepository(stored, snapshots)
+
+	monday := repotest.Day(2025, time.February, 17, rating.Good)
+	tuesday := repotest.Day(2025, time.February, 18, rating.Fair)
+	for _, dr := range []rating.DayRating{monday, tuesday} {
+		if err := repo.Save(ctx, dr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first save had no file to copy, the second took the day's snapshot
+	list, err := snapshots.List(ctx)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("List = %+v, %v, want one snapshot", list, err)
+	}
+	if err := repo.Delete(ctx, monday.ID); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := snapshots.List(ctx); len(list) != 1 {
+		t.Errorf("List after a second change = %+v, want still one snapshot today", list)
+	}
+}
@@ -0,0 +1,54 @@
+// internal/adapters/secondary/file/snooze.go
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// SnoozeStore keeps the reminder snooze in a JSON file, since every check is a new process
+type SnoozeStore struct {
+	filepath string
+}
+
+type snoozeFile struct {
+	SnoozedUntil time.Time `json:"snoozedUntil"`
+}
+
+func NewSnoozeStore(path string) (secondary.SnoozeStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "creating directory: %w", err)
+	}
+	return &SnoozeStore{filepath: path}, nil
+}
+
+func (s *SnoozeStore) SnoozedUntil(_ context.Context) (time.Time, error) {
+	data, err := os.ReadFile(s.filepath)
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, rating.Errorf(rating.KindStorage, "reading snooze: %w", err)
+	}
+	var f snoozeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return time.Time{}, rating.Errorf(rating.KindStorage, "reading snooze from %s: %w", s.filepath, err)
+	}
+	return f.SnoozedUntil, nil
+}
+
+func (s *SnoozeStore) SnoozeUntil(_ context.Context, until time.Time) error {
+	data, err := json.Marshal(snoozeFile{SnoozedUntil: until})
+	if err != nil {
+		return rating.Errorf(rating.KindStorage, "marshaling snooze: %w", err)
+	}
+	if err := writeAtomic(s.filepath, data); err != nil {
+		return rating.Errorf(rating.KindStorage, "writing snooze: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// internal/adapters/secondary/file/team.go
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/team"
+	"track/internal/track/ports/secondary"
+)
+
+// TeamDirectory keeps each member's export as <member>.json in a shared directory,
+// such as a synced folder or a network share
+type TeamDirectory struct {
+	dir string
+}
+
+var _ secondary.TeamDirectory = (*TeamDirectory)(nil)
+
+func NewTeamDirectory(dir string) *TeamDirectory {
+	return &TeamDirectory{dir: dir}
+}
+
+// Members reads plain ratings files and replicas alike, so a member may also share their ratings file itself
+func (d *TeamDirectory) Members(_ context.Context) ([]team.Member, error) {
+	paths, err := filepath.Glob(filepath.Join(d.dir, "*.json"))
+	if err != nil {
+		return nil, rating.Errorf(rating.KindInternal, "listing %s: %w", d.dir, err)
+	}
+	if len(paths) == 0 {
+		if _, err := os.Stat(d.dir); err != nil {
+			return nil, rating.Errorf(rating.KindConfig, "reading team.dir: %w", err)
+		}
+	}
+
+	members := make([]team.Member, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, rating.Errorf(rating.KindStorage, "reading %s: %w", path, err)
+		}
+		replica, err := decodeReplica(data)
+		if err != nil {
+			return nil, rating.Errorf(rating.KindStorage, "%s is not a ratings file: %w", path, err)
+		}
+		m := team.Member{Name: strings.TrimSuffix(filepath.Base(path), ".json")}
+		for _, dr := range replica.Entries.Ratings() {
+			m.Ratings = append(m.Ratings, dr)
+		}
+		sort.Slice(m.Ratings, func(i, j int) bool { return m.Ratings[i].Date.Before(m.Ratings[j].Date) })
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+func (d *TeamDirectory) Publish(_ context.Context, member string, ratings []rating.DayRating) error {
+	if member == "" || strings.HasPrefix(member, ".") || strings.ContainsAny(member, `/\`) {
+		return rating.Errorf(rating.KindValidation, "invalid member name %q", member)
+	}
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return rating.Errorf(rating.KindStorage, "creating team directory: %w", err)
+	}
+	byID := make(map[string]rating.DayRating, len(ratings))
+	for _, dr := range ratings {
+		byID[dr.ID] = dr
+	}
+	return WriteRatings(filepath.Join(d.dir, member+".json"), byID)
+}
@@ -0,0 +1,63 @@
+// internal/adapters/secondary/gitlog/gitlog.go
+package gitlog
+
+import (
+	"context"
+	"strings"
+	"time"
+	"track/internal/track/adapters/secondary/gitsync"
+	"track/internal/track/domain/activity"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// Log reads commits with the git binary, from every branch, leaving out merges
+type Log struct {
+	authors []string
+}
+
+var _ secondary.CommitLog = (*Log)(nil)
+
+// New finds the commits authored with one of the emails in authors. With none,
+// each repository's own user.email is the user's.
+func New(authors []string) *Log {
+	return &Log{authors: authors}
+}
+
+func (l *Log) Commits(ctx context.Context, repo string, since time.Time) ([]activity.Commit, error) {
+	authors := l.authors
+	if len(authors) == 0 {
+		email, err := gitsync.Run(ctx, repo, "config", "user.email")
+		if err != nil || email == "" {
+			return nil, rating.Errorf(rating.KindConfig, "%s has no user.email, set commits.author in ~/.track.properties", repo)
+		}
+		authors = []string{email}
+	}
+
+	out, err := gitsync.Run(ctx, repo, "log", "--all", "--no-merges", "--since="+since.Format(time.RFC3339), "--format=%H %aI %ae")
+	if err != nil {
+		return nil, err
+	}
+	var commits []activity.Commit
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || !isAuthor(fields[2], authors) {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, rating.Errorf(rating.KindStorage, "%s: reading the date of %.8s: %w", repo, fields[0], err)
+		}
+		commits = append(commits, activity.Commit{Hash: fields[0], At: at})
+	}
+	return commits, nil
+}
+
+func isAuthor(email string, authors []string) bool {
+	for _, a := range authors {
+		if strings.EqualFold(email, a) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package gitlog
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+func TestCommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	ctx := context.Background()
+	repo := t.TempDir()
+	run := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(cmd.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	commit := func(email, date string) {
+		t.Helper()
+		run([]string{"GIT_AUTHOR_EMAIL=" + email, "GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date},
+			"-c", "user.name=Someone", "commit", "-q", "--allow-empty", "-m", "work")
+	}
+	run(nil, "init", "-q")
+
+	if _, err := New(nil).Commits(ctx, repo, time.Time{}); rating.KindOf(err) != rating.KindConfig {
+		t.Errorf("Commits without any author = %v, want a config error", err)
+	}
+
+	run(nil, "config", "user.email", "me@example.com")
+	commit("me@example.com", "2025-01-10T12:00:00+01:00")
+	commit("me@example.com", "2025-02-17T09:00:00+01:00")
+	commit("someone@example.com", "2025-02-17T10:00:00+01:00")
+	commit("Me@Example.com", "2025-02-18T23:30:00+01:00")
+
+	since := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+	commits, err := New(nil).Commits(ctx, repo, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Commits = %+v, want the user's two since February", commits)
+	}
+	if day := commits[0].At.Format(time.DateOnly); day != "2025-02-18" || len(commits[0].Hash) != 40 {
+		t.Errorf("latest commit = %+v, want one on the 18th where it was made", commits[0])
+	}
+
+	if commits, err := New([]string{"someone@example.com"}).Commits(ctx, repo, since); err != nil || len(commits) != 1 {
+		t.Errorf("Commits by someone = %+v, %v, want one", commits, err)
+	}
+	if _, err := New(nil).Commits(ctx, t.TempDir(), since); err == nil {
+		t.Error("Commits outside a repository succeeded")
+	}
+}
@@ -0,0 +1,243 @@
+// internal/adapters/secondary/gitsync/git.go
+package gitsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"track/internal/track/adapters/secondary/file"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// Git keeps the ratings file's directory in a git repository and syncs it with a remote.
+// Only the ratings file is committed, whatever else shares the directory.
+type Git struct {
+	dir, file string
+	home      string
+	remote    string
+	branch    string
+}
+
+var _ secondary.SyncRemote = (*Git)(nil)
+
+// New syncs the ratings file at path with the branch of remote, which may be any
+// URL or path git accepts. home is never made a repository.
+func New(path, home, remote, branch string) *Git {
+	return &Git{
+		dir:    filepath.Dir(path),
+		file:   filepath.Base(path),
+		home:   home,
+		remote: remote,
+		branch: branch,
+	}
+}
+
+// Initialized reports whether the ratings directory is a repository already
+func (g *Git) Initialized() bool {
+	_, err := os.Stat(filepath.Join(g.dir, ".git"))
+	return err == nil
+}
+
+func (g *Git) Fetch(ctx context.Context) (secondary.SyncState, error) {
+	if g.remote == "" {
+		return secondary.SyncState{}, rating.Errorf(rating.KindConfig, "no remote to sync with, set sync.remote in ~/.track.properties")
+	}
+	if err := g.init(ctx); err != nil {
+		return secondary.SyncState{}, err
+	}
+	if err := g.Commit(ctx, "Update ratings"); err != nil {
+		return secondary.SyncState{}, err
+	}
+	if _, err := g.git(ctx, "fetch", "-q", "origin"); err != nil {
+		return secondary.SyncState{}, err
+	}
+
+	theirs, ok := g.revision(ctx, g.tracking())
+	if !ok {
+		return secondary.SyncState{Relation: secondary.SyncNoRemote}, nil
+	}
+	ours, ok := g.revision(ctx, "HEAD")
+	if !ok {
+		// Nothing rated here yet, the remote's history becomes ours
+		return secondary.SyncState{Relation: secondary.SyncBehind}, nil
+	}
+	if ours == theirs {
+		return secondary.SyncState{Relation: secondary.SyncUpToDate}, nil
+	}
+
+	// Without a merge base the histories are unrelated, two devices that started separately
+	base, _ := g.git(ctx, "merge-base", "HEAD", theirs)
+	switch base {
+	case theirs:
+		return secondary.SyncState{Relation: secondary.SyncAhead}, nil
+	case ours:
+		return secondary.SyncState{Relation: secondary.SyncBehind}, nil
+	}
+
+	state := secondary.SyncState{Relation: secondary.SyncDiverged}
+	var err error
+	if state.Base, err = g.ratingsAt(ctx, base); err != nil {
+		return state, err
+	}
+	if state.Ours, err = g.ratingsAt(ctx, ours); err != nil {
+		return state, err
+	}
+	if state.Theirs, err = g.ratingsAt(ctx, theirs); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+func (g *Git) FastForward(ctx context.Context) error {
+	_, err := g.git(ctx, "merge", "-q", "--ff-only", g.tracking())
+	return err
+}
+
+// CommitMerge records a merge commit of both histories that keeps merged as the ratings,
+// so git never merges the file's text itself
+func (g *Git) CommitMerge(ctx context.Context, merged map[string]rating.DayRating) error {
+	merge := append(g.identity(ctx), "merge", "-q", "--no-ff", "--no-commit", "-s", "ours", "--allow-unrelated-histories", g.tracking())
+	if _, err := g.git(ctx, merge...); err != nil {
+		return err
+	}
+	if err := file.WriteRatings(filepath.Join(g.dir, g.file), merged); err != nil {
+		_, _ = g.git(ctx, "merge", "--abort")
+		return err
+	}
+	if _, err := g.git(ctx, "add", "--", g.file); err != nil {
+		return err
+	}
+	_, err := g.commit(ctx, "Merge ratings from "+g.tracking())
+	return err
+}
+
+func (g *Git) Push(ctx context.Context) error {
+	_, err := g.git(ctx, "push", "-q", "origin", "HEAD:refs/heads/"+g.branch)
+	return err
+}
+
+// Commit records the ratings file if it changed since the last commit
+func (g *Git) Commit(ctx context.Context, message string) error {
+	if _, err := os.Stat(filepath.Join(g.dir, g.file)); err == nil {
+		if _, err := g.git(ctx, "add", "--", g.file); err != nil {
+			return err
+		}
+	} else if _, err := g.git(ctx, "rm", "-q", "--cached", "--ignore-unmatch", "--", g.file); err != nil {
+		return err
+	}
+
+	// Exit status 1 means staged changes
+	if _, err := g.git(ctx, "diff", "--cached", "--quiet", "--", g.file); err == nil {
+		return nil
+	}
+	_, err := g.commit(ctx, message)
+	return err
+}
+
+// init makes the ratings directory a repository on the sync branch, pointing origin at the remote
+func (g *Git) init(ctx context.Context) error {
+	if !g.Initialized() {
+		if filepath.Clean(g.dir) == filepath.Clean(g.home) {
+			return rating.Errorf(rating.KindConfig,
+				"not making your home directory a git repository, set rating.file to a file in a directory of its own, e.g. ~/.track/ratings.json")
+		}
+		if _, err := g.git(ctx, "init", "-q"); err != nil {
+			return err
+		}
+		if _, err := g.git(ctx, "symbolic-ref", "HEAD", "refs/heads/"+g.branch); err != nil {
+			return err
+		}
+	}
+
+	url, err := g.git(ctx, "remote", "get-url", "origin")
+	switch {
+	case err != nil:
+		_, err = g.git(ctx, "remote", "add", "origin", g.remote)
+	case url != g.remote:
+		_, err = g.git(ctx, "remote", "set-url", "origin", g.remote)
+	}
+	return err
+}
+
+// commit commits the index
+func (g *Git) commit(ctx context.Context, message string) (string, error) {
+	return g.git(ctx, append(g.identity(ctx), "commit", "-q", "--no-verify", "-m", message)...)
+}
+
+// identity returns settings that commit as track when no git identity is configured
+func (g *Git) identity(ctx context.Context) []string {
+	if email, _ := g.git(ctx, "config", "user.email"); email != "" {
+		return nil
+	}
+	host, _ := os.Hostname()
+	return []string{"-c", "user.name=track", "-c", "user.email=track@" + host}
+}
+
+func (g *Git) tracking() string {
+	return "refs/remotes/origin/" + g.branch
+}
+
+// revision resolves ref to a commit, false when it doesn't exist
+func (g *Git) revision(ctx context.Context, ref string) (string, bool) {
+	rev, err := g.git(ctx, "rev-parse", "-q", "--verify", ref+"^{commit}")
+	return rev, err == nil
+}
+
+// ratingsAt reads the ratings file as of a commit, empty if it didn't exist then
+func (g *Git) ratingsAt(ctx context.Context, rev string) (map[string]rating.DayRating, error) {
+	if rev == "" {
+		return map[string]rating.DayRating{}, nil
+	}
+	object := rev + ":" + g.file
+	if _, err := g.git(ctx, "cat-file", "-e", object); err != nil {
+		return map[string]rating.DayRating{}, nil
+	}
+	data, err := g.git(ctx, "cat-file", "blob", object)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := file.DecodeRatings([]byte(data))
+	if err != nil {
+		return nil, rating.Errorf(rating.KindStorage, "reading ratings at %.8s: %w", rev, err)
+	}
+	return ratings, nil
+}
+
+// git runs a git command in the ratings directory and returns its trimmed output
+func (g *Git) git(ctx context.Context, args ...string) (string, error) {
+	return Run(ctx, g.dir, args...)
+}
+
+// Run runs a git command in dir and returns its trimmed output. A missing git
+// binary is a configuration error, a failing command a storage error.
+func Run(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", rating.Errorf(rating.KindConfig, "running git, is it installed? %w", err)
+		}
+		name := subcommand(args)
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", rating.Errorf(rating.KindStorage, "git %s in %s: %w: %s", name, dir, err, detail)
+		}
+		return "", rating.Errorf(rating.KindStorage, "git %s in %s: %w", name, dir, err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// subcommand names a git command for errors, skipping -c settings before it
+func subcommand(args []string) string {
+	for len(args) > 2 && args[0] == "-c" {
+		args = args[2:]
+	}
+	return args[0]
+}
@@ -0,0 +1,155 @@
+package gitsync
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/file"
+	"track/internal/track/application/sync"
+	"track/internal/track/domain/rating"
+)
+
+// device is one machine's ratings, synced through the shared remote
+type device struct {
+	repo *Repository
+	sync *sync.Service
+}
+
+func newDevice(t *testing.T, home, remote string) device {
+	t.Helper()
+	path := filepath.Join(home, ".track", "ratings.json")
+	ratings, err := file.OpenFileRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	git := New(path, home, remote, "main")
+	return device{repo: NewRepository(ratings, git), sync: sync.NewService(git)}
+}
+
+func (d device) save(t *testing.T, dr rating.DayRating) {
+	t.Helper()
+	if stored, err := d.repo.GetByID(context.Background(), dr.ID); err == nil {
+		dr.Revision = stored.Revision + 1
+	} else {
+		dr.Revision = 1
+	}
+	if err := d.repo.Save(context.Background(), dr); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (d device) rating(t *testing.T, id string) rating.Rating {
+	t.Helper()
+	dr, err := d.repo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("%s: %v", id, err)
+	}
+	return dr.Rating
+}
+
+func isolateGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+}
+
+func TestSyncTwoDevices(t *testing.T) {
+	isolateGit(t)
+	ctx := context.Background()
+	remote := filepath.Join(t.TempDir(), "ratings.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+	laptop := newDevice(t, t.TempDir(), remote)
+	desktop := newDevice(t, t.TempDir(), remote)
+
+	monday := time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC)
+	day := func(date time.Time, r rating.Rating, updated time.Time) rating.DayRating {
+		return rating.DayRating{ID: rating.DayID(date), Date: date, Rating: r, CreatedAt: updated, UpdatedAt: updated}
+	}
+
+	laptop.save(t, day(monday, rating.Good, monday.Add(20*time.Hour)))
+	if report, err := laptop.sync.Sync(ctx); err != nil || !report.Pushed {
+		t.Fatalf("first sync = %+v, %v, want a push", report, err)
+	}
+	if report, err := desktop.sync.Sync(ctx); err != nil || !report.Pulled || report.Merged {
+		t.Fatalf("sync on a new device = %+v, %v, want a plain pull", report, err)
+	}
+	if got := desktop.rating(t, "25w08-1"); got != rating.Good {
+		t.Errorf("desktop's Monday = %v, want Good", got)
+	}
+
+	// Both change Monday, the laptop later, and the desktop also rates Tuesday
+	desktop.save(t, day(monday, rating.Bad, monday.Add(21*time.Hour)))
+	desktop.save(t, day(monday.AddDate(0, 0, 1), rating.Fair, monday.Add(44*time.Hour)))
+	laptop.save(t, day(monday, rating.Awesome, monday.Add(22*time.Hour)))
+	if _, err := laptop.sync.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	report, err := desktop.sync.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Merged || !report.Pushed || len(report.Conflicts) != 1 || report.Conflicts[0].Rule != rating.RuleLatest {
+		t.Errorf("diverged sync = %+v, want a pushed merge with Monday's conflict", report)
+	}
+	if _, err := laptop.sync.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for name, d := range map[string]device{"laptop": laptop, "desktop": desktop} {
+		if got := d.rating(t, "25w08-1"); got != rating.Awesome {
+			t.Errorf("%s's Monday = %v, want the later Awesome", name, got)
+		}
+		if got := d.rating(t, "25w08-2"); got != rating.Fair {
+			t.Errorf("%s's Tuesday = %v, want Fair", name, got)
+		}
+	}
+
+	if report, err := desktop.sync.Sync(ctx); err != nil || report.Pulled || report.Pushed {
+		t.Errorf("sync when up to date = %+v, %v", report, err)
+	}
+}
+
+func TestSyncUnrelatedHistories(t *testing.T) {
+	isolateGit(t)
+	ctx := context.Background()
+	remote := filepath.Join(t.TempDir(), "ratings.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+	a := newDevice(t, t.TempDir(), remote)
+	b := newDevice(t, t.TempDir(), remote)
+	monday := time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC)
+	a.save(t, rating.DayRating{ID: "25w08-1", Date: monday, Rating: rating.Good})
+	b.save(t, rating.DayRating{ID: "25w08-2", Date: monday.AddDate(0, 0, 1), Rating: rating.Poor})
+
+	if _, err := a.sync.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if report, err := b.sync.Sync(ctx); err != nil || !report.Merged {
+		t.Fatalf("sync of a separate history = %+v, %v, want a merge", report, err)
+	}
+	if b.rating(t, "25w08-1") != rating.Good || b.rating(t, "25w08-2") != rating.Poor {
+		t.Error("merge of unrelated histories lost a day")
+	}
+}
+
+func TestSyncRefusesHome(t *testing.T) {
+	isolateGit(t)
+	home := t.TempDir()
+	git := New(filepath.Join(home, ".track.rating.json"), home, filepath.Join(t.TempDir(), "remote.git"), "main")
+
+	_, err := git.Fetch(context.Background())
+	if rating.KindOf(err) != rating.KindConfig {
+		t.Errorf("Fetch in home = %v, want a config error", err)
+	}
+	if git.Initialized() {
+		t.Error("home was made a repository")
+	}
+}
@@ -0,0 +1,44 @@
+// internal/adapters/secondary/gitsync/repository.go
+package gitsync
+
+import (
+	"context"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// Repository commits every change to the ratings once the directory is synced, so
+// each write is its own step in the history that sync merges
+type Repository struct {
+	secondary.RatingRepository
+	git *Git
+}
+
+var _ secondary.RatingRepository = (*Repository)(nil)
+
+func NewRepository(repo secondary.RatingRepository, git *Git) *Repository {
+	return &Repository{RatingRepository: repo, git: git}
+}
+
+func (r *Repository) Save(ctx context.Context, dr rating.DayRating) error {
+	if err := r.RatingRepository.Save(ctx, dr); err != nil {
+		return err
+	}
+	r.commit(ctx, "Rate "+dr.ID)
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	if err := r.RatingRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.commit(ctx, "Remove "+id)
+	return nil
+}
+
+// commit is best effort: the change is saved either way, and the next sync commits what is left
+func (r *Repository) commit(ctx context.Context, message string) {
+	if r.git.Initialized() {
+		_ = r.git.Commit(ctx, message)
+	}
+}
@@ -0,0 +1,51 @@
+// internal/adapters/secondary/memory/goals.go
+package memory
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"track/internal/track/domain/goal"
+	"track/internal/track/ports/secondary"
+)
+
+// GoalRepository keeps goals in a slice, for tests and throwaway sessions
+type GoalRepository struct {
+	mu     sync.Mutex
+	goals  []goal.Goal
+	lastID int
+}
+
+func NewGoalRepository() secondary.GoalRepository {
+	return &GoalRepository{}
+}
+
+func (r *GoalRepository) List(_ context.Context) ([]goal.Goal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]goal.Goal(nil), r.goals...), nil
+}
+
+func (r *GoalRepository) Add(_ context.Context, g goal.Goal) (goal.Goal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	g.ID = strconv.Itoa(r.lastID)
+	r.goals = append(r.goals, g)
+	return g, nil
+}
+
+func (r *GoalRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, g := range r.goals {
+		if g.ID == id {
+			r.goals = append(r.goals[:i], r.goals[i+1:]...)
+			return nil
+		}
+	}
+	return goal.ErrNotFound
+}
@@ -0,0 +1,42 @@
+// internal/adapters/secondary/memory/promptcache.go
+package memory
+
+import (
+	"context"
+	"sync"
+	"track/internal/track/ports/secondary"
+)
+
+// PromptCache keeps the segments of one version in a map, for tests
+type PromptCache struct {
+	mu       sync.Mutex
+	version  string
+	segments map[string]string
+}
+
+func NewPromptCache() secondary.PromptCache {
+	return &PromptCache{}
+}
+
+func (c *PromptCache) Get(_ context.Context, version, format string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version != c.version {
+		return "", false, nil
+	}
+	segment, ok := c.segments[format]
+	return segment, ok, nil
+}
+
+func (c *PromptCache) Put(_ context.Context, version, format, segment string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version != c.version || c.segments == nil {
+		c.version = version
+		c.segments = make(map[string]string)
+	}
+	c.segments[format] = segment
+	return nil
+}
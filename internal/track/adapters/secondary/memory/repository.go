@@ -0,0 +1,110 @@
+// internal/adapters/secondary/memory/repository.go
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// MemoryRepository keeps ratings in a map, for tests and throwaway sessions
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	ratings map[string]rating.DayRating
+	writes  int
+}
+
+func NewMemoryRepository() secondary.RatingRepository {
+	return &MemoryRepository{
+		ratings: make(map[string]rating.DayRating),
+	}
+}
+
+func (r *MemoryRepository) Save(_ context.Context, dr rating.DayRating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored := r.ratings[dr.ID].Revision; dr.Revision != stored+1 {
+		return fmt.Errorf("saving %s at revision %d over %d: %w", dr.ID, dr.Revision, stored, rating.ErrStaleRevision)
+	}
+	r.ratings[dr.ID] = dr
+	r.writes++
+	return nil
+}
+
+func (r *MemoryRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.ratings[id]; !exists {
+		return rating.ErrNotFound
+	}
+	delete(r.ratings, id)
+	r.writes++
+	return nil
+}
+
+// Version counts the writes, so it changes with every Save and Delete
+func (r *MemoryRepository) Version(_ context.Context) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return strconv.Itoa(r.writes), nil
+}
+
+func (r *MemoryRepository) GetByID(_ context.Context, id string) (rating.DayRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	dr, exists := r.ratings[id]
+	if !exists {
+		return rating.DayRating{}, rating.ErrNotFound
+	}
+
+	return dr, nil
+}
+
+func (r *MemoryRepository) GetByDateRange(_ context.Context, start, end time.Time) ([]rating.DayRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var results []rating.DayRating
+	for _, dr := range r.ratings {
+		if !dr.Date.Before(start) && !dr.Date.After(end) {
+			results = append(results, dr)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Date.Before(results[j].Date) })
+
+	return results, nil
+}
+
+func (r *MemoryRepository) GetByWeek(_ context.Context, year, week int) ([]rating.DayRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var results []rating.DayRating
+	for _, dr := range r.ratings {
+		y, w := dr.Date.ISOWeek()
+		if y == year && w == week {
+			results = append(results, dr)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Date.Before(results[j].Date) })
+
+	return results, nil
+}
+
+// Records returns a copy of the stored ratings by ID
+func (r *MemoryRepository) Records(_ context.Context) (map[string]rating.DayRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return maps.Clone(r.ratings), nil
+}
@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"testing"
+	"track/internal/track/adapters/secondary/repotest"
+	"track/internal/track/ports/secondary"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) secondary.RatingRepository {
+		return NewMemoryRepository()
+	})
+}
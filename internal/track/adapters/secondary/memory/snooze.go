@@ -0,0 +1,34 @@
+// internal/adapters/secondary/memory/snooze.go
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+	"track/internal/track/ports/secondary"
+)
+
+// SnoozeStore keeps the snooze in memory, for tests
+type SnoozeStore struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+func NewSnoozeStore() secondary.SnoozeStore {
+	return &SnoozeStore{}
+}
+
+func (s *SnoozeStore) SnoozedUntil(_ context.Context) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.until, nil
+}
+
+func (s *SnoozeStore) SnoozeUntil(_ context.Context, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.until = until
+	return nil
+}
@@ -0,0 +1,77 @@
+// internal/adapters/secondary/notify/notify.go
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// Names of the notifiers for the remind.notifier setting
+const (
+	NameNotifySend = "notify-send"
+	NameBell       = "bell"
+	NameCommand    = "command"
+)
+
+// New returns the notifier called name. command is the shell hook for the command notifier,
+// out is where the bell rings.
+func New(name, command string, out io.Writer) (secondary.Notifier, error) {
+	switch name {
+	case NameNotifySend:
+		return NotifySend{}, nil
+	case NameBell:
+		return Bell{Out: out}, nil
+	case NameCommand:
+		if command == "" {
+			return nil, rating.Errorf(rating.KindConfig, "the command notifier needs remind.command")
+		}
+		return Command{Command: command}, nil
+	}
+	return nil, rating.Errorf(rating.KindConfig, "unknown notifier %q, expected notify-send, bell or command", name)
+}
+
+// NotifySend shows a desktop notification through libnotify's notify-send
+type NotifySend struct{}
+
+func (NotifySend) Notify(ctx context.Context, title, message string) error {
+	return run(exec.CommandContext(ctx, "notify-send", "--app-name=track", title, message))
+}
+
+// Bell rings the terminal bell and prints the reminder
+type Bell struct {
+	Out io.Writer
+}
+
+func (b Bell) Notify(_ context.Context, title, message string) error {
+	_, err := fmt.Fprintf(b.Out, "\a%s: %s\n", title, message)
+	return err
+}
+
+// Command runs a shell command with the reminder in $TRACK_TITLE and $TRACK_MESSAGE
+type Command struct {
+	Command string
+}
+
+func (c Command) Notify(ctx context.Context, title, message string) error {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", c.Command)
+	cmd.Env = append(os.Environ(), "TRACK_TITLE="+title, "TRACK_MESSAGE="+message)
+	return run(cmd)
+}
+
+// run runs cmd, adding its output to the error when it fails
+func run(cmd *exec.Cmd) error {
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if detail := strings.TrimSpace(string(output)); detail != "" {
+			return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, detail)
+		}
+		return fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	return nil
+}
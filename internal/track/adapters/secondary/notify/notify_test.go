@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"track/internal/track/domain/rating"
+)
+
+func TestCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "hook.txt")
+	hook := Command{Command: `printf '%s|%s' "$TRACK_TITLE" "$TRACK_MESSAGE" > ` + out}
+
+	if err := hook.Notify(context.Background(), "Rate your day", "25w08-3 isn't rated yet"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Rate your day|25w08-3 isn't rated yet"; string(got) != want {
+		t.Errorf("hook saw %q, want %q", got, want)
+	}
+
+	failing := Command{Command: "echo no display >&2; exit 3"}
+	if err := failing.Notify(context.Background(), "t", "m"); err == nil || err.Error() != "/bin/sh: exit status 3: no display" {
+		t.Errorf("failing hook = %v, want its exit status and output", err)
+	}
+}
+
+func TestBell(t *testing.T) {
+	var out bytes.Buffer
+	if err := (Bell{Out: &out}).Notify(context.Background(), "Rate your day", "now"); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "\aRate your day: now\n" {
+		t.Errorf("bell wrote %q", out.String())
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(NameCommand, "", nil); rating.KindOf(err) != rating.KindConfig {
+		t.Errorf("command without a hook = %v, want a config error", err)
+	}
+	if _, err := New("pigeon", "", nil); rating.KindOf(err) != rating.KindConfig {
+		t.Errorf("unknown notifier = %v, want a config error", err)
+	}
+}
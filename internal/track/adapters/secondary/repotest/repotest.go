@@ -0,0 +1,320 @@
+// internal/adapters/secondary/repotest/repotest.go
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// Factory returns an empty repository, cleaning up after itself through t
+type Factory func(t *testing.T) secondary.RatingRepository
+
+// Run checks that the repositories made by newRepo honour the secondary.RatingRepository contract
+func Run(t *testing.T, newRepo Factory) {
+	t.Run("SaveAndGet", func(t *testing.T) { testSaveAndGet(t, newRepo(t)) })
+	t.Run("Overwrite", func(t *testing.T) { testOverwrite(t, newRepo(t)) })
+	t.Run("StaleRevision", func(t *testing.T) { testStaleRevision(t, newRepo(t)) })
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, newRepo(t)) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newRepo(t)) })
+	t.Run("RangeInclusiveBounds", func(t *testing.T) { testRangeInclusiveBounds(t, newRepo(t)) })
+	t.Run("RangeOrdering", func(t *testing.T) { testRangeOrdering(t, newRepo(t)) })
+	t.Run("WeekAcrossYearBoundary", func(t *testing.T) { testWeekAcrossYearBoundary(t, newRepo(t)) })
+	t.Run("ConcurrentAccess", func(t *testing.T) { testConcurrentAccess(t, newRepo(t)) })
+	t.Run("ConcurrentWriters", func(t *testing.T) { testConcurrentWriters(t, newRepo(t)) })
+}
+
+// Day builds a valid first revision rating for the given UTC date
+func Day(year int, month time.Month, day int, r rating.Rating) rating.DayRating {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return rating.DayRating{ID: rating.DayID(date), Date: date, Rating: r, Revision: 1}
+}
+
+// next returns dr as its following revision with rating r
+func next(dr rating.DayRating, r rating.Rating) rating.DayRating {
+	dr.Rating = r
+	dr.Revision++
+	return dr
+}
+
+func mustSave(t *testing.T, repo secondary.RatingRepository, ratings ...rating.DayRating) {
+	t.Helper()
+	for _, dr := range ratings {
+		if err := repo.Save(context.Background(), dr); err != nil {
+			t.Fatalf("Save(%s): %v", dr.ID, err)
+		}
+	}
+}
+
+func ids(ratings []rating.DayRating) string {
+	var s string
+	for i, dr := range ratings {
+		if i > 0 {
+			s += ","
+		}
+		s += dr.ID
+	}
+	return s
+}
+
+func testSaveAndGet(t *testing.T, repo secondary.RatingRepository) {
+	want := Day(2025, time.February, 17, rating.Good)
+	want.Note = "shipped it"
+	want.CreatedAt = time.Date(2025, time.February, 18, 8, 30, 0, 0, time.UTC)
+	want.UpdatedAt = want.CreatedAt.Add(time.Hour)
+	want.Source, want.Host = rating.SourceHTTP, "laptop"
+	mustSave(t, repo, want)
+
+	got, err := repo.GetByID(context.Background(), want.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.ID != want.ID || !got.Date.Equal(want.Date) || got.Rating != want.Rating || got.Note != want.Note {
+		t.Errorf("GetByID = %+v, want %+v", got, want)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) || got.Source != want.Source || got.Host != want.Host {
+		t.Errorf("GetByID lost provenance: %+v, want %+v", got, want)
+	}
+}
+
+func testOverwrite(t *testing.T, repo secondary.RatingRepository) {
+	first := Day(2025, time.February, 17, rating.Bad)
+	mustSave(t, repo, first, next(first, rating.Awesome))
+
+	got, err := repo.GetByID(context.Background(), first.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Rating != rating.Awesome || got.Revision != 2 {
+		t.Errorf("after overwrite got %v revision %d, want %v revision 2", got.Rating, got.Revision, rating.Awesome)
+	}
+
+	all, err := repo.GetByDateRange(context.Background(), time.Time{}, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetByDateRange: %v", err)
+	}
+	if len(all) != 1 {
+		t.Errorf("overwrite left %d ratings, want 1", len(all))
+	}
+}
+
+func testStaleRevision(t *testing.T, repo secondary.RatingRepository) {
+	first := Day(2025, time.February, 17, rating.Good)
+	mustSave(t, repo, first)
+
+	tests := []struct {
+		name string
+		dr   rating.DayRating
+	}{
+		{"second create", Day(2025, time.February, 17, rating.Bad)},
+		{"unversioned", rating.DayRating{ID: first.ID, Date: first.Date, Rating: rating.Bad}},
+		{"skipped revision", next(next(first, rating.Bad), rating.Bad)},
+		{"new day at revision 2", next(Day(2025, time.February, 18, rating.Bad), rating.Bad)},
+	}
+	for _, tt := range tests {
+		if err := repo.Save(context.Background(), tt.dr); !errors.Is(err, rating.ErrStaleRevision) {
+			t.Errorf("%s: Save = %v, want ErrStaleRevision", tt.name, err)
+		}
+	}
+
+	got, err := repo.GetByID(context.Background(), first.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Rating != first.Rating || got.Revision != first.Revision {
+		t.Errorf("rejected saves changed the rating to %v revision %d", got.Rating, got.Revision)
+	}
+	if _, err := repo.GetByID(context.Background(), Day(2025, time.February, 18, 0).ID); !errors.Is(err, rating.ErrNotFound) {
+		t.Errorf("rejected save stored a new day: %v", err)
+	}
+}
+
+func testNotFound(t *testing.T, repo secondary.RatingRepository) {
+	if _, err := repo.GetByID(context.Background(), "25w08-1"); !errors.Is(err, rating.ErrNotFound) {
+		t.Errorf("GetByID on empty repository = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(context.Background(), "25w08-1"); !errors.Is(err, rating.ErrNotFound) {
+		t.Errorf("Delete on empty repository = %v, want ErrNotFound", err)
+	}
+
+	ratings, err := repo.GetByWeek(context.Background(), 2025, 8)
+	if err != nil || len(ratings) != 0 {
+		t.Errorf("GetByWeek on empty repository = %v, %v, want nothing", ratings, err)
+	}
+}
+
+func testDelete(t *testing.T, repo secondary.RatingRepository) {
+	keep, remove := Day(2025, time.February, 17, rating.Good), Day(2025, time.February, 18, rating.Poor)
+	mustSave(t, repo, keep, remove)
+
+	if err := repo.Delete(context.Background(), remove.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(context.Background(), remove.ID); !errors.Is(err, rating.ErrNotFound) {
+		t.Errorf("GetByID after Delete = %v, want ErrNotFound", err)
+	}
+	if _, err := repo.GetByID(context.Background(), keep.ID); err != nil {
+		t.Errorf("Delete removed the wrong rating: %v", err)
+	}
+}
+
+func testRangeInclusiveBounds(t *testing.T, repo secondary.RatingRepository) {
+	mustSave(t, repo,
+		Day(2025, time.March, 9, rating.Bad),
+		Day(2025, time.March, 10, rating.Poor),
+		Day(2025, time.March, 12, rating.Fair),
+		Day(2025, time.March, 14, rating.Good),
+		Day(2025, time.March, 15, rating.Awesome),
+	)
+
+	start := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)
+	got, err := repo.GetByDateRange(context.Background(), start, end)
+	if err != nil {
+		t.Fatalf("GetByDateRange: %v", err)
+	}
+	if want := "25w11-1,25w11-3,25w11-5"; ids(got) != want {
+		t.Errorf("GetByDateRange = %s, want %s", ids(got), want)
+	}
+
+	got, err = repo.GetByDateRange(context.Background(), start, start)
+	if err != nil {
+		t.Fatalf("GetByDateRange: %v", err)
+	}
+	if want := "25w11-1"; ids(got) != want {
+		t.Errorf("single day GetByDateRange = %s, want %s", ids(got), want)
+	}
+}
+
+func testRangeOrdering(t *testing.T, repo secondary.RatingRepository) {
+	// Saved out of order, spanning a Sunday whose weekday digit sorts first
+	mustSave(t, repo,
+		Day(2025, time.March, 18, rating.Good),
+		Day(2025, time.March, 16, rating.Fair),
+		Day(2025, time.March, 17, rating.Bad),
+		Day(2025, time.March, 12, rating.Awesome),
+	)
+
+	got, err := repo.GetByDateRange(context.Background(), time.Time{}, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetByDateRange: %v", err)
+	}
+	for i := 1; i < len(got); i++ {
+		if !got[i-1].Date.Before(got[i].Date) {
+			t.Fatalf("GetByDateRange not ordered by date: %s", ids(got))
+		}
+	}
+
+	week, err := repo.GetByWeek(context.Background(), 2025, 11)
+	if err != nil {
+		t.Fatalf("GetByWeek: %v", err)
+	}
+	if want := "25w11-3,25w11-0"; ids(week) != want {
+		t.Errorf("GetByWeek = %s, want %s", ids(week), want)
+	}
+}
+
+func testWeekAcrossYearBoundary(t *testing.T, repo secondary.RatingRepository) {
+	// 2024-12-30 and 2025-01-05 are both in ISO week 1 of 2025, 2024-01-01 is week 1 of 2024
+	mustSave(t, repo,
+		Day(2024, time.January, 1, rating.Bad),
+		Day(2024, time.December, 29, rating.Poor),
+		Day(2024, time.December, 30, rating.Fair),
+		Day(2025, time.January, 5, rating.Good),
+		Day(2025, time.January, 6, rating.Awesome),
+	)
+
+	tests := []struct {
+		year, week int
+		want       string
+	}{
+		{2025, 1, "25w01-1,25w01-0"},
+		{2024, 1, "24w01-1"},
+		{2024, 52, "24w52-0"},
+		{2025, 2, "25w02-1"},
+	}
+	for _, tt := range tests {
+		got, err := repo.GetByWeek(context.Background(), tt.year, tt.week)
+		if err != nil {
+			t.Fatalf("GetByWeek(%d, %d): %v", tt.year, tt.week, err)
+		}
+		if ids(got) != tt.want {
+			t.Errorf("GetByWeek(%d, %d) = %s, want %s", tt.year, tt.week, ids(got), tt.want)
+		}
+	}
+}
+
+func testConcurrentAccess(t *testing.T, repo secondary.RatingRepository) {
+	const days = 40
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*days)
+	for i := 0; i < days; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			date := start.AddDate(0, 0, i)
+			dr := rating.DayRating{ID: rating.DayID(date), Date: date, Rating: rating.Rating(i%5 + 1), Revision: 1}
+			if err := repo.Save(context.Background(), dr); err != nil {
+				errs <- fmt.Errorf("Save(%s): %w", dr.ID, err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.GetByDateRange(context.Background(), start, start.AddDate(0, 0, days)); err != nil {
+				errs <- fmt.Errorf("GetByDateRange: %w", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	got, err := repo.GetByDateRange(context.Background(), start, start.AddDate(0, 0, days))
+	if err != nil {
+		t.Fatalf("GetByDateRange: %v", err)
+	}
+	if len(got) != days {
+		t.Errorf("after concurrent saves got %d ratings, want %d", len(got), days)
+	}
+}
+
+func testConcurrentWriters(t *testing.T, repo secondary.RatingRepository) {
+	const writers = 20
+	first := Day(2025, time.February, 17, rating.Fair)
+	mustSave(t, repo, first)
+
+	// Every writer read revision 1, only one of them may win
+	var wg sync.WaitGroup
+	results := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results <- repo.Save(context.Background(), next(first, rating.Rating(i%5+1)))
+		}(i)
+	}
+	wg.Wait()
+	close(results)
+
+	saved := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			saved++
+		case !errors.Is(err, rating.ErrStaleRevision):
+			t.Errorf("Save: %v, want nil or ErrStaleRevision", err)
+		}
+	}
+	if saved != 1 {
+		t.Errorf("%d concurrent writers of revision 2 succeeded, want 1", saved)
+	}
+}
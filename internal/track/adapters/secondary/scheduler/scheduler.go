@@ -0,0 +1,209 @@
+// internal/adapters/secondary/scheduler/scheduler.go
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// Both schedulers run the check every quarter of an hour, the check itself knows the reminder time
+const (
+	onCalendar = "*:0/15"
+	cronSpec   = "*/15 * * * *"
+	unitName   = "track-remind"
+	cronMarker = "# track-remind"
+)
+
+// Runner runs a program with stdin and returns its standard output, swapped out in tests
+type Runner func(ctx context.Context, stdin string, name string, args ...string) (string, error)
+
+// Exec runs programs for real
+func Exec(ctx context.Context, stdin string, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return stdout.String(), fmt.Errorf("%s: %w: %s", name, err, detail)
+		}
+		return stdout.String(), fmt.Errorf("%s: %w", name, err)
+	}
+	return stdout.String(), nil
+}
+
+// Systemd installs a systemd user service and a timer that starts it
+type Systemd struct {
+	// UnitDir is usually ~/.config/systemd/user
+	UnitDir string
+	// Command is the check to run, the track executable and its arguments
+	Command []string
+	// Env holds KEY=value pairs the check needs, such as TRACK_CONFIG
+	Env []string
+	Run Runner
+}
+
+var _ secondary.Scheduler = (*Systemd)(nil)
+
+func (s *Systemd) Install(ctx context.Context) (string, error) {
+	if err := os.MkdirAll(s.UnitDir, 0755); err != nil {
+		return "", rating.Errorf(rating.KindStorage, "creating %s: %w", s.UnitDir, err)
+	}
+	units := map[string]string{
+		unitName + ".service": s.service(),
+		unitName + ".timer":   timer,
+	}
+	for name, content := range units {
+		if err := os.WriteFile(filepath.Join(s.UnitDir, name), []byte(content), 0644); err != nil {
+			return "", rating.Errorf(rating.KindStorage, "writing %s: %w", name, err)
+		}
+	}
+
+	if _, err := s.Run(ctx, "", "systemctl", "--user", "daemon-reload"); err != nil {
+		return "", err
+	}
+	if _, err := s.Run(ctx, "", "systemctl", "--user", "enable", "--now", unitName+".timer"); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("installed %s.timer in %s", unitName, s.UnitDir), nil
+}
+
+func (s *Systemd) Uninstall(ctx context.Context) (string, error) {
+	timerPath := filepath.Join(s.UnitDir, unitName+".timer")
+	if _, err := os.Stat(timerPath); os.IsNotExist(err) {
+		return unitName + ".timer is not installed", nil
+	}
+
+	if _, err := s.Run(ctx, "", "systemctl", "--user", "disable", "--now", unitName+".timer"); err != nil {
+		return "", err
+	}
+	for _, name := range []string{unitName + ".timer", unitName + ".service"} {
+		if err := os.Remove(filepath.Join(s.UnitDir, name)); err != nil && !os.IsNotExist(err) {
+			return "", rating.Errorf(rating.KindStorage, "removing %s: %w", name, err)
+		}
+	}
+	if _, err := s.Run(ctx, "", "systemctl", "--user", "daemon-reload"); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("removed %s.timer from %s", unitName, s.UnitDir), nil
+}
+
+func (s *Systemd) service() string {
+	var b strings.Builder
+	b.WriteString("[Unit]\nDescription=Remind to rate the day\n\n[Service]\nType=oneshot\n")
+	for _, env := range s.Env {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(env))
+	}
+	var args []string
+	for _, arg := range s.Command {
+		args = append(args, systemdQuote(arg))
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(args, " "))
+	return b.String()
+}
+
+var timer = `[Unit]
+Description=Check whether the day is rated
+
+[Timer]
+OnCalendar=` + onCalendar + `
+
+[Install]
+WantedBy=timers.target
+`
+
+// systemdQuote double quotes a word for a unit file, where % starts a specifier
+func systemdQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%").Replace(s)
+	return `"` + s + `"`
+}
+
+// Cron adds a line to the user's crontab, keeping the rest of it as it is
+type Cron struct {
+	Command []string
+	Env     []string
+	Run     Runner
+}
+
+var _ secondary.Scheduler = (*Cron)(nil)
+
+func (c *Cron) Install(ctx context.Context) (string, error) {
+	lines, _, err := c.read(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var words []string
+	for _, env := range c.Env {
+		// Only an unquoted NAME= makes the word an assignment rather than the command
+		name, value, _ := strings.Cut(env, "=")
+		words = append(words, name+"="+shellQuote(value))
+	}
+	for _, arg := range c.Command {
+		words = append(words, shellQuote(arg))
+	}
+	// An unescaped % ends the command in crontab
+	line := strings.ReplaceAll(fmt.Sprintf("%s %s %s", cronSpec, strings.Join(words, " "), cronMarker), "%", `\%`)
+
+	if err := c.write(ctx, append(lines, line)); err != nil {
+		return "", err
+	}
+	return "added the reminder check to your crontab", nil
+}
+
+func (c *Cron) Uninstall(ctx context.Context) (string, error) {
+	lines, found, err := c.read(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "the reminder check is not in your crontab", nil
+	}
+	if err := c.write(ctx, lines); err != nil {
+		return "", err
+	}
+	return "removed the reminder check from your crontab", nil
+}
+
+// read returns the crontab without our line, and whether it was there
+func (c *Cron) read(ctx context.Context) (lines []string, found bool, err error) {
+	current, err := c.Run(ctx, "", "crontab", "-l")
+	if err != nil {
+		// crontab -l fails when the user has no crontab yet
+		if current == "" && strings.Contains(err.Error(), "no crontab") {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	current = strings.TrimRight(current, "\n")
+	if current == "" {
+		return nil, false, nil
+	}
+	for _, line := range strings.Split(current, "\n") {
+		if strings.HasSuffix(line, cronMarker) {
+			found = true
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, found, nil
+}
+
+func (c *Cron) write(ctx context.Context, lines []string) error {
+	_, err := c.Run(ctx, strings.Join(lines, "\n")+"\n", "crontab", "-")
+	return err
+}
+
+// shellQuote single quotes a word for /bin/sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
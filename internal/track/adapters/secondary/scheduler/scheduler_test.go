@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeSystem records the commands run and plays crontab from a string
+type fakeSystem struct {
+	calls   []string
+	crontab *string
+}
+
+func (f *fakeSystem) run(_ context.Context, stdin string, name string, args ...string) (string, error) {
+	call := strings.Join(append([]string{name}, args...), " ")
+	f.calls = append(f.calls, call)
+	switch call {
+	case "crontab -l":
+		if f.crontab == nil {
+			return "", errors.New("crontab: exit status 1: no crontab for ann")
+		}
+		return *f.crontab, nil
+	case "crontab -":
+		f.crontab = &stdin
+	}
+	return "", nil
+}
+
+func TestSystemd(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "systemd", "user")
+	system := &fakeSystem{}
+	s := &Systemd{
+		UnitDir: dir,
+		Command: []string{"/opt/my track/track", "remind", "check"},
+		Env:     []string{"TRACK_CONFIG=/home/ann/100%.properties"},
+		Run:     system.run,
+	}
+
+	if _, err := s.Install(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	service, err := os.ReadFile(filepath.Join(dir, "track-remind.service"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`Environment="TRACK_CONFIG=/home/ann/100%%.properties"`,
+		`ExecStart="/opt/my track/track" "remind" "check"`,
+	} {
+		if !strings.Contains(string(service), want) {
+			t.Errorf("service unit lacks %s:\n%s", want, service)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "track-remind.timer")); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := s.Uninstall(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "track-remind.service")); !os.IsNotExist(err) {
+		t.Errorf("service unit left behind: %v", err)
+	}
+
+	want := []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable --now track-remind.timer",
+		"systemctl --user disable --now track-remind.timer",
+		"systemctl --user daemon-reload",
+	}
+	if strings.Join(system.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ran\n%s\nwant\n%s", strings.Join(system.calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCron(t *testing.T) {
+	existing := "MAILTO=ann\n0 3 * * * backup\n"
+	system := &fakeSystem{crontab: &existing}
+	c := &Cron{Command: []string{"/usr/bin/track", "remind", "check"}, Run: system.run}
+	ctx := context.Background()
+
+	// Installing twice leaves one line
+	for i := 0; i < 2; i++ {
+		if _, err := c.Install(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := "MAILTO=ann\n0 3 * * * backup\n*/15 * * * * '/usr/bin/track' 'remind' 'check' # track-remind\n"
+	if *system.crontab != want {
+		t.Errorf("crontab =\n%s\nwant\n%s", *system.crontab, want)
+	}
+
+	if _, err := c.Uninstall(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if *system.crontab != existing {
+		t.Errorf("crontab after uninstall =\n%s\nwant\n%s", *system.crontab, existing)
+	}
+	if msg, err := c.Uninstall(ctx); err != nil || !strings.Contains(msg, "not in your crontab") {
+		t.Errorf("second Uninstall = %q, %v", msg, err)
+	}
+}
+
+func TestCronWithoutCrontab(t *testing.T) {
+	system := &fakeSystem{}
+	c := &Cron{Command: []string{"track", "remind", "check"}, Run: system.run}
+
+	if _, err := c.Install(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := "*/15 * * * * 'track' 'remind' 'check' # track-remind\n"; *system.crontab != want {
+		t.Errorf("crontab = %q, want %q", *system.crontab, want)
+	}
+}
+
+func TestCronWithEnv(t *testing.T) {
+	system := &fakeSystem{}
+	value := "/home/ann/ann's track.properties"
+	c := &Cron{Command: []string{"printenv", "TRACK_CONFIG"}, Env: []string{"TRACK_CONFIG=" + value}, Run: system.run}
+
+	if _, err := c.Install(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	line := strings.TrimSuffix(*system.crontab, "\n")
+	if want := `*/15 * * * * TRACK_CONFIG='/home/ann/ann'\''s track.properties' 'printenv' 'TRACK_CONFIG' # track-remind`; line != want {
+		t.Errorf("crontab line = %q, want %q", line, want)
+	}
+
+	// cron hands the line after its five schedule fields to sh
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh to run the line with")
+	}
+	command := strings.SplitN(line, " ", 6)[5]
+	out, err := exec.Command("sh", "-c", command).Output()
+	if err != nil {
+		t.Fatalf("sh -c %q: %v", command, err)
+	}
+	if got := strings.TrimSpace(string(out)); got != value {
+		t.Errorf("the command sees TRACK_CONFIG=%q, want %q", got, value)
+	}
+}
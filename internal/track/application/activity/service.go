@@ -0,0 +1,144 @@
+// internal/application/activity/service.go
+package activity
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"track/internal/track/domain/activity"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/series"
+	primary "track/internal/track/ports/primary/activity"
+	ratingPort "track/internal/track/ports/primary/rating"
+	"track/internal/track/ports/secondary"
+)
+
+var _ primary.Service = (*Service)(nil)
+
+// Service imports commits and meetings as daily metrics and compares them with the ratings
+type Service struct {
+	ratings  ratingPort.Service
+	metrics  secondary.MetricStore
+	commits  secondary.CommitLog
+	repos    []string
+	calendar secondary.CalendarReader
+	clock    secondary.Clock
+}
+
+// NewService imports the commits found in repos through commits, and meetings through calendar
+func NewService(ratings ratingPort.Service, metrics secondary.MetricStore, commits secondary.CommitLog, repos []string, calendar secondary.CalendarReader, clock secondary.Clock) *Service {
+	return &Service{
+		ratings:  ratings,
+		metrics:  metrics,
+		commits:  commits,
+		repos:    repos,
+		calendar: calendar,
+		clock:    clock,
+	}
+}
+
+func (s *Service) ImportCommits(ctx context.Context, since time.Time) (primary.Imported, error) {
+	if len(s.repos) == 0 {
+		return primary.Imported{}, rating.Errorf(rating.KindConfig, "no repositories to import commits from, set commits.repos in ~/.track.properties")
+	}
+	start, end, err := s.importRange(since)
+	if err != nil {
+		return primary.Imported{}, err
+	}
+
+	var found []activity.Commit
+	for _, repo := range s.repos {
+		commits, err := s.commits.Commits(ctx, repo, start)
+		if err != nil {
+			return primary.Imported{}, err
+		}
+		found = append(found, commits...)
+	}
+	counts := activity.CountByDay(found, start, end)
+	if err := s.metrics.Put(ctx, activity.Commits, start, end, counts); err != nil {
+		return primary.Imported{}, err
+	}
+	return imported(activity.Commits, len(s.repos), start, end, counts), nil
+}
+
+func (s *Service) ImportMeetings(ctx context.Context, paths []string, since time.Time) (primary.Imported, error) {
+	if len(paths) == 0 {
+		return primary.Imported{}, rating.Errorf(rating.KindValidation, "no calendar files to import")
+	}
+	start, end, err := s.importRange(since)
+	if err != nil {
+		return primary.Imported{}, err
+	}
+
+	var found []activity.Event
+	for _, path := range paths {
+		events, err := s.calendar.Events(ctx, path, start, end)
+		if err != nil {
+			return primary.Imported{}, err
+		}
+		found = append(found, events...)
+	}
+	count, hours := activity.MeetingsByDay(found, start, end)
+	if err := s.metrics.Put(ctx, activity.Meetings, start, end, count); err != nil {
+		return primary.Imported{}, err
+	}
+	if err := s.metrics.Put(ctx, activity.MeetingHours, start, end, hours); err != nil {
+		return primary.Imported{}, err
+	}
+	report := imported(activity.Meetings, len(paths), start, end, count)
+	for _, h := range hours {
+		report.Hours += h
+	}
+	return report, nil
+}
+
+// importRange is the days an import from since replaces, up to today
+func (s *Service) importRange(since time.Time) (start, end time.Time, err error) {
+	start, end = rating.DayStart(since), rating.DayEnd(s.clock.Now())
+	if end.Before(start) {
+		return start, end, rating.Errorf(rating.KindValidation, "%s is in the future", rating.DayID(since))
+	}
+	return start, end, nil
+}
+
+// imported summarises the values put from start to end
+func imported(metric string, sources int, start, end time.Time, values map[time.Time]float64) primary.Imported {
+	report := primary.Imported{Metric: metric, Sources: sources, Start: start, End: end}
+	for _, v := range values {
+		report.Items += int(v)
+		if v > 0 {
+			report.Days++
+		}
+	}
+	return report
+}
+
+func (s *Service) Metrics(ctx context.Context, start, end time.Time) ([]series.Series, error) {
+	return s.metrics.Get(ctx, start, end)
+}
+
+func (s *Service) Insights(ctx context.Context, start, end time.Time) ([]activity.Insight, error) {
+	if end.Before(start) {
+		return nil, rating.Errorf(rating.KindValidation, "the range ends before it starts")
+	}
+	metrics, err := s.metrics.Get(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(metrics) == 0 {
+		return nil, rating.Errorf(rating.KindNotFound, "no metrics imported, see track import --help")
+	}
+	ratings, err := s.ratings.GetDateRangeRatings(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("reading ratings: %w", err)
+	}
+	if len(ratings) == 0 {
+		return nil, rating.Errorf(rating.KindNotFound, "no ratings between %s and %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	insights := make([]activity.Insight, 0, len(metrics))
+	for _, m := range metrics {
+		insights = append(insights, activity.Compare(m, ratings))
+	}
+	return insights, nil
+}
@@ -0,0 +1,100 @@
+package activity
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/file"
+	"track/internal/track/adapters/secondary/memory"
+	ratingService "track/internal/track/application/rating"
+	"track/internal/track/domain/activity"
+	"track/internal/track/domain/rating"
+)
+
+// fakeLog has the same commits in every repository, as clones of one would
+type fakeLog map[string]time.Time
+
+func (l fakeLog) Commits(_ context.Context, _ string, since time.Time) ([]activity.Commit, error) {
+	var commits []activity.Commit
+	for hash, at := range l {
+		if !at.Before(since) {
+			commits = append(commits, activity.Commit{Hash: hash, At: at})
+		}
+	}
+	return commits, nil
+}
+
+// fakeCalendar has a meeting from 10 to 12 on the 17th and one from 14 to 15 on the 17th and 19th
+type fakeCalendar struct{}
+
+func (fakeCalendar) Events(_ context.Context, _ string, start, end time.Time) ([]activity.Event, error) {
+	at := func(d, hour int) time.Time { return time.Date(2025, time.February, d, hour, 0, 0, 0, time.UTC) }
+	return []activity.Event{
+		{UID: "review", Start: at(17, 10), End: at(17, 12)},
+		{UID: "sync", Start: at(17, 14), End: at(17, 15)},
+		{UID: "sync", Start: at(19, 14), End: at(19, 15)},
+	}, nil
+}
+
+func TestImportCommitsAndInsights(t *testing.T) {
+	ctx := context.Background()
+	day := func(d, hour int) time.Time { return time.Date(2025, time.February, d, hour, 0, 0, 0, time.UTC) }
+	clk := clock.NewFixed(day(20, 20))
+	ratings := ratingService.NewService(memory.NewMemoryRepository(), clk)
+	metrics := file.NewMetricStore(filepath.Join(t.TempDir(), "metrics.json"))
+	log := fakeLog{"a": day(17, 9), "b": day(17, 15), "c": day(18, 11), "d": day(20, 10), "old": day(1, 10)}
+
+	if _, err := NewService(ratings, metrics, log, nil, nil, clk).ImportCommits(ctx, day(16, 0)); rating.KindOf(err) != rating.KindConfig {
+		t.Errorf("ImportCommits without repositories = %v, want a config error", err)
+	}
+	service := NewService(ratings, metrics, log, []string{"work", "clone-of-work"}, fakeCalendar{}, clk)
+	if _, err := service.Insights(ctx, day(16, 0), clk.Now()); rating.KindOf(err) != rating.KindNotFound {
+		t.Errorf("Insights before an import = %v, want not found", err)
+	}
+
+	report, err := service.ImportCommits(ctx, day(16, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Sources != 2 || report.Items != 4 || report.Days != 3 {
+		t.Errorf("ImportCommits = %+v, want 4 commits on 3 days from 2 repositories", report)
+	}
+
+	for d, r := range map[int]rating.Rating{16: rating.Poor, 17: rating.Awesome, 18: rating.Good, 19: rating.Fair, 20: rating.Good} {
+		if _, err := ratings.CreateDayRating(ctx, day(d, 0), r, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	insights, err := service.Insights(ctx, day(16, 0), clk.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 1 || insights[0].Metric != activity.Commits || insights[0].Days != 5 {
+		t.Fatalf("Insights = %+v, want commits over 5 days", insights)
+	}
+	// Median 1: the three days with commits are high and felt good, the two without did not
+	if got := insights[0].Table; got != [2][2]int{{2, 0}, {0, 3}} {
+		t.Errorf("Table = %v, want 2 low and not good, 3 high and good", got)
+	}
+
+	report, err = service.ImportMeetings(ctx, []string{"work.ics"}, day(16, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Items != 3 || report.Hours != 4 || report.Days != 2 {
+		t.Errorf("ImportMeetings = %+v, want 3 meetings, 4 hours on 2 days", report)
+	}
+	insights, err = service.Insights(ctx, day(16, 0), clk.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights) != 3 || insights[1].Metric != activity.MeetingHours || insights[2].Metric != activity.Meetings {
+		t.Fatalf("Insights = %+v, want commits, meeting-hours and meetings", insights)
+	}
+	// Meeting-heavy are the 17th, Awesome, and the 19th, Fair
+	if in := insights[2]; in.HighRating != 4 || in.Table != [2][2]int{{1, 2}, {1, 1}} {
+		t.Errorf("meetings = %+v, want an average rating of 4 on the two meeting-heavy days", in)
+	}
+}
@@ -0,0 +1,145 @@
+// internal/application/backup/service.go
+package backup
+
+import (
+	"context"
+	"fmt"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/rating"
+	primary "track/internal/track/ports/primary/backup"
+	"track/internal/track/ports/secondary"
+)
+
+var _ primary.Service = (*Service)(nil)
+
+// Service takes, checks and restores snapshots of the ratings file
+type Service struct {
+	snapshots secondary.SnapshotStore
+	records   secondary.RecordReader
+	cipher    secondary.Cipher
+}
+
+// NewService compares snapshots with records, the ratings as stored now. Sealed days
+// are opened with cipher, so previews and checks see what they hold.
+func NewService(snapshots secondary.SnapshotStore, records secondary.RecordReader, cipher secondary.Cipher) *Service {
+	return &Service{snapshots: snapshots, records: records, cipher: cipher}
+}
+
+func (s *Service) Create(ctx context.Context) (backup.Snapshot, error) {
+	return s.snapshots.Create(ctx)
+}
+
+func (s *Service) List(ctx context.Context) ([]backup.Snapshot, error) {
+	return s.snapshots.List(ctx)
+}
+
+func (s *Service) Verify(ctx context.Context, id string) ([]primary.Verification, error) {
+	snapshots, err := s.snapshots.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if id != "" {
+		snapshots, err = pick(snapshots, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	verifications := make([]primary.Verification, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		v := primary.Verification{Snapshot: snapshot}
+		v.Problems, v.Err = s.check(ctx, snapshot.ID)
+		verifications = append(verifications, v)
+	}
+	return verifications, nil
+}
+
+func (s *Service) Preview(ctx context.Context, id string) ([]backup.Change, error) {
+	current, err := s.records.Records(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading ratings: %w", err)
+	}
+	if current, err = s.open(ctx, current); err != nil {
+		return nil, err
+	}
+	snapshot, err := s.snapshots.Records(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot, err = s.open(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	return backup.Diff(current, snapshot), nil
+}
+
+// Restore refuses a snapshot that fails its checksum, so a damaged copy never replaces the
+// ratings, and one encrypted or not unlike the ratings now, which would leave days in the
+// clear under encryption or sealed without a key
+func (s *Service) Restore(ctx context.Context, id string) (backup.Snapshot, error) {
+	if err := s.snapshots.Verify(ctx, id); err != nil {
+		return backup.Snapshot{}, err
+	}
+	records, err := s.snapshots.Records(ctx, id)
+	if err != nil {
+		return backup.Snapshot{}, err
+	}
+	sealed := 0
+	for _, dr := range records {
+		if s.cipher.Sealed(dr) {
+			sealed++
+		}
+	}
+	switch {
+	case s.cipher.Enabled() && sealed < len(records):
+		return backup.Snapshot{}, rating.Errorf(rating.KindConflict,
+			"snapshot %s holds days unencrypted and the ratings are encrypted: run track storage decrypt, restore it, then track storage encrypt", id)
+	case !s.cipher.Enabled() && sealed > 0:
+		return backup.Snapshot{}, rating.Errorf(rating.KindConflict,
+			"snapshot %s is encrypted and the ratings are not: put back the key file it was encrypted with to restore it", id)
+	}
+	return s.snapshots.Restore(ctx, id)
+}
+
+// check verifies the snapshot's checksum, then its records
+func (s *Service) check(ctx context.Context, id string) ([]rating.Problem, error) {
+	if err := s.snapshots.Verify(ctx, id); err != nil {
+		return nil, err
+	}
+	records, err := s.snapshots.Records(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if records, err = s.open(ctx, records); err != nil {
+		return nil, err
+	}
+	return rating.Check(records), nil
+}
+
+// open decrypts the sealed records, snapshots may predate encryption or follow it
+func (s *Service) open(ctx context.Context, records map[string]rating.DayRating) (map[string]rating.DayRating, error) {
+	if !s.cipher.Enabled() {
+		return records, nil
+	}
+	opened := make(map[string]rating.DayRating, len(records))
+	for key, dr := range records {
+		if !s.cipher.Sealed(dr) {
+			opened[key] = dr
+			continue
+		}
+		day, err := s.cipher.Open(ctx, dr)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting %s: %w", key, err)
+		}
+		opened[key] = day
+	}
+	return opened, nil
+}
+
+func pick(snapshots []backup.Snapshot, id string) ([]backup.Snapshot, error) {
+	for _, snapshot := range snapshots {
+		if snapshot.ID == id {
+			return []backup.Snapshot{snapshot}, nil
+		}
+	}
+	return nil, backup.ErrNotFound
+}
@@ -0,0 +1,124 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/crypt"
+	"track/internal/track/adapters/secondary/file"
+	"track/internal/track/adapters/secondary/repotest"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/rating"
+)
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	source := filepath.Join(dir, "ratings.json")
+	repo, err := file.OpenFileRepository(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots := file.NewSnapshots(filepath.Join(dir, "backups"), source, backup.DefaultPolicy())
+	// No key file, so nothing is sealed
+	keys := crypt.NewKeyFile(filepath.Join(dir, "ratings.key"), crypt.Passphrases{}, crypt.DefaultParams)
+	service := NewService(snapshots, repo, keys)
+
+	monday := repotest.Day(2025, time.February, 17, rating.Good)
+	if err := repo.Save(ctx, monday); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := service.Create(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Delete(ctx, monday.ID); err != nil {
+		t.Fatal(err)
+	}
+	tuesday := repotest.Day(2025, time.February, 18, rating.Fair)
+	if err := repo.Save(ctx, tuesday); err != nil {
+		t.Fatal(err)
+	}
+
+	verifications, err := service.Verify(ctx, "")
+	if err != nil || len(verifications) != 1 || verifications[0].Err != nil || len(verifications[0].Problems) != 0 {
+		t.Errorf("Verify = %+v, %v, want one sound snapshot", verifications, err)
+	}
+	changes, err := service.Preview(ctx, snapshot.ID)
+	if err != nil || len(changes) != 2 || changes[0].Current != nil || changes[1].Snapshot != nil {
+		t.Errorf("Preview = %+v, %v, want Monday back and Tuesday gone", changes, err)
+	}
+
+	undo, err := service.Restore(ctx, snapshot.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByID(ctx, monday.ID); err != nil {
+		t.Errorf("GetByID(Monday) after Restore = %v", err)
+	}
+	if _, err := repo.GetByID(ctx, tuesday.ID); !errors.Is(err, rating.ErrNotFound) {
+		t.Errorf("GetByID(Tuesday) after Restore = %v, want not found", err)
+	}
+	// Restoring the snapshot taken before undoes the restore
+	if _, err := service.Restore(ctx, undo.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByID(ctx, tuesday.ID); err != nil {
+		t.Errorf("GetByID(Tuesday) after undoing = %v", err)
+	}
+
+	if _, err := service.Restore(ctx, "20200101T000000Z"); rating.KindOf(err) != rating.KindNotFound {
+		t.Errorf("Restore of an unknown snapshot = %v, want not found", err)
+	}
+}
+
+func TestRestoreRefusesMismatchedEncryption(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	source := filepath.Join(dir, "ratings.json")
+	repo, err := file.OpenFileRepository(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots := file.NewSnapshots(filepath.Join(dir, "backups"), source, backup.DefaultPolicy())
+	passphrase := crypt.Passphrases{Getenv: func(string) string { return "correct horse" }}
+	keys := crypt.NewKeyFile(filepath.Join(dir, "ratings.key"), passphrase, crypt.Params{Time: 1, Memory: 64, Threads: 1})
+	service := NewService(snapshots, repo, keys)
+
+	monday := repotest.Day(2025, time.February, 17, rating.Good)
+	if err := repo.Save(ctx, monday); err != nil {
+		t.Fatal(err)
+	}
+	plain, err := service.Create(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := keys.Create(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sealedMonday := monday
+	sealedMonday.Revision++
+	if err := crypt.NewRepository(repo, keys).Save(ctx, sealedMonday); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Restore(ctx, plain.ID); rating.KindOf(err) != rating.KindConflict {
+		t.Errorf("Restore of an unencrypted snapshot while encrypted = %v, want a conflict", err)
+	}
+	if raw, _ := repo.GetByID(ctx, monday.ID); !keys.Sealed(raw) {
+		t.Error("the refused restore replaced the encrypted ratings")
+	}
+
+	encrypted, err := service.Create(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.Remove(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Restore(ctx, encrypted.ID); rating.KindOf(err) != rating.KindConflict {
+		t.Errorf("Restore of an encrypted snapshot without a key = %v, want a conflict", err)
+	}
+}
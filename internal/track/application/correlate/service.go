@@ -0,0 +1,80 @@
+// internal/application/correlate/service.go
+package correlate
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/series"
+	primary "track/internal/track/ports/primary/correlate"
+	ratingPort "track/internal/track/ports/primary/rating"
+	"track/internal/track/ports/secondary"
+)
+
+var _ primary.Service = (*Service)(nil)
+
+// Service relates dated series to the ratings
+type Service struct {
+	ratings ratingPort.Service
+	reader  secondary.SeriesReader
+}
+
+func NewService(ratings ratingPort.Service, reader secondary.SeriesReader) *Service {
+	return &Service{ratings: ratings, reader: reader}
+}
+
+func (s *Service) Correlate(ctx context.Context, paths []string, opts primary.Options) ([]primary.Result, error) {
+	if opts.End.Before(opts.Start) {
+		return nil, rating.Errorf(rating.KindValidation, "the range ends before it starts")
+	}
+	all, err := s.read(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := s.ratings.GetDateRangeRatings(ctx, opts.Start, opts.End)
+	if err != nil {
+		return nil, fmt.Errorf("reading ratings: %w", err)
+	}
+	if len(ratings) == 0 {
+		return nil, rating.Errorf(rating.KindNotFound, "no ratings between %s and %s", opts.Start.Format("2006-01-02"), opts.End.Format("2006-01-02"))
+	}
+
+	lags := opts.Lags
+	if len(lags) == 0 {
+		lags = []int{0}
+	}
+	var results []primary.Result
+	for _, ser := range all {
+		for _, lag := range lags {
+			pairs := series.Align(ratings, ser, lag)
+			results = append(results, primary.Result{
+				Correlation: series.Correlate(ser.Name, lag, pairs),
+				Quantiles:   series.Quantiles(pairs, max(opts.Quantiles, 1)),
+				Scatter:     series.NewScatter(pairs, opts.Bins),
+			})
+		}
+	}
+	return results, nil
+}
+
+// read returns the series of every file, a name repeated across files prefixed with its file's
+func (s *Service) read(ctx context.Context, paths []string) ([]series.Series, error) {
+	var all []series.Series
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		found, err := s.reader.Read(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, ser := range found {
+			if seen[ser.Name] {
+				ser.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + "." + ser.Name
+			}
+			seen[ser.Name] = true
+			all = append(all, ser)
+		}
+	}
+	return all, nil
+}
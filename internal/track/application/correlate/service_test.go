@@ -0,0 +1,57 @@
+package correlate
+
+import (
+	"context"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/memory"
+	ratingService "track/internal/track/application/rating"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/series"
+	primary "track/internal/track/ports/primary/correlate"
+)
+
+// fakeReader returns a steps series per file, rising by the day
+type fakeReader struct{}
+
+func (fakeReader) Read(_ context.Context, path string) ([]series.Series, error) {
+	s := series.Series{Name: "steps", Values: make(map[time.Time]float64)}
+	for d := 10; d <= 20; d++ {
+		s.Values[time.Date(2025, time.February, d, 0, 0, 0, 0, time.UTC)] = float64(d * 1000)
+	}
+	return []series.Series{s}, nil
+}
+
+func TestCorrelate(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFixed(time.Date(2025, time.February, 20, 20, 0, 0, 0, time.UTC))
+	ratings := ratingService.NewService(memory.NewMemoryRepository(), clk)
+	service := NewService(ratings, fakeReader{})
+	opts := primary.Options{
+		Start: time.Date(2025, time.February, 14, 0, 0, 0, 0, time.UTC),
+		End:   clk.Now(),
+		Lags:  []int{0, 2},
+	}
+
+	if _, err := service.Correlate(ctx, []string{"steps.csv"}, opts); rating.KindOf(err) != rating.KindNotFound {
+		t.Errorf("Correlate without ratings = %v, want not found", err)
+	}
+	// Ratings rising with the days, so with the steps too
+	for d, r := range map[int]rating.Rating{14: rating.Bad, 15: rating.Poor, 17: rating.Fair, 18: rating.Good, 20: rating.Awesome} {
+		if _, err := ratings.CreateDayRating(ctx, time.Date(2025, time.February, d, 0, 0, 0, 0, time.UTC), r, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := service.Correlate(ctx, []string{"a/steps.csv", "b/walks.csv"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 || results[2].Series != "walks.steps" || results[1].Lag != 2 {
+		t.Fatalf("Correlate = %+v, want steps and walks.steps at lags 0 and 2", results)
+	}
+	if r := results[0]; r.N != 5 || r.Spearman != 1 || len(r.Quantiles) != 1 {
+		t.Errorf("steps at lag 0 = %+v, want a perfect rank correlation over 5 days", r)
+	}
+}
@@ -0,0 +1,78 @@
+// internal/application/goals/service.go
+package goals
+
+import (
+	"context"
+	"fmt"
+	"track/internal/track/domain/goal"
+	"track/internal/track/domain/rating"
+	primary "track/internal/track/ports/primary/goals"
+	ratingPort "track/internal/track/ports/primary/rating"
+	"track/internal/track/ports/secondary"
+)
+
+var _ primary.Service = (*Service)(nil)
+
+// Service keeps the goals and measures the ratings against them
+type Service struct {
+	repo    secondary.GoalRepository
+	ratings ratingPort.Service
+	clock   secondary.Clock
+}
+
+func NewService(repo secondary.GoalRepository, ratings ratingPort.Service, clock secondary.Clock) *Service {
+	return &Service{
+		repo:    repo,
+		ratings: ratings,
+		clock:   clock,
+	}
+}
+
+// AddGoal validates and stores a new goal
+func (s *Service) AddGoal(ctx context.Context, kind goal.Kind, period goal.Period, target float64) (goal.Goal, error) {
+	g, err := goal.New(kind, period, target)
+	if err != nil {
+		return goal.Goal{}, err
+	}
+	g.CreatedAt = s.clock.Now()
+	return s.repo.Add(ctx, g)
+}
+
+// RemoveGoal deletes the goal with id
+func (s *Service) RemoveGoal(ctx context.Context, id string) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("goal %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListGoals returns every goal in the order they were added
+func (s *Service) ListGoals(ctx context.Context) ([]goal.Goal, error) {
+	return s.repo.List(ctx)
+}
+
+// GetProgress evaluates every goal over its period containing today
+func (s *Service) GetProgress(ctx context.Context) ([]goal.Progress, error) {
+	goals, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	// Goals share periods, so each period's ratings are read once
+	ratings := make(map[goal.Period][]rating.DayRating)
+	var progress []goal.Progress
+	for _, g := range goals {
+		if _, ok := ratings[g.Period]; !ok {
+			start, end := g.Period.Bounds(now)
+			// end is the last day at midnight, the day itself may be rated at any time
+			days, err := s.ratings.GetDateRangeRatings(ctx, start, rating.DayEnd(end))
+			if err != nil {
+				return nil, fmt.Errorf("getting ratings for this %s: %w", g.Period, err)
+			}
+			ratings[g.Period] = days
+		}
+		progress = append(progress, goal.Evaluate(g, ratings[g.Period], now))
+	}
+	return progress, nil
+}
@@ -0,0 +1,105 @@
+package goals
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/memory"
+	ratingService "track/internal/track/application/rating"
+	"track/internal/track/domain/goal"
+	"track/internal/track/domain/rating"
+)
+
+func TestGetProgress(t *testing.T) {
+	ctx := context.Background()
+	// Wednesday of ISO week 8
+	clk := clock.NewFixed(time.Date(2025, time.February, 19, 20, 0, 0, 0, time.UTC))
+	ratings := ratingService.NewService(memory.NewMemoryRepository(), clk)
+	service := NewService(memory.NewGoalRepository(), ratings, clk)
+
+	for day, r := range map[int]rating.Rating{3: rating.Awesome, 17: rating.Bad, 18: rating.Fair, 19: rating.Bad} {
+		if _, err := ratings.CreateDayRating(ctx, time.Date(2025, time.February, day, 0, 0, 0, 0, time.UTC), r, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, g := range []goal.Goal{
+		{Kind: goal.KindAverage, Period: goal.PeriodMonth, Target: 2.5},
+		{Kind: goal.KindMaxBad, Period: goal.PeriodWeek, Target: 2},
+		{Kind: goal.KindEveryDay, Period: goal.PeriodWeek},
+		{Kind: goal.KindEveryDay, Period: goal.PeriodMonth},
+	} {
+		if _, err := service.AddGoal(ctx, g.Kind, g.Period, g.Target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	progress, err := service.GetProgress(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []goal.Status{goal.StatusOnTrack, goal.StatusAtRisk, goal.StatusOnTrack, goal.StatusMissed}
+	if len(progress) != len(want) {
+		t.Fatalf("got %d goals, want %d", len(progress), len(want))
+	}
+	for i, p := range progress {
+		if p.Status != want[i] {
+			t.Errorf("%s: %s, want %s", p.Goal, p.Status, want[i])
+		}
+	}
+	if progress[0].Current != 2.5 || progress[0].Rated != 4 {
+		t.Errorf("month average = %g over %d days, want 2.5 over 4", progress[0].Current, progress[0].Rated)
+	}
+}
+
+func TestGetProgressCountsTheLastDayRatedLater(t *testing.T) {
+	ctx := context.Background()
+	// Sunday evening, the last day of ISO week 8
+	clk := clock.NewFixed(time.Date(2025, time.February, 23, 20, 0, 0, 0, time.UTC))
+	ratings := ratingService.NewService(memory.NewMemoryRepository(), clk)
+	service := NewService(memory.NewGoalRepository(), ratings, clk)
+
+	for day := 17; day < 23; day++ {
+		if _, err := ratings.CreateDayRating(ctx, time.Date(2025, time.February, day, 0, 0, 0, 0, time.UTC), rating.Good, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ratings.AddCheckIn(ctx, clk.Now(), rating.Good, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddGoal(ctx, goal.KindEveryDay, goal.PeriodWeek, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	progress, err := service.GetProgress(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := progress[0]; p.Status != goal.StatusMet || p.Rated != 7 {
+		t.Errorf("%s: %s with %d days rated, want met with 7", p.Goal, p.Status, p.Rated)
+	}
+}
+
+func TestAddAndRemoveGoal(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFixed(time.Date(2025, time.February, 19, 20, 0, 0, 0, time.UTC))
+	service := NewService(memory.NewGoalRepository(), ratingService.NewService(memory.NewMemoryRepository(), clk), clk)
+
+	if _, err := service.AddGoal(ctx, goal.KindAverage, goal.PeriodWeek, 7); rating.KindOf(err) != rating.KindValidation {
+		t.Errorf("AddGoal(average 7) = %v, want a validation error", err)
+	}
+	g, err := service.AddGoal(ctx, goal.KindAverage, goal.PeriodWeek, 3.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.CreatedAt.Equal(clk.Now()) {
+		t.Errorf("CreatedAt = %v, want the clock's time", g.CreatedAt)
+	}
+	if err := service.RemoveGoal(ctx, g.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.RemoveGoal(ctx, g.ID); !errors.Is(err, goal.ErrNotFound) {
+		t.Errorf("removing twice = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,131 @@
+// internal/application/prompt/service.go
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"track/internal/track/domain/prompt"
+	"track/internal/track/domain/rating"
+	primary "track/internal/track/ports/primary/prompt"
+	ratingPort "track/internal/track/ports/primary/rating"
+	"track/internal/track/ports/secondary"
+)
+
+var _ primary.Service = (*Service)(nil)
+
+// Service renders prompt segments, caching them until the ratings change or the day does
+type Service struct {
+	ratings ratingPort.Service
+	data    secondary.Versioned
+	cache   secondary.PromptCache
+	clock   secondary.Clock
+	cipher  secondary.Cipher
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithCipher makes segments only tell whether today is rated while cipher encrypts the
+// ratings: reading them would ask for the passphrase at every prompt
+func WithCipher(cipher secondary.Cipher) Option {
+	return func(s *Service) {
+		s.cipher = cipher
+	}
+}
+
+// NewService caches segments against data's version, data may be nil to never cache
+func NewService(ratings ratingPort.Service, data secondary.Versioned, cache secondary.PromptCache, clock secondary.Clock, opts ...Option) *Service {
+	s := &Service{
+		ratings: ratings,
+		data:    data,
+		cache:   cache,
+		clock:   clock,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Segment renders format for today, or unrated while today is not rated.
+// A cache hit costs a stat and a small file read, a miss reads the ratings.
+func (s *Service) Segment(ctx context.Context, format, unrated string) (string, error) {
+	version := s.version(ctx)
+	key := format + "\x00" + unrated
+	if version != "" {
+		// A broken cache only costs speed, so its errors count as misses
+		if segment, ok, err := s.cache.Get(ctx, version, key); err == nil && ok {
+			return segment, nil
+		}
+	}
+
+	data, err := s.segmentData(ctx)
+	if err != nil {
+		return "", err
+	}
+	segment, err := prompt.Render(format, unrated, data)
+	if err != nil {
+		return "", err
+	}
+
+	if version != "" {
+		_ = s.cache.Put(ctx, version, key, segment)
+	}
+	return segment, nil
+}
+
+// version is the data version on today's date, empty when it can't be known
+func (s *Service) version(ctx context.Context) string {
+	if s.data == nil {
+		return ""
+	}
+	v, err := s.data.Version(ctx)
+	if err != nil {
+		return ""
+	}
+	return rating.DayID(s.clock.Now()) + "/" + v
+}
+
+func (s *Service) segmentData(ctx context.Context) (prompt.Segment, error) {
+	now := s.clock.Now()
+	data := prompt.Segment{DayID: rating.DayID(now)}
+
+	if s.cipher != nil && s.cipher.Enabled() {
+		rated, err := s.ratings.IsDayRated(ctx, now)
+		if err != nil {
+			return data, fmt.Errorf("checking today's rating: %w", err)
+		}
+		data.Rated = rated
+		return data, nil
+	}
+
+	today, err := s.ratings.GetTodayRating(ctx)
+	switch {
+	case err == nil:
+		data.Rated = true
+		data.Rating = int(today.Rating)
+		data.Label = today.Rating.String()
+		data.Emoji = today.Rating.Emoji()
+	case !errors.Is(err, rating.ErrNotFound):
+		return data, fmt.Errorf("getting today's rating: %w", err)
+	}
+
+	year, week := now.ISOWeek()
+	current, err := s.ratings.GetWeekSummary(ctx, year, week)
+	if err != nil {
+		return data, fmt.Errorf("getting week summary: %w", err)
+	}
+	lastYear, lastWeek := now.AddDate(0, 0, -7).ISOWeek()
+	previous, err := s.ratings.GetWeekSummary(ctx, lastYear, lastWeek)
+	if err != nil {
+		return data, fmt.Errorf("getting last week's summary: %w", err)
+	}
+
+	data.Days = current.DayCount
+	if current.DayCount > 0 {
+		data.Average = fmt.Sprintf("%.1f", current.Average)
+		data.Trend = prompt.Trend(current.Average, previous.Average, previous.DayCount)
+	}
+	return data, nil
+}
@@ -0,0 +1,77 @@
+package prompt
+
+import (
+	"context"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/memory"
+	ratingService "track/internal/track/application/rating"
+	"track/internal/track/domain/prompt"
+	"track/internal/track/domain/rating"
+	ratingPort "track/internal/track/ports/primary/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// countingService counts the reads that a cached segment should avoid
+type countingService struct {
+	ratingPort.Service
+	reads int
+}
+
+func (c *countingService) GetTodayRating(ctx context.Context) (rating.DayRating, error) {
+	c.reads++
+	return c.Service.GetTodayRating(ctx)
+}
+
+func TestSegment(t *testing.T) {
+	ctx := context.Background()
+	// Wednesday of ISO week 8
+	clk := clock.NewFixed(time.Date(2025, time.February, 19, 9, 0, 0, 0, time.UTC))
+	repo := memory.NewMemoryRepository()
+	ratings := &countingService{Service: ratingService.NewService(repo, clk)}
+	service := NewService(ratings, repo.(secondary.Versioned), memory.NewPromptCache(), clk)
+
+	segment := func() string {
+		t.Helper()
+		s, err := service.Segment(ctx, prompt.DefaultFormat, prompt.DefaultUnrated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	rate := func(year int, month time.Month, day int, r rating.Rating) {
+		t.Helper()
+		if _, err := ratings.CreateDayRating(ctx, time.Date(year, month, day, 0, 0, 0, 0, time.UTC), r, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := segment(); got != "⚠ unrated" {
+		t.Errorf("unrated segment = %q", got)
+	}
+
+	rate(2025, time.February, 12, rating.Fair)
+	rate(2025, time.February, 18, rating.Good)
+	rate(2025, time.February, 19, rating.Awesome)
+	if got := segment(); got != "🤩 4.5↑" {
+		t.Errorf("segment = %q, want 🤩 4.5↑", got)
+	}
+	reads := ratings.reads
+	if got := segment(); got != "🤩 4.5↑" || ratings.reads != reads {
+		t.Errorf("second segment = %q after %d more reads, want it from the cache", got, ratings.reads-reads)
+	}
+
+	// The next day is unrated, whatever the cache holds
+	clk.Advance(24 * time.Hour)
+	if got := segment(); got != "⚠ unrated" {
+		t.Errorf("next day's segment = %q", got)
+	}
+
+	if _, err := service.Segment(ctx, "{{.Emoji", prompt.DefaultUnrated); rating.KindOf(err) != rating.KindValidation {
+		t.Errorf("broken format = %v, want a validation error", err)
+	}
+	if _, err := service.Segment(ctx, prompt.DefaultFormat, "{{.Mood}}"); rating.KindOf(err) != rating.KindValidation {
+		t.Errorf("unknown field = %v, want a validation error", err)
+	}
+}
@@ -3,36 +3,89 @@ package rating
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 	"track/internal/track/domain/rating"
+	primary "track/internal/track/ports/primary/rating"
 	"track/internal/track/ports/secondary"
 )
 
+var _ primary.Service = (*Service)(nil)
+
 type Service struct {
-	repo secondary.RatingRepository
+	repo        secondary.RatingRepository
+	clock       secondary.Clock
+	wall        secondary.Clock
+	hostname    string
+	aggregation rating.Aggregation
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithHostname records host on the ratings the service creates
+func WithHostname(host string) Option {
+	return func(s *Service) {
+		s.hostname = host
+	}
 }
 
-func NewService(repo secondary.RatingRepository) *Service {
-	return &Service{
-		repo: repo,
+// WithWallClock stamps CreatedAt and UpdatedAt from wall rather than the service's clock,
+// which --as-of pins to another day while the rating is still entered now
+func WithWallClock(wall secondary.Clock) Option {
+	return func(s *Service) {
+		s.wall = wall
 	}
 }
 
-// SetDayRating creates a new rating for a specific day
-func (s *Service) SetDayRating(ctx context.Context, date time.Time, r rating.Rating) (rating.DayRating, error) {
+// WithAggregation sets how days with several check-ins are rated, the last check-in by default
+func WithAggregation(a rating.Aggregation) Option {
+	return func(s *Service) {
+		s.aggregation = a
+	}
+}
+
+func NewService(repo secondary.RatingRepository, clock secondary.Clock, opts ...Option) *Service {
+	s := &Service{
+		repo:        repo,
+		clock:       clock,
+		wall:        clock,
+		aggregation: rating.AggregateLast,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateDayRating rates a day that has no rating yet, with an optional note.
+// The rating records when it was entered, the source set on ctx and the hostname.
+func (s *Service) CreateDayRating(ctx context.Context, date time.Time, r rating.Rating, note string) (rating.DayRating, error) {
 	if !r.IsValid() {
 		return rating.DayRating{}, rating.ErrInvalidRating
 	}
 
-	_, week := date.ISOWeek()
+	existing, err := s.GetDayRating(ctx, date)
+	switch {
+	case err == nil:
+		return rating.DayRating{}, fmt.Errorf("%s is %w %s", existing.ID, rating.ErrAlreadyRated, existing.Rating)
+	case !errors.Is(err, rating.ErrNotFound):
+		return rating.DayRating{}, fmt.Errorf("checking existing rating: %w", err)
+	}
 
-	dayId := fmt.Sprintf("%sw%02d-%d", date.Format("06"), week, date.Weekday())
-	//fmt.Printf("weekday %s", dayId)
+	now := s.wall.Now()
 	dayRating := rating.DayRating{
-		ID:     dayId,
-		Date:   date,
-		Rating: r,
+		ID:        rating.DayID(date),
+		Date:      date,
+		Rating:    r,
+		Note:      note,
+		Revision:  1,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Source:    primary.SourceFrom(ctx),
+		Host:      s.hostname,
 	}
 
 	// Save to repository
@@ -43,45 +96,135 @@ func (s *Service) SetDayRating(ctx context.Context, date time.Time, r rating.Rat
 	return dayRating, nil
 }
 
+// SetDayRating creates the day's rating or overwrites an existing one, check-ins included,
+// for callers that asked to overwrite
+func (s *Service) SetDayRating(ctx context.Context, date time.Time, r rating.Rating, note string) (rating.DayRating, error) {
+	dayRating, err := s.CreateDayRating(ctx, date, r, note)
+	if !errors.Is(err, rating.ErrAlreadyRated) {
+		return dayRating, err
+	}
+
+	current, err := s.repo.GetByID(ctx, rating.DayID(date))
+	if err != nil {
+		return rating.DayRating{}, fmt.Errorf("getting %s: %w", rating.DayID(date), err)
+	}
+	current.CheckIns = nil
+	return s.replace(ctx, current, r, note)
+}
+
+// AddCheckIn records a rating given at a moment of the day, creating the day if it is not rated.
+// A rating set on the day directly becomes its first check-in.
+func (s *Service) AddCheckIn(ctx context.Context, at time.Time, r rating.Rating, note string) (rating.DayRating, error) {
+	if !r.IsValid() {
+		return rating.DayRating{}, rating.ErrInvalidRating
+	}
+	checkIn := rating.CheckIn{At: at, Rating: r, Note: note}
+	now := s.wall.Now()
+
+	dayRating, err := s.repo.GetByID(ctx, rating.DayID(at))
+	switch {
+	case errors.Is(err, rating.ErrNotFound):
+		dayRating = rating.DayRating{
+			ID:        rating.DayID(at),
+			Date:      at,
+			CreatedAt: now,
+			Source:    primary.SourceFrom(ctx),
+			Host:      s.hostname,
+		}
+	case err != nil:
+		return rating.DayRating{}, fmt.Errorf("getting %s: %w", rating.DayID(at), err)
+	case len(dayRating.CheckIns) == 0:
+		first := rating.CheckIn{At: dayRating.CreatedAt, Rating: dayRating.Rating}
+		if first.At.IsZero() || rating.DayStart(first.At.In(at.Location())).After(rating.DayStart(at)) {
+			// Entered late or at an unknown time, so place it at the start of the day
+			first.At = rating.DayStart(at)
+		}
+		dayRating.CheckIns = append(dayRating.CheckIns, first)
+	}
+
+	dayRating.CheckIns = append(dayRating.CheckIns, checkIn)
+	rating.SortCheckIns(dayRating.CheckIns)
+	dayRating.Rating = s.aggregation.Apply(dayRating.CheckIns)
+	dayRating.Revision++
+	dayRating.UpdatedAt = now
+
+	if err := s.repo.Save(ctx, dayRating); err != nil {
+		return rating.DayRating{}, fmt.Errorf("saving check-in: %w", err)
+	}
+	return dayRating, nil
+}
+
+// GetDayRating gets the rating for a specific day
+func (s *Service) GetDayRating(ctx context.Context, date time.Time) (rating.DayRating, error) {
+	dayRating, err := s.repo.GetByID(ctx, rating.DayID(date))
+	if err != nil {
+		return rating.DayRating{}, err
+	}
+	return s.resolve(dayRating), nil
+}
+
+// resolve rates a day with check-ins by the configured aggregation, which may have
+// changed since the day was saved
+func (s *Service) resolve(dr rating.DayRating) rating.DayRating {
+	if len(dr.CheckIns) > 0 {
+		dr.Rating = s.aggregation.Apply(dr.CheckIns)
+	}
+	return dr
+}
+
+func (s *Service) resolveAll(ratings []rating.DayRating) []rating.DayRating {
+	for i := range ratings {
+		ratings[i] = s.resolve(ratings[i])
+	}
+	return ratings
+}
+
+// IsDayRated reports whether date is rated without reading its rating, which for
+// encrypted ratings would need the passphrase
+func (s *Service) IsDayRated(ctx context.Context, date time.Time) (bool, error) {
+	if prober, ok := s.repo.(secondary.Prober); ok {
+		return prober.Exists(ctx, rating.DayID(date))
+	}
+	_, err := s.repo.GetByID(ctx, rating.DayID(date))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, rating.ErrNotFound):
+		return false, nil
+	}
+	return false, err
+}
+
 // GetTodayRating gets the rating for the current day
 func (s *Service) GetTodayRating(ctx context.Context) (rating.DayRating, error) {
-	today := time.Now()
-	_, week := today.ISOWeek()
-	id := fmt.Sprintf("%sw%02d-%d", today.Format("06"), week, today.Weekday())
-
-	return s.repo.GetByID(ctx, id)
+	return s.GetDayRating(ctx, s.clock.Now())
 }
 
 // GetWeekRatings gets all ratings for a specific week
 func (s *Service) GetWeekRatings(ctx context.Context, year, week int) ([]rating.DayRating, error) {
-	return s.repo.GetByWeek(ctx, year, week)
+	ratings, err := s.repo.GetByWeek(ctx, year, week)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolveAll(ratings), nil
 }
 
 // GetCurrentWeekRatings gets all ratings for the current week
 func (s *Service) GetCurrentWeekRatings(ctx context.Context) ([]rating.DayRating, error) {
-	now := time.Now()
+	now := s.clock.Now()
 	year, week := now.ISOWeek()
 	return s.GetWeekRatings(ctx, year, week)
 }
 
 // GetWeekSummary provides a summary of ratings for a specific week
-type WeekSummary struct {
-	Year     int
-	Week     int
-	Average  float64
-	Best     rating.DayRating
-	Worst    rating.DayRating
-	DayCount int
-}
-
-func (s *Service) GetWeekSummary(ctx context.Context, year, week int) (WeekSummary, error) {
-	ratings, err := s.repo.GetByWeek(ctx, year, week)
+func (s *Service) GetWeekSummary(ctx context.Context, year, week int) (rating.WeekSummary, error) {
+	ratings, err := s.GetWeekRatings(ctx, year, week)
 	if err != nil {
-		return WeekSummary{}, fmt.Errorf("getting week ratings: %w", err)
+		return rating.WeekSummary{}, fmt.Errorf("getting week ratings: %w", err)
 	}
 
 	if len(ratings) == 0 {
-		return WeekSummary{
+		return rating.WeekSummary{
 			Year: year,
 			Week: week,
 		}, nil
@@ -102,7 +245,7 @@ func (s *Service) GetWeekSummary(ctx context.Context, year, week int) (WeekSumma
 		}
 	}
 
-	return WeekSummary{
+	return rating.WeekSummary{
 		Year:     year,
 		Week:     week,
 		Average:  float64(sum) / float64(len(ratings)),
@@ -115,36 +258,230 @@ func (s *Service) GetWeekSummary(ctx context.Context, year, week int) (WeekSumma
 // GetDateRangeRatings gets all ratings between start and end dates
 func (s *Service) GetDateRangeRatings(ctx context.Context, start, end time.Time) ([]rating.DayRating, error) {
 	if end.Before(start) {
-		return nil, fmt.Errorf("end date before start date")
+		return nil, rating.Errorf(rating.KindValidation, "end date before start date")
 	}
 
-	return s.repo.GetByDateRange(ctx, start, end)
+	ratings, err := s.repo.GetByDateRange(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ratings, func(i, j int) bool { return ratings[i].Date.Before(ratings[j].Date) })
+
+	return s.resolveAll(ratings), nil
 }
 
-// UpdateTodayRating updates the rating for the current day
-func (s *Service) UpdateTodayRating(ctx context.Context, r rating.Rating) (rating.DayRating, error) {
+// GetWeeklyTrend returns the average rating of each of the weeks up to and including end's week, oldest first
+func (s *Service) GetWeeklyTrend(ctx context.Context, end time.Time, weeks int) ([]rating.WeekTrend, error) {
+	start := rating.DayStart(end).AddDate(0, 0, -7*(weeks-1))
+	ratings, err := s.GetDateRangeRatings(ctx, start.AddDate(0, 0, -7), rating.DayEnd(end))
+	if err != nil {
+		return nil, fmt.Errorf("getting trend data: %w", err)
+	}
+
+	type key struct{ year, week int }
+	sums := make(map[key]int)
+	counts := make(map[key]int)
+	for _, dr := range ratings {
+		y, w := dr.Date.ISOWeek()
+		sums[key{y, w}] += int(dr.Rating)
+		counts[key{y, w}]++
+	}
+
+	trend := make([]rating.WeekTrend, 0, weeks)
+	for i := weeks - 1; i >= 0; i-- {
+		y, w := end.AddDate(0, 0, -7*i).ISOWeek()
+		wt := rating.WeekTrend{Year: y, Week: w, Count: counts[key{y, w}]}
+		if wt.Count > 0 {
+			wt.Average = float64(sums[key{y, w}]) / float64(wt.Count)
+		}
+		trend = append(trend, wt)
+	}
+
+	return trend, nil
+}
+
+// GetWeekdayBreakdown returns the average rating for each weekday between start and end, Monday first
+func (s *Service) GetWeekdayBreakdown(ctx context.Context, start, end time.Time) ([]rating.WeekdayStat, error) {
+	ratings, err := s.GetDateRangeRatings(ctx, rating.DayStart(start), rating.DayEnd(end))
+	if err != nil {
+		return nil, fmt.Errorf("getting weekday data: %w", err)
+	}
+
+	var sums, counts [7]int
+	for _, dr := range ratings {
+		sums[dr.Date.Weekday()] += int(dr.Rating)
+		counts[dr.Date.Weekday()]++
+	}
+
+	breakdown := make([]rating.WeekdayStat, 0, 7)
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7)
+		ws := rating.WeekdayStat{Weekday: day, Count: counts[day]}
+		if ws.Count > 0 {
+			ws.Average = float64(sums[day]) / float64(ws.Count)
+		}
+		breakdown = append(breakdown, ws)
+	}
+
+	return breakdown, nil
+}
+
+// GetMetrics takes a snapshot of today's rating, trailing averages, the streak and totals
+func (s *Service) GetMetrics(ctx context.Context) (rating.Metrics, error) {
+	now := s.clock.Now()
+	metrics := rating.Metrics{DaysSinceLast: -1}
+
+	if today, err := s.GetDayRating(ctx, now); err == nil {
+		metrics.Today = today.Rating
+	} else if !errors.Is(err, rating.ErrNotFound) {
+		return rating.Metrics{}, fmt.Errorf("getting today's rating: %w", err)
+	}
+
+	all, err := s.GetStats(ctx, time.Time{}, now, rating.StatsOptions{})
+	if err != nil {
+		return rating.Metrics{}, err
+	}
+	metrics.Total = all.Count
+	metrics.Counts = all.Distribution
+	metrics.Streak = all.CurrentStreak
+
+	week, err := s.GetStats(ctx, now.AddDate(0, 0, -6), now, rating.StatsOptions{})
+	if err != nil {
+		return rating.Metrics{}, err
+	}
+	metrics.Average7d, metrics.Count7d = week.Average, week.Count
+
+	month, err := s.GetStats(ctx, now.AddDate(0, 0, -29), now, rating.StatsOptions{})
+	if err != nil {
+		return rating.Metrics{}, err
+	}
+	metrics.Average30d, metrics.Count30d = month.Average, month.Count
+
+	ratings, err := s.GetDateRangeRatings(ctx, time.Time{}, rating.DayEnd(now))
+	if err != nil {
+		return rating.Metrics{}, err
+	}
+	if len(ratings) > 0 {
+		last := ratings[len(ratings)-1]
+		metrics.DaysSinceLast = int(rating.DayStart(now).Sub(rating.DayStart(last.Date)).Hours()+12) / 24
+	}
+
+	return metrics, nil
+}
+
+// UpdateDayRating changes the rating and note of a day that has already been rated.
+// revision is the one the caller last read, 0 updates whatever is stored. Days rated
+// from check-ins fail with rating.ErrHasCheckIns, add a check-in or use SetDayRating.
+func (s *Service) UpdateDayRating(ctx context.Context, date time.Time, r rating.Rating, note string, revision int) (rating.DayRating, error) {
 	if !r.IsValid() {
 		return rating.DayRating{}, rating.ErrInvalidRating
 	}
 
-	today := time.Now()
-	_, week := today.ISOWeek()
-	dayRating := rating.DayRating{
-		ID:     fmt.Sprintf("%sw%02d-%d", today.Format("06"), week, today.Weekday()),
-		Date:   today,
-		Rating: r,
+	dayRating, err := s.repo.GetByID(ctx, rating.DayID(date))
+	if err != nil {
+		return rating.DayRating{}, fmt.Errorf("getting %s: %w", rating.DayID(date), err)
+	}
+	if revision != 0 && revision != dayRating.Revision {
+		return rating.DayRating{}, fmt.Errorf("%s is at revision %d, not %d: %w", dayRating.ID, dayRating.Revision, revision, rating.ErrStaleRevision)
+	}
+	if len(dayRating.CheckIns) > 0 {
+		return rating.DayRating{}, fmt.Errorf("%s is %w (%d)", dayRating.ID, rating.ErrHasCheckIns, len(dayRating.CheckIns))
 	}
 
+	return s.replace(ctx, dayRating, r, note)
+}
+
+// replace saves dayRating as its next revision with the new rating and note
+func (s *Service) replace(ctx context.Context, dayRating rating.DayRating, r rating.Rating, note string) (rating.DayRating, error) {
+	dayRating.Rating = r
+	dayRating.Note = note
+	dayRating.Revision++
+	dayRating.UpdatedAt = s.wall.Now()
+
 	if err := s.repo.Save(ctx, dayRating); err != nil {
-		return rating.DayRating{}, fmt.Errorf("updating today's rating: %w", err)
+		return rating.DayRating{}, fmt.Errorf("updating day rating: %w", err)
 	}
 
 	return dayRating, nil
 }
 
+// DeleteDayRating removes the rating for a specific day
+func (s *Service) DeleteDayRating(ctx context.Context, date time.Time) error {
+	if err := s.repo.Delete(ctx, rating.DayID(date)); err != nil {
+		return fmt.Errorf("deleting day rating: %w", err)
+	}
+	return nil
+}
+
+// GetStats summarises the ratings between the start and end days, inclusive
+func (s *Service) GetStats(ctx context.Context, start, end time.Time, opts rating.StatsOptions) (rating.Stats, error) {
+	all, err := s.GetDateRangeRatings(ctx, rating.DayStart(start), rating.DayEnd(end))
+	if err != nil {
+		return rating.Stats{}, fmt.Errorf("getting stats ratings: %w", err)
+	}
+
+	stats := rating.Stats{
+		Start:        rating.DayStart(start),
+		End:          rating.DayStart(end),
+		Distribution: make(map[rating.Rating]int),
+	}
+	ratings := make([]rating.DayRating, 0, len(all))
+	for _, dr := range all {
+		if dr.RatedLate() {
+			stats.Late++
+			if opts.ExcludeLate {
+				continue
+			}
+		}
+		ratings = append(ratings, dr)
+	}
+	stats.Count = len(ratings)
+	if len(ratings) == 0 {
+		return stats, nil
+	}
+
+	var sum int
+	streak := 0
+	var previous time.Time
+	for _, dr := range ratings {
+		sum += int(dr.Rating)
+		stats.Distribution[dr.Rating]++
+
+		day := rating.DayStart(dr.Date)
+		if !previous.IsZero() && day.Equal(previous.AddDate(0, 0, 1)) {
+			streak++
+		} else if !day.Equal(previous) {
+			streak = 1
+		}
+		previous = day
+		if streak > stats.LongestStreak {
+			stats.LongestStreak = streak
+		}
+	}
+	stats.Average = float64(sum) / float64(len(ratings))
+
+	// The current streak is still alive if the last rating was on the end day or the day before it
+	if !previous.Before(stats.End.AddDate(0, 0, -1)) {
+		stats.CurrentStreak = streak
+	}
+
+	return stats, nil
+}
+
+// UpdateTodayRating changes the rating for the current day, keeping its note
+func (s *Service) UpdateTodayRating(ctx context.Context, r rating.Rating) (rating.DayRating, error) {
+	today := s.clock.Now()
+	current, err := s.GetDayRating(ctx, today)
+	if err != nil {
+		return rating.DayRating{}, fmt.Errorf("getting today's rating: %w", err)
+	}
+	return s.UpdateDayRating(ctx, today, r, current.Note, current.Revision)
+}
+
+// GetLastRatingBefore finds the most recent rating in the 30 days before date
 func (s *Service) GetLastRatingBefore(ctx context.Context, date time.Time) (rating.DayRating, error) {
 	// Implementation to get the last rating before the given date
-	ratings, err := s.repo.GetByDateRange(ctx, date.AddDate(0, 0, -30), date)
+	ratings, err := s.GetDateRangeRatings(ctx, date.AddDate(0, 0, -30), date)
 	if err != nil {
 		return rating.DayRating{}, err
 	}
@@ -159,24 +496,38 @@ func (s *Service) GetLastRatingBefore(ctx context.Context, date time.Time) (rati
 	return lastRating, nil
 }
 
+// GetMissingDays lists the days strictly between start and end that have no rating
+func (s *Service) GetMissingDays(ctx context.Context, start, end time.Time) ([]time.Time, error) {
+	var missing []time.Time
+
+	for current := start.AddDate(0, 0, 1); current.Before(end); current = current.AddDate(0, 0, 1) {
+		_, err := s.repo.GetByID(ctx, rating.DayID(current))
+		switch {
+		case errors.Is(err, rating.ErrNotFound):
+			missing = append(missing, current)
+		case err != nil:
+			return missing, err
+		}
+	}
+
+	return missing, nil
+}
+
+// FillMissingRatings rates every unrated day strictly between start and end with r
 func (s *Service) FillMissingRatings(ctx context.Context, start, end time.Time, r rating.Rating) ([]rating.DayRating, error) {
 	var filled []rating.DayRating
 
-	current := start.AddDate(0, 0, 1)
-	for current.Before(end) {
-		_, week := current.ISOWeek()
-		// Check if rating exists for this day
-		id := fmt.Sprintf("%sw%02d-%d", current.Format("06"), week, current.Weekday())
-		_, err := s.repo.GetByID(ctx, id)
-		if err == rating.ErrNotFound {
-			// Add rating for this day
-			dayRating, err := s.SetDayRating(ctx, current, r)
-			if err != nil {
-				return filled, err
-			}
-			filled = append(filled, dayRating)
+	missing, err := s.GetMissingDays(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	ctx = primary.WithSource(ctx, rating.SourceFill)
+	for _, day := range missing {
+		dayRating, err := s.CreateDayRating(ctx, day, r, "")
+		if err != nil {
+			return filled, err
 		}
-		current = current.AddDate(0, 0, 1)
+		filled = append(filled, dayRating)
 	}
 
 	return filled, nil
@@ -0,0 +1,259 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/memory"
+	"track/internal/track/domain/rating"
+	primary "track/internal/track/ports/primary/rating"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// newTestService seeds a Fair rating on each of the given days
+func newTestService(t *testing.T, now time.Time, days ...time.Time) (*Service, *clock.Fixed) {
+	t.Helper()
+	clk := clock.NewFixed(now)
+	service := NewService(memory.NewMemoryRepository(), clk)
+	for _, day := range days {
+		if _, err := service.CreateDayRating(context.Background(), day, rating.Fair, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return service, clk
+}
+
+func TestGetCurrentWeekRatings(t *testing.T) {
+	seeded := []time.Time{
+		date(2024, time.December, 29),
+		date(2024, time.December, 30),
+		date(2025, time.January, 5),
+		date(2025, time.January, 6),
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want []string
+	}{
+		{"sunday before year end", time.Date(2024, time.December, 29, 21, 0, 0, 0, time.UTC), []string{"24w52-0"}},
+		{"monday of week one in december", time.Date(2024, time.December, 30, 8, 0, 0, 0, time.UTC), []string{"25w01-1", "25w01-0"}},
+		{"new year's day", time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC), []string{"25w01-1", "25w01-0"}},
+		{"week two", time.Date(2025, time.January, 7, 12, 0, 0, 0, time.UTC), []string{"25w02-1"}},
+		{"empty week", time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService(t, tt.now, seeded...)
+			got, err := service.GetCurrentWeekRatings(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d ratings, want %v", len(got), tt.want)
+			}
+			for i := range got {
+				if got[i].ID != tt.want[i] {
+					t.Errorf("rating %d = %s, want %s", i, got[i].ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTodayFollowsClock(t *testing.T) {
+	service, clk := newTestService(t, time.Date(2025, time.February, 17, 22, 30, 0, 0, time.UTC), date(2025, time.February, 17))
+	ctx := context.Background()
+
+	if _, err := service.UpdateTodayRating(ctx, rating.Good); err != nil {
+		t.Fatal(err)
+	}
+	today, err := service.GetTodayRating(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if today.ID != "25w08-1" || today.Rating != rating.Good {
+		t.Errorf("GetTodayRating = %v, want 25w08-1 Good", today)
+	}
+
+	clk.Advance(2 * time.Hour)
+	if _, err := service.GetTodayRating(ctx); !errors.Is(err, rating.ErrNotFound) {
+		t.Errorf("GetTodayRating after midnight = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetStatsStreaks(t *testing.T) {
+	tests := []struct {
+		name              string
+		days              []time.Time
+		end               time.Time
+		count, cur, total int
+	}{
+		{"no ratings", nil, date(2025, time.March, 10), 0, 0, 0},
+		{
+			"streak ending today",
+			[]time.Time{date(2025, time.March, 8), date(2025, time.March, 9), date(2025, time.March, 10)},
+			date(2025, time.March, 10), 3, 3, 3,
+		},
+		{
+			"streak ending yesterday is still current",
+			[]time.Time{date(2025, time.March, 8), date(2025, time.March, 9)},
+			date(2025, time.March, 10), 2, 2, 2,
+		},
+		{
+			"broken streak",
+			[]time.Time{date(2025, time.March, 1), date(2025, time.March, 2), date(2025, time.March, 3), date(2025, time.March, 7)},
+			date(2025, time.March, 10), 4, 0, 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService(t, tt.end, tt.days...)
+			stats, err := service.GetStats(context.Background(), date(2025, time.March, 1), tt.end, rating.StatsOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Count != tt.count || stats.CurrentStreak != tt.cur || stats.LongestStreak != tt.total {
+				t.Errorf("stats = count %d, current %d, longest %d, want %d, %d, %d",
+					stats.Count, stats.CurrentStreak, stats.LongestStreak, tt.count, tt.cur, tt.total)
+			}
+		})
+	}
+}
+
+func TestGetMetricsDaysSinceLast(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{"rated today", time.Date(2025, time.March, 10, 20, 0, 0, 0, time.UTC), 0},
+		{"rated two days ago", time.Date(2025, time.March, 12, 8, 0, 0, 0, time.UTC), 2},
+		{"long gap", time.Date(2025, time.June, 8, 8, 0, 0, 0, time.UTC), 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService(t, tt.now, date(2025, time.March, 10))
+			metrics, err := service.GetMetrics(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if metrics.DaysSinceLast != tt.want {
+				t.Errorf("DaysSinceLast = %d, want %d", metrics.DaysSinceLast, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvenance(t *testing.T) {
+	now := time.Date(2025, time.February, 19, 21, 0, 0, 0, time.UTC)
+	clk := clock.NewFixed(now)
+	service := NewService(memory.NewMemoryRepository(), clk, WithHostname("laptop"))
+	ctx := primary.WithSource(context.Background(), rating.SourceCLI)
+
+	today, err := service.CreateDayRating(ctx, now, rating.Good, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !today.CreatedAt.Equal(now) || today.Source != rating.SourceCLI || today.Host != "laptop" || today.RatedLate() {
+		t.Errorf("today = created %v by %q on %q late %v, want now by cli on laptop, on time",
+			today.CreatedAt, today.Source, today.Host, today.RatedLate())
+	}
+
+	backfilled, err := service.CreateDayRating(ctx, date(2025, time.February, 12), rating.Poor, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !backfilled.RatedLate() {
+		t.Error("a rating entered a week later is not marked late")
+	}
+
+	clk.Advance(time.Hour)
+	updated, err := service.UpdateDayRating(ctx, now, rating.Awesome, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.CreatedAt.Equal(now) || !updated.UpdatedAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("updated = created %v updated %v, want %v and an hour later", updated.CreatedAt, updated.UpdatedAt, now)
+	}
+
+	filled, err := service.FillMissingRatings(ctx, date(2025, time.February, 16), date(2025, time.February, 18), rating.Fair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filled) != 1 || filled[0].Source != rating.SourceFill {
+		t.Errorf("filled = %v, want one rating from fill", filled)
+	}
+
+	stats, err := service.GetStats(ctx, date(2025, time.February, 10), now, rating.StatsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	onTime, err := service.GetStats(ctx, date(2025, time.February, 10), now, rating.StatsOptions{ExcludeLate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Count != 3 || stats.Late != 2 || onTime.Count != 1 || onTime.Late != 2 {
+		t.Errorf("counts = %d (%d late), excluding late %d (%d late), want 3 (2), 1 (2)",
+			stats.Count, stats.Late, onTime.Count, onTime.Late)
+	}
+}
+
+func TestCheckInAggregation(t *testing.T) {
+	repo := memory.NewMemoryRepository()
+	clk := clock.NewFixed(time.Date(2025, time.February, 17, 21, 0, 0, 0, time.UTC))
+	mean := NewService(repo, clk, WithAggregation(rating.AggregateMean))
+	ctx := context.Background()
+
+	for hour, r := range map[int]rating.Rating{8: rating.Awesome, 13: rating.Poor, 20: rating.Good} {
+		if _, err := mean.AddCheckIn(ctx, time.Date(2025, time.February, 17, hour, 0, 0, 0, time.UTC), r, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	summary, err := mean.GetWeekSummary(ctx, 2025, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.DayCount != 1 || summary.Average != 4 {
+		t.Errorf("mean summary = %d days averaging %.1f, want 1 day of 4.0", summary.DayCount, summary.Average)
+	}
+
+	// The aggregation applies when reading, so changing it re-rates stored days
+	min := NewService(repo, clk, WithAggregation(rating.AggregateMin))
+	day, err := min.GetDayRating(ctx, date(2025, time.February, 17))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if day.Rating != rating.Poor || len(day.CheckIns) != 3 {
+		t.Errorf("min rating = %v from %d check-ins, want Poor from 3", day.Rating, len(day.CheckIns))
+	}
+
+	if _, err := min.UpdateDayRating(ctx, date(2025, time.February, 17), rating.Bad, "", 0); !errors.Is(err, rating.ErrHasCheckIns) {
+		t.Errorf("UpdateDayRating on a checked-in day = %v, want ErrHasCheckIns", err)
+	}
+	forced, err := min.SetDayRating(ctx, date(2025, time.February, 17), rating.Bad, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forced.Rating != rating.Bad || len(forced.CheckIns) != 0 {
+		t.Errorf("SetDayRating = %v with %d check-ins, want Bad with none", forced.Rating, len(forced.CheckIns))
+	}
+}
+
+func TestCheckInKeepsDirectRating(t *testing.T) {
+	service, _ := newTestService(t, time.Date(2025, time.February, 17, 21, 0, 0, 0, time.UTC), date(2025, time.February, 17))
+
+	day, err := service.AddCheckIn(context.Background(), time.Date(2025, time.February, 17, 22, 0, 0, 0, time.UTC), rating.Good, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(day.CheckIns) != 2 || day.CheckIns[0].Rating != rating.Fair || day.Rating != rating.Good {
+		t.Errorf("check-ins = %+v rated %v, want the Fair rating kept first and Good last", day.CheckIns, day.Rating)
+	}
+}
@@ -0,0 +1,130 @@
+// internal/application/remind/service.go
+package remind
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/remind"
+	ratingPort "track/internal/track/ports/primary/rating"
+	primary "track/internal/track/ports/primary/remind"
+	"track/internal/track/ports/secondary"
+)
+
+var _ primary.Service = (*Service)(nil)
+
+type Service struct {
+	ratings    ratingPort.Service
+	notifier   secondary.Notifier
+	snooze     secondary.SnoozeStore
+	clock      secondary.Clock
+	schedule   remind.Schedule
+	schedulers map[remind.SchedulerKind]secondary.Scheduler
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithSchedule sets when reminders are due, 20:00 every day by default
+func WithSchedule(schedule remind.Schedule) Option {
+	return func(s *Service) {
+		s.schedule = schedule
+	}
+}
+
+// WithScheduler makes scheduler available to Install and Uninstall as kind
+func WithScheduler(kind remind.SchedulerKind, scheduler secondary.Scheduler) Option {
+	return func(s *Service) {
+		s.schedulers[kind] = scheduler
+	}
+}
+
+func NewService(ratings ratingPort.Service, notifier secondary.Notifier, snooze secondary.SnoozeStore, clock secondary.Clock, opts ...Option) *Service {
+	s := &Service{
+		ratings:    ratings,
+		notifier:   notifier,
+		snooze:     snooze,
+		clock:      clock,
+		schedule:   remind.DefaultSchedule(),
+		schedulers: make(map[remind.SchedulerKind]secondary.Scheduler),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Check notifies if today is unrated past the reminder time and not snoozed or quiet.
+// It runs every few minutes from the scheduler, so each way out is cheap.
+func (s *Service) Check(ctx context.Context) (remind.Outcome, error) {
+	now := s.clock.Now()
+	if s.schedule.IsQuiet(now) {
+		return remind.OutcomeQuiet, nil
+	}
+	if now.Before(s.schedule.DueAt(now)) {
+		return remind.OutcomeEarly, nil
+	}
+
+	// Whether today is rated is enough, and doesn't ask for a passphrase from the scheduler
+	rated, err := s.ratings.IsDayRated(ctx, now)
+	if err != nil {
+		return "", fmt.Errorf("checking today's rating: %w", err)
+	}
+	if rated {
+		return remind.OutcomeRated, nil
+	}
+
+	until, err := s.snooze.SnoozedUntil(ctx)
+	if err != nil {
+		return "", fmt.Errorf("reading snooze: %w", err)
+	}
+	if now.Before(until) {
+		return remind.OutcomeSnoozed, nil
+	}
+
+	if err := s.notifier.Notify(ctx, "Rate your day", fmt.Sprintf("%s isn't rated yet: track day set", rating.DayID(now))); err != nil {
+		return "", fmt.Errorf("notifying: %w", err)
+	}
+	return remind.OutcomeNotified, nil
+}
+
+// Snooze holds back reminders for d and returns when they resume
+func (s *Service) Snooze(ctx context.Context, d time.Duration) (time.Time, error) {
+	if d <= 0 {
+		return time.Time{}, rating.Errorf(rating.KindValidation, "snooze for %s, expected a positive duration", d)
+	}
+	until := s.clock.Now().Add(d)
+	if err := s.snooze.SnoozeUntil(ctx, until); err != nil {
+		return time.Time{}, fmt.Errorf("saving snooze: %w", err)
+	}
+	return until, nil
+}
+
+func (s *Service) Schedule() remind.Schedule {
+	return s.schedule
+}
+
+func (s *Service) Install(ctx context.Context, kind remind.SchedulerKind) (string, error) {
+	scheduler, err := s.scheduler(kind)
+	if err != nil {
+		return "", err
+	}
+	return scheduler.Install(ctx)
+}
+
+func (s *Service) Uninstall(ctx context.Context, kind remind.SchedulerKind) (string, error) {
+	scheduler, err := s.scheduler(kind)
+	if err != nil {
+		return "", err
+	}
+	return scheduler.Uninstall(ctx)
+}
+
+func (s *Service) scheduler(kind remind.SchedulerKind) (secondary.Scheduler, error) {
+	scheduler, ok := s.schedulers[kind]
+	if !ok {
+		return nil, rating.Errorf(rating.KindValidation, "unknown scheduler %q, expected systemd or cron", kind)
+	}
+	return scheduler, nil
+}
@@ -0,0 +1,92 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/memory"
+	ratingService "track/internal/track/application/rating"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/remind"
+)
+
+// fakeNotifier records notifications instead of showing them
+type fakeNotifier struct {
+	messages []string
+	err      error
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, title, message string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.messages = append(f.messages, title+": "+message)
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	// Saturday 22 Feb 2025, before the reminder is due
+	clk := clock.NewFixed(time.Date(2025, time.February, 22, 19, 0, 0, 0, time.UTC))
+	ratings := ratingService.NewService(memory.NewMemoryRepository(), clk)
+	notifier := &fakeNotifier{}
+	schedule := remind.Schedule{Hour: 19, Minute: 30, Quiet: []time.Weekday{time.Sunday}}
+	service := NewService(ratings, notifier, memory.NewSnoozeStore(), clk, WithSchedule(schedule))
+
+	steps := []struct {
+		name string
+		do   func()
+		want remind.Outcome
+	}{
+		{"before the reminder time", func() {}, remind.OutcomeEarly},
+		{"due and unrated", func() { clk.Set(time.Date(2025, time.February, 22, 19, 30, 0, 0, time.UTC)) }, remind.OutcomeNotified},
+		{"snoozed", func() {
+			if _, err := service.Snooze(ctx, time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			clk.Advance(45 * time.Minute)
+		}, remind.OutcomeSnoozed},
+		{"snooze over", func() { clk.Advance(15 * time.Minute) }, remind.OutcomeNotified},
+		{"rated", func() {
+			if _, err := ratings.CreateDayRating(ctx, clk.Now(), rating.Good, ""); err != nil {
+				t.Fatal(err)
+			}
+		}, remind.OutcomeRated},
+		{"quiet Sunday", func() { clk.Advance(24 * time.Hour) }, remind.OutcomeQuiet},
+	}
+	for _, step := range steps {
+		step.do()
+		got, err := service.Check(ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got != step.want {
+			t.Errorf("%s: Check = %q, want %q", step.name, got, step.want)
+		}
+	}
+
+	if len(notifier.messages) != 2 {
+		t.Fatalf("notified %d times, want 2: %q", len(notifier.messages), notifier.messages)
+	}
+	if want := "Rate your day: 25w08-6 isn't rated yet: track day set"; notifier.messages[0] != want {
+		t.Errorf("notification = %q, want %q", notifier.messages[0], want)
+	}
+}
+
+func TestRemindErrors(t *testing.T) {
+	clk := clock.NewFixed(time.Date(2025, time.February, 22, 21, 0, 0, 0, time.UTC))
+	failing := &fakeNotifier{err: errors.New("no display")}
+	service := NewService(ratingService.NewService(memory.NewMemoryRepository(), clk), failing, memory.NewSnoozeStore(), clk)
+
+	if _, err := service.Check(context.Background()); err == nil || err.Error() != "notifying: no display" {
+		t.Errorf("Check = %v, want the notifier's error", err)
+	}
+	if _, err := service.Snooze(context.Background(), -time.Minute); rating.KindOf(err) != rating.KindValidation {
+		t.Errorf("Snooze(-1m) = %v, want a validation error", err)
+	}
+	if _, err := service.Install(context.Background(), "launchd"); rating.KindOf(err) != rating.KindValidation {
+		t.Errorf("Install(launchd) = %v, want a validation error", err)
+	}
+}
@@ -0,0 +1,28 @@
+// internal/application/replica/service.go
+package replica
+
+import (
+	"context"
+	"track/internal/track/domain/crdt"
+	"track/internal/track/domain/rating"
+	primary "track/internal/track/ports/primary/replica"
+	"track/internal/track/ports/secondary"
+)
+
+var _ primary.Service = (*Service)(nil)
+
+type Service struct {
+	store secondary.Replica
+}
+
+// NewService merges into store, which is nil when the ratings are not kept as a replica
+func NewService(store secondary.Replica) *Service {
+	return &Service{store: store}
+}
+
+func (s *Service) Merge(ctx context.Context, path string) ([]crdt.Change, error) {
+	if s.store == nil {
+		return nil, rating.Errorf(rating.KindConfig, "merging needs the ratings kept as a replica, set rating.store = crdt in ~/.track.properties")
+	}
+	return s.store.MergeFile(ctx, path)
+}
@@ -0,0 +1,51 @@
+package replica
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/file"
+	"track/internal/track/adapters/secondary/repotest"
+	"track/internal/track/domain/rating"
+)
+
+func TestMergeNeedsReplica(t *testing.T) {
+	_, err := NewService(nil).Merge(context.Background(), filepath.Join(t.TempDir(), "phone.json"))
+	if rating.KindOf(err) != rating.KindConfig {
+		t.Errorf("Merge without a replica = %v, want a config error", err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	phone, err := file.NewReplicaRepository(filepath.Join(dir, "phone.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	laptop, err := file.NewReplicaRepository(filepath.Join(dir, "laptop.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	monday := repotest.Day(2025, time.February, 17, rating.Good)
+	if err := phone.Save(ctx, monday); err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewService(laptop)
+	changes, err := service.Merge(ctx, filepath.Join(dir, "phone.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].ID != monday.ID || changes[0].Before != nil || changes[0].After == nil {
+		t.Fatalf("Merge = %+v, want Monday added", changes)
+	}
+	if got, err := laptop.GetByID(ctx, monday.ID); err != nil || got.Rating != rating.Good {
+		t.Errorf("laptop's Monday = %v, %v, want Good", got.Rating, err)
+	}
+
+	if changes, err := service.Merge(ctx, filepath.Join(dir, "phone.json")); err != nil || len(changes) != 0 {
+		t.Errorf("merging again = %+v, %v, want no changes", changes, err)
+	}
+}
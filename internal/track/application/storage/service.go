@@ -0,0 +1,167 @@
+// internal/application/storage/service.go
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/rating"
+	primary "track/internal/track/ports/primary/storage"
+	"track/internal/track/ports/secondary"
+)
+
+var _ primary.Service = (*Service)(nil)
+
+// Service re-stores every day when encryption is turned on or off, and checks them
+type Service struct {
+	stored    secondary.RatingRepository
+	cipher    secondary.Cipher
+	keys      secondary.KeyStore
+	records   secondary.RecordReader
+	snapshots secondary.SnapshotStore
+}
+
+// NewService works on stored, the backend as it is beneath the encryption, and reads
+// records, the same backend's records as they are stored. Encrypting leaves snapshots
+// as they were, so it looks for those still in the clear.
+func NewService(stored secondary.RatingRepository, cipher secondary.Cipher, keys secondary.KeyStore, records secondary.RecordReader, snapshots secondary.SnapshotStore) *Service {
+	return &Service{
+		stored:    stored,
+		cipher:    cipher,
+		keys:      keys,
+		records:   records,
+		snapshots: snapshots,
+	}
+}
+
+func (s *Service) Encrypt(ctx context.Context) (int, error) {
+	if !s.cipher.Enabled() {
+		if err := s.keys.Create(ctx); err != nil {
+			return 0, err
+		}
+	}
+	days, err := s.all(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sealed := 0
+	for _, dr := range days {
+		if s.cipher.Sealed(dr) {
+			continue
+		}
+		// The revision is sealed with the day, so it is bumped first
+		day := dr
+		day.Revision++
+		next, err := s.cipher.Seal(ctx, day)
+		if err != nil {
+			return sealed, fmt.Errorf("encrypting %s: %w", dr.ID, err)
+		}
+		if err := s.stored.Save(ctx, next); err != nil {
+			return sealed, fmt.Errorf("saving %s: %w", dr.ID, err)
+		}
+		sealed++
+	}
+	return sealed, nil
+}
+
+// Decrypt opens every day before removing the key, so a day that fails to open
+// leaves everything still encrypted
+func (s *Service) Decrypt(ctx context.Context) (int, error) {
+	if !s.cipher.Enabled() {
+		return 0, rating.Errorf(rating.KindConflict, "the ratings are not encrypted")
+	}
+	days, err := s.all(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	opened := make([]rating.DayRating, 0, len(days))
+	for _, dr := range days {
+		if !s.cipher.Sealed(dr) {
+			continue
+		}
+		day, err := s.cipher.Open(ctx, dr)
+		if err != nil {
+			return 0, fmt.Errorf("decrypting %s: %w", dr.ID, err)
+		}
+		day.Revision = dr.Revision + 1
+		opened = append(opened, day)
+	}
+	for i, dr := range opened {
+		if err := s.stored.Save(ctx, dr); err != nil {
+			return i, fmt.Errorf("saving %s: %w", dr.ID, err)
+		}
+	}
+	if err := s.keys.Remove(ctx); err != nil {
+		return len(opened), err
+	}
+	return len(opened), nil
+}
+
+func (s *Service) PlainSnapshots(ctx context.Context) ([]backup.Snapshot, error) {
+	snapshots, err := s.snapshots.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var plain []backup.Snapshot
+	for _, snapshot := range snapshots {
+		records, err := s.snapshots.Records(ctx, snapshot.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, dr := range records {
+			if !s.cipher.Sealed(dr) {
+				plain = append(plain, snapshot)
+				break
+			}
+		}
+	}
+	return plain, nil
+}
+
+func (s *Service) Rekey(ctx context.Context) error {
+	if !s.cipher.Enabled() {
+		return rating.Errorf(rating.KindConflict, "the ratings are not encrypted, run track storage encrypt first")
+	}
+	return s.keys.Rekey(ctx)
+}
+
+// Fsck reports the records that fail to decrypt along with those rating.Check finds fault with
+func (s *Service) Fsck(ctx context.Context) ([]rating.Problem, error) {
+	records, err := s.records.Records(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading ratings: %w", err)
+	}
+	if !s.cipher.Enabled() {
+		return rating.Check(records), nil
+	}
+
+	var unreadable []rating.Problem
+	opened := make(map[string]rating.DayRating, len(records))
+	for key, dr := range records {
+		day, err := s.cipher.Open(ctx, dr)
+		switch {
+		case err == nil:
+			opened[key] = day
+		case rating.KindOf(err) == rating.KindStorage:
+			unreadable = append(unreadable, rating.Problem{Key: key, Message: err.Error()})
+		default:
+			// Without the passphrase nothing can be checked
+			return nil, err
+		}
+	}
+	problems := append(rating.Check(opened), unreadable...)
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Key < problems[j].Key })
+	return problems, nil
+}
+
+func (s *Service) all(ctx context.Context) ([]rating.DayRating, error) {
+	days, err := s.stored.GetByDateRange(ctx, time.Time{}, time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, fmt.Errorf("reading ratings: %w", err)
+	}
+	return days, nil
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/crypt"
+	"track/internal/track/adapters/secondary/file"
+	"track/internal/track/adapters/secondary/memory"
+	"track/internal/track/adapters/secondary/repotest"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	stored := memory.NewMemoryRepository()
+	passphrase := crypt.Passphrases{Getenv: func(string) string { return "correct horse" }}
+	keys := crypt.NewKeyFile(filepath.Join(t.TempDir(), "ratings.key"), passphrase, crypt.Params{Time: 1, Memory: 64, Threads: 1})
+	service := NewService(stored, keys, keys, stored.(secondary.RecordReader), nil)
+	repo := crypt.NewRepository(stored, keys)
+
+	monday := repotest.Day(2025, time.February, 17, rating.Good)
+	monday.Note = "long walk"
+	for _, dr := range []rating.DayRating{monday, repotest.Day(2025, time.February, 18, rating.Fair)} {
+		if err := repo.Save(ctx, dr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := service.Encrypt(ctx); err != nil || n != 2 {
+		t.Fatalf("Encrypt = %d, %v, want 2 days sealed", n, err)
+	}
+	if raw, _ := stored.GetByID(ctx, monday.ID); !keys.Sealed(raw) || raw.Revision != 2 {
+		t.Errorf("stored after Encrypt = %+v, want sealed at revision 2", raw)
+	}
+	if got, err := repo.GetByID(ctx, monday.ID); err != nil || got.Note != "long walk" {
+		t.Errorf("GetByID while encrypted = %+v, %v", got, err)
+	}
+	if n, err := service.Encrypt(ctx); err != nil || n != 0 {
+		t.Errorf("Encrypt again = %d, %v, want nothing left to seal", n, err)
+	}
+
+	if n, err := service.Decrypt(ctx); err != nil || n != 2 {
+		t.Fatalf("Decrypt = %d, %v, want 2 days opened", n, err)
+	}
+	if keys.Enabled() {
+		t.Error("the key is still there after Decrypt")
+	}
+	if raw, _ := stored.GetByID(ctx, monday.ID); raw.Note != "long walk" || raw.Rating != rating.Good {
+		t.Errorf("stored after Decrypt = %+v, want the day in the clear", raw)
+	}
+	if _, err := service.Decrypt(ctx); rating.KindOf(err) != rating.KindConflict {
+		t.Errorf("Decrypt when not encrypted = %v, want a conflict", err)
+	}
+}
+
+func TestFsck(t *testing.T) {
+	ctx := context.Background()
+	stored := memory.NewMemoryRepository()
+	passphrase := crypt.Passphrases{Getenv: func(string) string { return "correct horse" }}
+	keys := crypt.NewKeyFile(filepath.Join(t.TempDir(), "ratings.key"), passphrase, crypt.Params{Time: 1, Memory: 64, Threads: 1})
+	service := NewService(stored, keys, keys, stored.(secondary.RecordReader), nil)
+
+	monday, tuesday := repotest.Day(2025, time.February, 17, rating.Good), repotest.Day(2025, time.February, 18, rating.Fair)
+	for _, dr := range []rating.DayRating{monday, tuesday} {
+		if err := stored.Save(ctx, dr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if problems, err := service.Fsck(ctx); err != nil || len(problems) != 0 {
+		t.Fatalf("Fsck = %v, %v, want no problems", problems, err)
+	}
+
+	if _, err := service.Encrypt(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Another day's sealed content copied over Tuesday's
+	sealed, _ := stored.GetByID(ctx, monday.ID)
+	moved, _ := stored.GetByID(ctx, tuesday.ID)
+	moved.Note, moved.Revision = sealed.Note, moved.Revision+1
+	if err := stored.Save(ctx, moved); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := service.Fsck(ctx)
+	if err != nil || len(problems) != 1 || problems[0].Key != tuesday.ID {
+		t.Errorf("Fsck after tampering = %v, %v, want one problem with %s", problems, err, tuesday.ID)
+	}
+}
+
+func TestPlainSnapshots(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	source := filepath.Join(dir, "ratings.json")
+	stored, err := file.OpenFileRepository(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots := file.NewSnapshots(filepath.Join(dir, "backups"), source, backup.DefaultPolicy())
+	passphrase := crypt.Passphrases{Getenv: func(string) string { return "correct horse" }}
+	keys := crypt.NewKeyFile(filepath.Join(dir, "ratings.key"), passphrase, crypt.Params{Time: 1, Memory: 64, Threads: 1})
+	service := NewService(stored, keys, keys, stored, snapshots)
+
+	if err := stored.Save(ctx, repotest.Day(2025, time.February, 17, rating.Good)); err != nil {
+		t.Fatal(err)
+	}
+	before, err := snapshots.Create(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Encrypt(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := service.PlainSnapshots(ctx)
+	if err != nil || len(plain) != 1 || plain[0].ID != before.ID {
+		t.Errorf("PlainSnapshots = %+v, %v, want %s from before encrypting", plain, err, before.ID)
+	}
+	// An encrypted snapshot replaces the day's unencrypted one
+	if _, err := snapshots.Create(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := service.PlainSnapshots(ctx); err != nil || len(plain) != 0 {
+		t.Errorf("PlainSnapshots after an encrypted snapshot = %+v, %v, want none", plain, err)
+	}
+}
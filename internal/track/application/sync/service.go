@@ -0,0 +1,98 @@
+// internal/application/sync/service.go
+package sync
+
+import (
+	"context"
+	"fmt"
+	"track/internal/track/domain/rating"
+	primary "track/internal/track/ports/primary/sync"
+	"track/internal/track/ports/secondary"
+)
+
+var _ primary.Service = (*Service)(nil)
+
+// Service merges ratings by day rather than letting the remote merge them as text
+type Service struct {
+	remote secondary.SyncRemote
+	cipher secondary.Cipher
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithCipher reseals the encrypted days a merge gives a new revision, which is sealed with them
+func WithCipher(cipher secondary.Cipher) Option {
+	return func(s *Service) {
+		s.cipher = cipher
+	}
+}
+
+func NewService(remote secondary.SyncRemote, opts ...Option) *Service {
+	s := &Service{remote: remote}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Sync pulls the remote's changes, merging them with local ones day by day, and pushes the result
+func (s *Service) Sync(ctx context.Context) (primary.Report, error) {
+	var report primary.Report
+
+	state, err := s.remote.Fetch(ctx)
+	if err != nil {
+		return report, fmt.Errorf("fetching: %w", err)
+	}
+
+	switch state.Relation {
+	case secondary.SyncUpToDate:
+		return report, nil
+	case secondary.SyncBehind:
+		if err := s.remote.FastForward(ctx); err != nil {
+			return report, fmt.Errorf("applying remote changes: %w", err)
+		}
+		report.Pulled = true
+		return report, nil
+	case secondary.SyncDiverged:
+		merged, conflicts := rating.Merge(state.Base, state.Ours, state.Theirs)
+		if err := s.reseal(ctx, merged, conflicts); err != nil {
+			return report, fmt.Errorf("merging: %w", err)
+		}
+		if err := s.remote.CommitMerge(ctx, merged); err != nil {
+			return report, fmt.Errorf("recording merge: %w", err)
+		}
+		report.Pulled, report.Merged, report.Conflicts = true, true, conflicts
+	}
+
+	if err := s.remote.Push(ctx); err != nil {
+		return report, fmt.Errorf("pushing: %w", err)
+	}
+	report.Pushed = true
+	return report, nil
+}
+
+// reseal seals again each encrypted day merged from a conflict, under the revision and
+// creation time the merge gave it, opening the side it was taken from
+func (s *Service) reseal(ctx context.Context, merged map[string]rating.DayRating, conflicts []rating.MergeConflict) error {
+	if s.cipher == nil {
+		return nil
+	}
+	for _, c := range conflicts {
+		if c.Result == nil || !s.cipher.Sealed(*c.Result) {
+			continue
+		}
+		source := c.Ours
+		if source == nil || source.Note != c.Result.Note {
+			source = c.Theirs
+		}
+		day, err := s.cipher.Open(ctx, *source)
+		if err != nil {
+			return err
+		}
+		day.Revision, day.CreatedAt = c.Result.Revision, c.Result.CreatedAt
+		if merged[c.ID], err = s.cipher.Seal(ctx, day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
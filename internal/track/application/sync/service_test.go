@@ -0,0 +1,132 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/crypt"
+	"track/internal/track/domain/rating"
+	"track/internal/track/ports/secondary"
+)
+
+// fakeRemote records which steps a sync took
+type fakeRemote struct {
+	state   secondary.SyncState
+	pushErr error
+	steps   []string
+	merged  map[string]rating.DayRating
+}
+
+func (f *fakeRemote) Fetch(context.Context) (secondary.SyncState, error) {
+	f.steps = append(f.steps, "fetch")
+	return f.state, nil
+}
+
+func (f *fakeRemote) FastForward(context.Context) error {
+	f.steps = append(f.steps, "fast-forward")
+	return nil
+}
+
+func (f *fakeRemote) CommitMerge(_ context.Context, merged map[string]rating.DayRating) error {
+	f.steps = append(f.steps, "merge")
+	f.merged = merged
+	return nil
+}
+
+func (f *fakeRemote) Push(context.Context) error {
+	f.steps = append(f.steps, "push")
+	return f.pushErr
+}
+
+func TestSync(t *testing.T) {
+	tests := []struct {
+		relation secondary.SyncRelation
+		steps    string
+	}{
+		{secondary.SyncUpToDate, "fetch"},
+		{secondary.SyncBehind, "fetch fast-forward"},
+		{secondary.SyncAhead, "fetch push"},
+		{secondary.SyncNoRemote, "fetch push"},
+		{secondary.SyncDiverged, "fetch merge push"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.relation), func(t *testing.T) {
+			remote := &fakeRemote{state: secondary.SyncState{Relation: tt.relation}}
+			if _, err := NewService(remote).Sync(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(remote.steps, " "); got != tt.steps {
+				t.Errorf("steps = %q, want %q", got, tt.steps)
+			}
+		})
+	}
+}
+
+func TestSyncMergesDays(t *testing.T) {
+	at := time.Date(2025, time.February, 17, 9, 0, 0, 0, time.UTC)
+	remote := &fakeRemote{state: secondary.SyncState{
+		Relation: secondary.SyncDiverged,
+		Ours:     map[string]rating.DayRating{"25w08-1": {ID: "25w08-1", Rating: rating.Good, Revision: 1, UpdatedAt: at}},
+		Theirs: map[string]rating.DayRating{
+			"25w08-1": {ID: "25w08-1", Rating: rating.Bad, Revision: 1, UpdatedAt: at.Add(time.Hour)},
+			"25w08-2": {ID: "25w08-2", Rating: rating.Fair, Revision: 1, UpdatedAt: at},
+		},
+	}}
+
+	report, err := NewService(remote).Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Merged || !report.Pushed || len(report.Conflicts) != 1 {
+		t.Errorf("report = %+v, want a pushed merge with one conflict", report)
+	}
+	if len(remote.merged) != 2 || remote.merged["25w08-1"].Rating != rating.Bad {
+		t.Errorf("merged = %v, want both days with the later Bad", remote.merged)
+	}
+}
+
+func TestSyncReseals(t *testing.T) {
+	ctx := context.Background()
+	passphrase := crypt.Passphrases{Getenv: func(string) string { return "correct horse" }}
+	keys := crypt.NewKeyFile(filepath.Join(t.TempDir(), "ratings.key"), passphrase, crypt.Params{Time: 1, Memory: 64, Threads: 1})
+	if err := keys.Create(ctx); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2025, time.February, 17, 9, 0, 0, 0, time.UTC)
+	seal := func(r rating.Rating, updated time.Time) rating.DayRating {
+		dr, err := keys.Seal(ctx, rating.DayRating{ID: "25w08-1", Date: at, Rating: r, Revision: 1, CreatedAt: updated, UpdatedAt: updated})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dr
+	}
+	remote := &fakeRemote{state: secondary.SyncState{
+		Relation: secondary.SyncDiverged,
+		Ours:     map[string]rating.DayRating{"25w08-1": seal(rating.Good, at)},
+		Theirs:   map[string]rating.DayRating{"25w08-1": seal(rating.Bad, at.Add(time.Hour))},
+	}}
+
+	if _, err := NewService(remote, WithCipher(keys)).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The merge gave the day revision 2 and our earlier creation time, sealed with it
+	merged := remote.merged["25w08-1"]
+	got, err := keys.Open(ctx, merged)
+	if err != nil {
+		t.Fatalf("the merged day fails to open: %v", err)
+	}
+	if got.Rating != rating.Bad || got.Revision != 2 || !got.CreatedAt.Equal(at) {
+		t.Errorf("merged day = %+v, want the later Bad at revision 2, created first", got)
+	}
+}
+
+func TestSyncPushError(t *testing.T) {
+	remote := &fakeRemote{state: secondary.SyncState{Relation: secondary.SyncAhead}, pushErr: errors.New("rejected")}
+	report, err := NewService(remote).Sync(context.Background())
+	if err == nil || report.Pushed {
+		t.Errorf("Sync = %+v, %v, want the push error", report, err)
+	}
+}
@@ -0,0 +1,82 @@
+// internal/application/team/service.go
+package team
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/team"
+	ratingPort "track/internal/track/ports/primary/rating"
+	primary "track/internal/track/ports/primary/team"
+	"track/internal/track/ports/secondary"
+)
+
+var _ primary.Service = (*Service)(nil)
+
+// Service exports this member's ratings to the team and reports on everyone's
+type Service struct {
+	dir      secondary.TeamDirectory
+	ratings  ratingPort.Service
+	clock    secondary.Clock
+	member   string
+	minGroup int
+}
+
+// NewService shares ratings through dir as member, reporting groups of minGroup or
+// more respondents. dir is nil when no team directory is configured.
+func NewService(dir secondary.TeamDirectory, ratings ratingPort.Service, clock secondary.Clock, member string, minGroup int) *Service {
+	return &Service{
+		dir:      dir,
+		ratings:  ratings,
+		clock:    clock,
+		member:   member,
+		minGroup: minGroup,
+	}
+}
+
+func (s *Service) Export(ctx context.Context) (int, error) {
+	if err := s.configured(); err != nil {
+		return 0, err
+	}
+	if s.member == "" {
+		return 0, rating.Errorf(rating.KindConfig, "no member name to export as, set team.member in ~/.track.properties")
+	}
+	days, err := s.ratings.GetDateRangeRatings(ctx, time.Time{}, rating.DayEnd(s.clock.Now()))
+	if err != nil {
+		return 0, fmt.Errorf("reading ratings: %w", err)
+	}
+
+	shared := make([]rating.DayRating, len(days))
+	for i, dr := range days {
+		shared[i] = rating.DayRating{ID: dr.ID, Date: dr.Date, Rating: dr.Rating}
+	}
+	if err := s.dir.Publish(ctx, s.member, shared); err != nil {
+		return 0, err
+	}
+	return len(shared), nil
+}
+
+func (s *Service) Report(ctx context.Context, start, end time.Time) (team.Report, error) {
+	if err := s.configured(); err != nil {
+		return team.Report{}, err
+	}
+	if end.Before(start) {
+		return team.Report{}, rating.Errorf(rating.KindValidation, "the report ends before it starts")
+	}
+	members, err := s.dir.Members(ctx)
+	if err != nil {
+		return team.Report{}, err
+	}
+	if len(members) == 0 {
+		return team.Report{}, rating.Errorf(rating.KindNotFound, "no member has exported ratings yet, run track team export")
+	}
+	return team.Aggregate(members, start, end, s.minGroup), nil
+}
+
+func (s *Service) configured() error {
+	if s.dir == nil {
+		return rating.Errorf(rating.KindConfig, "no team directory, set team.dir in ~/.track.properties")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package team
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	"track/internal/track/adapters/secondary/clock"
+	"track/internal/track/adapters/secondary/file"
+	"track/internal/track/adapters/secondary/memory"
+	ratingService "track/internal/track/application/rating"
+	"track/internal/track/domain/rating"
+)
+
+func TestExportAndReport(t *testing.T) {
+	ctx := context.Background()
+	dir := file.NewTeamDirectory(filepath.Join(t.TempDir(), "team"))
+	// Wednesday of ISO week 8
+	clk := clock.NewFixed(time.Date(2025, time.February, 19, 20, 0, 0, 0, time.UTC))
+
+	for i, member := range []string{"ann", "bob", "cem"} {
+		ratings := ratingService.NewService(memory.NewMemoryRepository(), clk)
+		monday := time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC)
+		if _, err := ratings.CreateDayRating(ctx, monday, rating.Rating(i+2), "private"); err != nil {
+			t.Fatal(err)
+		}
+		// A day after the report, not exported
+		if _, err := ratings.CreateDayRating(ctx, monday.AddDate(0, 0, 7), rating.Bad, ""); err != nil {
+			t.Fatal(err)
+		}
+		if n, err := NewService(dir, ratings, clk, member, 3).Export(ctx); err != nil || n != 1 {
+			t.Fatalf("Export as %s = %d, %v, want 1 day", member, n, err)
+		}
+	}
+
+	members, err := dir.Members(ctx)
+	if err != nil || len(members) != 3 || members[0].Ratings[0].Note != "" {
+		t.Fatalf("Members = %+v, %v, want three without notes", members, err)
+	}
+
+	service := NewService(dir, nil, clk, "", 3)
+	report, err := service.Report(ctx, clk.Now(), clk.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Weeks) != 1 || report.Weeks[0].Average != 3 || report.Weeks[0].Suppressed {
+		t.Errorf("Report weeks = %+v, want week 8 averaging 3", report.Weeks)
+	}
+	if report, err := NewService(dir, nil, clk, "", 4).Report(ctx, clk.Now(), clk.Now()); err != nil || !report.Weeks[0].Suppressed {
+		t.Errorf("Report with a minimum of 4 = %+v, %v, want week 8 suppressed", report.Weeks, err)
+	}
+
+	if _, err := NewService(nil, nil, clk, "", 3).Report(ctx, clk.Now(), clk.Now()); rating.KindOf(err) != rating.KindConfig {
+		t.Errorf("Report without a directory = %v, want a config error", err)
+	}
+	ratings := ratingService.NewService(memory.NewMemoryRepository(), clk)
+	if _, err := NewService(dir, ratings, clk, "../ann", 3).Export(ctx); rating.KindOf(err) != rating.KindValidation {
+		t.Errorf("Export as ../ann = %v, want a validation error", err)
+	}
+}
@@ -0,0 +1,285 @@
+// internal/config/config.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/prompt"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/remind"
+	"track/internal/track/domain/team"
+)
+
+// FileName is the configuration file looked for in the home directory
+const FileName = ".track.properties"
+
+// Store is how the ratings file is kept
+type Store string
+
+const (
+	// StoreJSON is a plain map of days, which track sync can share through git
+	StoreJSON Store = "json"
+	// StoreCRDT is a device's replica, which merges with other devices' through track merge
+	StoreCRDT Store = "crdt"
+)
+
+// Config holds the settings from the properties file, every key is optional
+type Config struct {
+	// RatingFile is where ratings are stored, key rating.file
+	RatingFile string
+	// Store is the format of the ratings file, key rating.store: json or crdt
+	Store Store
+	// Aggregation rates days that have several check-ins, key rating.aggregation
+	Aggregation rating.Aggregation
+	// GoalsFile is where goals are stored, key goals.file
+	GoalsFile string
+	// Remind is when to remind to rate the day, keys remind.at (HH:MM) and remind.quiet (sat,sun)
+	Remind remind.Schedule
+	// Notifier shows reminders, key remind.notifier: notify-send, bell or command
+	Notifier string
+	// NotifyCommand is the shell hook of the command notifier, key remind.command
+	NotifyCommand string
+	// PromptFormat and PromptUnrated are the track prompt templates, keys prompt.format and prompt.unrated
+	PromptFormat  string
+	PromptUnrated string
+	// KeyFile holds the key of encrypted ratings, key storage.key-file, by default the ratings file with .key added
+	KeyFile string
+	// PassphraseCommand prints the passphrase of encrypted ratings, key storage.passphrase-command
+	PassphraseCommand string
+	// BackupDir is where snapshots of the ratings file are kept, key backup.dir
+	BackupDir string
+	// Backup is how many snapshots are kept, keys backup.daily, backup.weekly and backup.monthly
+	Backup backup.Policy
+	// TeamDir is the directory a team shares its ratings in, key team.dir, none by default
+	TeamDir string
+	// TeamMember is the name this user exports as, key team.member, the login name by default
+	TeamMember string
+	// TeamMinGroup is the fewest members a team report shows a group's ratings for, key team.min-group
+	TeamMinGroup int
+	// MetricsFile is where imported metrics such as commits are kept, key metrics.file
+	MetricsFile string
+	// CommitRepos are the git repositories track import commits reads, key commits.repos, comma separated
+	CommitRepos []string
+	// CommitAuthors are the emails the user commits as, key commits.author, by default each repository's user.email.
+	// They also tell the meetings the user declined.
+	CommitAuthors []string
+	// SyncRemote is the git remote track sync pulls from and pushes to, key sync.remote
+	SyncRemote string
+	// SyncBranch is the remote branch holding the ratings, key sync.branch
+	SyncBranch string
+}
+
+// Default is the configuration when nothing is set
+func Default(home string) Config {
+	return Config{
+		RatingFile:    filepath.Join(home, ".track.rating.json"),
+		Store:         StoreJSON,
+		KeyFile:       filepath.Join(home, ".track.rating.json.key"),
+		BackupDir:     filepath.Join(home, ".track.backups"),
+		Backup:        backup.DefaultPolicy(),
+		TeamMinGroup:  team.DefaultMinGroup,
+		MetricsFile:   filepath.Join(home, ".track.metrics.json"),
+		Aggregation:   rating.AggregateLast,
+		GoalsFile:     filepath.Join(home, ".track.goals.json"),
+		Remind:        remind.DefaultSchedule(),
+		Notifier:      "notify-send",
+		PromptFormat:  prompt.DefaultFormat,
+		PromptUnrated: prompt.DefaultUnrated,
+		SyncBranch:    "main",
+	}
+}
+
+// Load reads the properties file at path over the defaults, a missing file is not an error.
+// Relative paths in it are relative to home, and ~/ is expanded.
+func Load(path, home string) (Config, error) {
+	cfg := Default(home)
+
+	// Checked here since properties logs the files it skips
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return cfg, nil
+	}
+	props, err := properties.LoadFile(path, properties.UTF8)
+	if err != nil {
+		return cfg, rating.Errorf(rating.KindConfig, "reading %s: %w", path, err)
+	}
+
+	known := map[string]func(string) error{
+		"rating.file": func(v string) error {
+			cfg.RatingFile = expandPath(v, home)
+			return nil
+		},
+		"rating.store": func(v string) error {
+			switch store := Store(v); store {
+			case StoreJSON, StoreCRDT:
+				cfg.Store = store
+				return nil
+			}
+			return rating.Errorf(rating.KindValidation, "unknown store %q, expected json or crdt", v)
+		},
+		"rating.aggregation": func(v string) (err error) {
+			cfg.Aggregation, err = rating.ParseAggregation(v)
+			return err
+		},
+		"goals.file": func(v string) error {
+			cfg.GoalsFile = expandPath(v, home)
+			return nil
+		},
+		"remind.at": func(v string) (err error) {
+			cfg.Remind.Hour, cfg.Remind.Minute, err = remind.ParseTime(v)
+			return err
+		},
+		"remind.quiet": func(v string) (err error) {
+			cfg.Remind.Quiet, err = remind.ParseWeekdays(v)
+			return err
+		},
+		"remind.notifier": func(v string) error {
+			cfg.Notifier = v
+			return nil
+		},
+		"remind.command": func(v string) error {
+			cfg.NotifyCommand = v
+			return nil
+		},
+		"prompt.format": func(v string) error {
+			cfg.PromptFormat = v
+			return nil
+		},
+		"prompt.unrated": func(v string) error {
+			cfg.PromptUnrated = v
+			return nil
+		},
+		"storage.key-file": func(v string) error {
+			cfg.KeyFile = expandPath(v, home)
+			return nil
+		},
+		"storage.passphrase-command": func(v string) error {
+			cfg.PassphraseCommand = v
+			return nil
+		},
+		"backup.dir": func(v string) error {
+			cfg.BackupDir = expandPath(v, home)
+			return nil
+		},
+		"backup.daily":   parseCount(&cfg.Backup.Daily),
+		"backup.weekly":  parseCount(&cfg.Backup.Weekly),
+		"backup.monthly": parseCount(&cfg.Backup.Monthly),
+		"team.dir": func(v string) error {
+			cfg.TeamDir = expandPath(v, home)
+			return nil
+		},
+		"team.member": func(v string) error {
+			cfg.TeamMember = v
+			return nil
+		},
+		"team.min-group": func(v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return rating.Errorf(rating.KindValidation, "invalid group size %q, expected 1 or more", v)
+			}
+			cfg.TeamMinGroup = n
+			return nil
+		},
+		"metrics.file": func(v string) error {
+			cfg.MetricsFile = expandPath(v, home)
+			return nil
+		},
+		"commits.repos": func(v string) error {
+			cfg.CommitRepos = nil
+			for _, repo := range splitList(v) {
+				cfg.CommitRepos = append(cfg.CommitRepos, expandPath(repo, home))
+			}
+			return nil
+		},
+		"commits.author": func(v string) error {
+			cfg.CommitAuthors = splitList(v)
+			return nil
+		},
+		"sync.remote": func(v string) error {
+			// A URL, or a path such as ~/Dropbox/track.git
+			if rest, ok := strings.CutPrefix(v, "~/"); ok {
+				v = filepath.Join(home, rest)
+			}
+			cfg.SyncRemote = v
+			return nil
+		},
+		"sync.branch": func(v string) error {
+			if v == "" {
+				return rating.Errorf(rating.KindValidation, "empty branch")
+			}
+			cfg.SyncBranch = v
+			return nil
+		},
+	}
+
+	var unknown []string
+	for _, key := range props.Keys() {
+		set, ok := known[key]
+		if !ok {
+			unknown = append(unknown, key)
+			continue
+		}
+		if err := set(strings.TrimSpace(props.MustGetString(key))); err != nil {
+			return cfg, rating.Errorf(rating.KindConfig, "%s: %s: %w", path, key, err)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return cfg, rating.Errorf(rating.KindConfig, "%s: unknown keys %s", path, strings.Join(unknown, ", "))
+	}
+	if _, ok := props.Get("storage.key-file"); !ok {
+		cfg.KeyFile = cfg.RatingFile + ".key"
+	}
+	if cfg.Store == StoreCRDT && cfg.SyncRemote != "" {
+		return cfg, rating.Errorf(rating.KindConfig, "%s: sync.remote needs rating.store = json, replicas are shared with track merge", path)
+	}
+
+	return cfg, nil
+}
+
+// parseCount sets n to a count of zero or more
+func parseCount(n *int) func(string) error {
+	return func(v string) error {
+		count, err := strconv.Atoi(v)
+		if err != nil || count < 0 {
+			return rating.Errorf(rating.KindValidation, "invalid count %q, expected 0 or more", v)
+		}
+		*n = count
+		return nil
+	}
+}
+
+// splitList splits a comma separated value, leaving out empty items
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// expandPath resolves ~/ and relative paths against home
+func expandPath(path, home string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		return filepath.Join(home, rest)
+	}
+	if !filepath.IsAbs(path) {
+		return filepath.Join(home, path)
+	}
+	return path
+}
+
+// Path returns the configuration file to read, $TRACK_CONFIG or FileName in home
+func Path(home string) string {
+	if path := os.Getenv("TRACK_CONFIG"); path != "" {
+		return path
+	}
+	return filepath.Join(home, FileName)
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/remind"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMissingFileUsesDefaults(t *testing.T) {
+	// Every command loads the configuration, so a missing file must not print anything
+	stderr, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, savedLog := os.Stderr, log.Writer()
+	os.Stderr = stderr
+	log.SetOutput(stderr)
+	t.Cleanup(func() {
+		os.Stderr = saved
+		log.SetOutput(savedLog)
+	})
+
+	cfg, err := Load(filepath.Join(t.TempDir(), FileName), "/home/ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, cfg, Default("/home/ann"))
+	assert.Equal(t, cfg.RatingFile, "/home/ann/.track.rating.json")
+
+	if written, err := os.ReadFile(stderr.Name()); err != nil || len(written) > 0 {
+		t.Errorf("Load of a missing file wrote %q to stderr, %v", written, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, "# ratings\nrating.file = ~/sync/ratings.json\nrating.aggregation = mean\ngoals.file = goals.json\nremind.at = 21:15\nremind.quiet = sat, 7\nprompt.format = {{.Label}}\nsync.remote = ~/backup/track.git\nbackup.weekly = 0\nteam.dir = ~/Shared/team\nteam.min-group = 5\ncommits.repos = ~/src/track, /work/api,\ncommits.author = ann@example.com, ann@work.example\n")
+
+	cfg, err := Load(path, "/home/ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, cfg.RatingFile, "/home/ann/sync/ratings.json")
+	assert.Equal(t, cfg.Aggregation, rating.AggregateMean)
+	assert.Equal(t, cfg.Store, StoreJSON)
+	assert.Equal(t, cfg.KeyFile, "/home/ann/sync/ratings.json.key")
+	assert.Equal(t, cfg.GoalsFile, "/home/ann/goals.json")
+	assert.Equal(t, cfg.PromptFormat, "{{.Label}}")
+	assert.Equal(t, cfg.PromptUnrated, "⚠ unrated")
+	assert.Equal(t, cfg.SyncRemote, "/home/ann/backup/track.git")
+	assert.Equal(t, cfg.SyncBranch, "main")
+	assert.Equal(t, cfg.BackupDir, "/home/ann/.track.backups")
+	assert.Equal(t, cfg.Backup, backup.Policy{Daily: 7, Monthly: 12})
+	assert.Equal(t, cfg.TeamDir, "/home/ann/Shared/team")
+	assert.Equal(t, cfg.TeamMinGroup, 5)
+	assert.Equal(t, cfg.MetricsFile, "/home/ann/.track.metrics.json")
+	assert.Equal(t, cfg.CommitRepos, []string{"/home/ann/src/track", "/work/api"})
+	assert.Equal(t, cfg.CommitAuthors, []string{"ann@example.com", "ann@work.example"})
+	assert.Equal(t, cfg.Remind, remind.Schedule{Hour: 21, Minute: 15, Quiet: []time.Weekday{time.Saturday, time.Sunday}})
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		match   string
+	}{
+		{"bad aggregation", "rating.aggregation = median\n", `rating.aggregation: unknown aggregation "median"`},
+		{"bad reminder time", "remind.at = 8pm\n", `remind.at: invalid time "8pm"`},
+		{"bad retention", "backup.daily = -1\n", `backup.daily: invalid count "-1"`},
+		{"bad group size", "team.min-group = 0\n", `team.min-group: invalid group size "0"`},
+		{"bad store", "rating.store = sqlite\n", `rating.store: unknown store "sqlite"`},
+		{"sync of a replica", "rating.store = crdt\nsync.remote = /srv/track.git\n", "sync.remote needs rating.store = json"},
+		{"unknown key", "rating.fil = x\nrating.colour = red\n", "unknown keys rating.colour, rating.fil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.content), "/home/ann")
+			if !errors.Is(err, rating.ErrConfig) {
+				t.Fatalf("Load = %v, want a config error", err)
+			}
+			assert.Matches(t, err.Error(), tt.match)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Package activity compares measures of what was done on a day, such as commits,
+// with how the day felt.
+package activity
+
+import (
+	"math"
+	"sort"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/series"
+)
+
+// The metrics imported, by name
+const (
+	// Commits is the number of the user's commits per day
+	Commits = "commits"
+	// Meetings is the number of meetings starting each day
+	Meetings = "meetings"
+	// MeetingHours is the time spent in meetings each day
+	MeetingHours = "meeting-hours"
+)
+
+// Commit is one commit found in a repository, its hash telling clones' copies apart
+type Commit struct {
+	Hash string
+	At   time.Time
+}
+
+// CountByDay counts commits on each day from start to end, days without any counted as 0.
+// A commit's day is its date where it was made, and a commit seen twice counts once.
+func CountByDay(commits []Commit, start, end time.Time) map[time.Time]float64 {
+	counts := make(map[time.Time]float64)
+	for day := series.Day(start); !day.After(series.Day(end)); day = day.AddDate(0, 0, 1) {
+		counts[day] = 0
+	}
+	seen := make(map[string]bool)
+	for _, c := range commits {
+		day := series.Day(c.At)
+		if _, ok := counts[day]; !ok || seen[c.Hash] {
+			continue
+		}
+		seen[c.Hash] = true
+		counts[day]++
+	}
+	return counts
+}
+
+// Event is one occurrence of a calendar event, recurring events having one for each
+type Event struct {
+	UID        string
+	Start, End time.Time
+}
+
+// MeetingsByDay counts the events starting on each day from start to end and adds up
+// the hours each spends on each day, days without any counted as 0. An event's days
+// are those in its Start's time zone, and an event seen twice counts once.
+func MeetingsByDay(events []Event, start, end time.Time) (count, hours map[time.Time]float64) {
+	count, hours = make(map[time.Time]float64), make(map[time.Time]float64)
+	for day := series.Day(start); !day.After(series.Day(end)); day = day.AddDate(0, 0, 1) {
+		count[day], hours[day] = 0, 0
+	}
+	type key struct {
+		uid   string
+		start int64
+	}
+	seen := make(map[key]bool)
+	for _, e := range events {
+		k := key{e.UID, e.Start.Unix()}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		if day := series.Day(e.Start); !day.Before(series.Day(start)) && !day.After(series.Day(end)) {
+			count[day]++
+		}
+		// Split at each midnight, so a meeting running late counts on both days
+		from := e.Start
+		for from.Before(e.End) {
+			y, m, d := from.Date()
+			midnight := time.Date(y, m, d+1, 0, 0, 0, 0, from.Location())
+			to := e.End
+			if midnight.Before(to) {
+				to = midnight
+			}
+			if _, ok := hours[series.Day(from)]; ok {
+				hours[series.Day(from)] += to.Sub(from).Hours()
+			}
+			from = to
+		}
+	}
+	return count, hours
+}
+
+// GoodDay is the lowest rating of a day that felt good
+const GoodDay = rating.Good
+
+// Insight compares a metric with the ratings over the days that have both. A day is
+// high when its value is above zero and at least the median, such as a productive day
+// by commits or a meeting-heavy one, and felt good when rated GoodDay or better.
+type Insight struct {
+	Metric string
+	Days   int
+	Median float64
+	// Correlation is Spearman's, NaN when there is too little to tell
+	Correlation float64
+	// GoodValue and OtherValue are the metric's average on good days and on the others
+	GoodValue, OtherValue float64
+	// HighRating and LowRating are the average rating on high days and on the others
+	HighRating, LowRating float64
+	// Table counts the days by [high][felt good], false first
+	Table [2][2]int
+}
+
+// Compare relates the metric s to ratings
+func Compare(s series.Series, ratings []rating.DayRating) Insight {
+	pairs := series.Align(ratings, s, 0)
+	in := Insight{Metric: s.Name, Days: len(pairs), Correlation: series.Correlate(s.Name, 0, pairs).Spearman}
+	if len(pairs) == 0 {
+		return in
+	}
+
+	values := make([]float64, len(pairs))
+	for i, p := range pairs {
+		values[i] = p.Value
+	}
+	sort.Float64s(values)
+	if n := len(values); n%2 == 1 {
+		in.Median = values[n/2]
+	} else {
+		in.Median = (values[n/2-1] + values[n/2]) / 2
+	}
+
+	var good, other, high, low mean
+	for _, p := range pairs {
+		feltGood, isHigh := p.Rating >= GoodDay, p.Value > 0 && p.Value >= in.Median
+		if feltGood {
+			good.add(p.Value)
+		} else {
+			other.add(p.Value)
+		}
+		if isHigh {
+			high.add(float64(p.Rating))
+		} else {
+			low.add(float64(p.Rating))
+		}
+		in.Table[index(isHigh)][index(feltGood)]++
+	}
+	in.GoodValue, in.OtherValue = good.value(), other.value()
+	in.HighRating, in.LowRating = high.value(), low.value()
+	return in
+}
+
+// mean is a running average, NaN until something is added
+type mean struct {
+	sum float64
+	n   int
+}
+
+func (m *mean) add(v float64) {
+	m.sum += v
+	m.n++
+}
+
+func (m mean) value() float64 {
+	if m.n == 0 {
+		return math.NaN()
+	}
+	return m.sum / float64(m.n)
+}
+
+func index(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
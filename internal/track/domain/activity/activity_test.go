@@ -0,0 +1,74 @@
+package activity
+
+import (
+	"math"
+	"testing"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/series"
+)
+
+// day is midnight UTC on the dth of February 2025
+func day(d int) time.Time {
+	return time.Date(2025, time.February, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCountByDay(t *testing.T) {
+	late := time.FixedZone("UTC-8", -8*3600)
+	commits := []Commit{
+		{Hash: "a", At: day(17).Add(9 * time.Hour)},
+		{Hash: "a", At: day(17).Add(9 * time.Hour)},
+		// Late on the 17th where it was made, already the 18th in UTC
+		{Hash: "b", At: time.Date(2025, time.February, 17, 22, 0, 0, 0, late)},
+		{Hash: "c", At: day(20)},
+	}
+	counts := CountByDay(commits, day(17), day(19))
+	if len(counts) != 3 || counts[day(17)] != 2 || counts[day(18)] != 0 || counts[day(19)] != 0 {
+		t.Errorf("CountByDay = %v, want 2 on the 17th and none on the 18th and 19th", counts)
+	}
+}
+
+func TestMeetingsByDay(t *testing.T) {
+	at := func(d int, hours float64) time.Time { return day(d).Add(time.Duration(hours * float64(time.Hour))) }
+	events := []Event{
+		{UID: "standup", Start: at(17, 9), End: at(17, 9.5)},
+		{UID: "standup", Start: at(17, 9), End: at(17, 9.5)},
+		{UID: "review", Start: at(17, 14), End: at(17, 15.5)},
+		// Runs past midnight, counted on the day it starts
+		{UID: "launch", Start: at(18, 23), End: at(19, 1)},
+		{UID: "offsite", Start: at(21, 9), End: at(21, 17)},
+	}
+	count, hours := MeetingsByDay(events, day(17), day(19))
+	if len(count) != 3 || count[day(17)] != 2 || count[day(18)] != 1 || count[day(19)] != 0 {
+		t.Errorf("count = %v, want 2, 1 and 0", count)
+	}
+	if len(hours) != 3 || hours[day(17)] != 2 || hours[day(18)] != 1 || hours[day(19)] != 1 {
+		t.Errorf("hours = %v, want 2, 1 and 1", hours)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	commits := series.Series{Name: Commits, Values: map[time.Time]float64{day(17): 6, day(18): 0, day(19): 2, day(20): 1}}
+	ratings := []rating.DayRating{
+		{Date: day(17), Rating: rating.Fair},
+		{Date: day(18), Rating: rating.Awesome},
+		{Date: day(19), Rating: rating.Good},
+		{Date: day(20), Rating: rating.Poor},
+		{Date: day(21), Rating: rating.Good},
+	}
+	in := Compare(commits, ratings)
+	if in.Days != 4 || in.Median != 1.5 {
+		t.Fatalf("Compare = %+v, want 4 days with a median of 1.5", in)
+	}
+	// High days are the 17th and 19th, good days the 18th and 19th
+	if in.Table != [2][2]int{{1, 1}, {1, 1}} {
+		t.Errorf("Table = %v, want one day in each cell", in.Table)
+	}
+	if in.GoodValue != 1 || in.OtherValue != 3.5 || in.HighRating != 3.5 || in.LowRating != 3.5 {
+		t.Errorf("averages = %+v", in)
+	}
+
+	if in := Compare(commits, nil); in.Days != 0 || !math.IsNaN(in.Correlation) {
+		t.Errorf("Compare without ratings = %+v, want no days", in)
+	}
+}
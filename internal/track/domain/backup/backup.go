@@ -0,0 +1,120 @@
+// Package backup decides which snapshots of the ratings to keep and what restoring one changes.
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+// IDLayout formats the time a snapshot was taken as its ID, so IDs sort by age
+const IDLayout = "20060102T150405Z"
+
+// ErrNotFound is returned for an unknown snapshot ID
+var ErrNotFound = rating.Errorf(rating.KindNotFound, "snapshot not found")
+
+// Snapshot is a copy of the ratings file as it was at a moment
+type Snapshot struct {
+	ID    string
+	Taken time.Time
+	Size  int64
+}
+
+// Policy is how many of the latest days, weeks and months keep a snapshot each
+type Policy struct {
+	Daily, Weekly, Monthly int
+}
+
+// DefaultPolicy keeps a week of daily, a month of weekly and a year of monthly snapshots
+func DefaultPolicy() Policy {
+	return Policy{Daily: 7, Weekly: 4, Monthly: 12}
+}
+
+func (p Policy) String() string {
+	return fmt.Sprintf("%d daily, %d weekly, %d monthly", p.Daily, p.Weekly, p.Monthly)
+}
+
+// Keep splits snapshots into those the policy keeps and those to delete. The newest
+// snapshot of each of the policy's latest days, weeks and months is kept, and the
+// newest snapshot of all always is. Periods are in loc, both lists newest first.
+func Keep(snapshots []Snapshot, p Policy, loc *time.Location) (keep, drop []Snapshot) {
+	sorted := append([]Snapshot(nil), snapshots...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Taken.After(sorted[j].Taken) })
+
+	kept := make(map[string]bool)
+	if len(sorted) > 0 {
+		kept[sorted[0].ID] = true
+	}
+	periods := []struct {
+		count int
+		key   func(time.Time) string
+	}{
+		{p.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{p.Weekly, func(t time.Time) string { y, w := t.ISOWeek(); return fmt.Sprintf("%d-W%02d", y, w) }},
+		{p.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+	for _, period := range periods {
+		seen := make(map[string]bool)
+		for _, s := range sorted {
+			key := period.key(s.Taken.In(loc))
+			if seen[key] {
+				continue
+			}
+			if len(seen) == period.count {
+				break
+			}
+			seen[key] = true
+			kept[s.ID] = true
+		}
+	}
+
+	for _, s := range sorted {
+		if kept[s.ID] {
+			keep = append(keep, s)
+		} else {
+			drop = append(drop, s)
+		}
+	}
+	return keep, drop
+}
+
+// Change is a day restoring a snapshot changes: added when Current is nil, removed when Snapshot is
+type Change struct {
+	ID                string
+	Current, Snapshot *rating.DayRating
+}
+
+// Diff lists the days that differ between the current ratings and a snapshot's, by ID
+func Diff(current, snapshot map[string]rating.DayRating) []Change {
+	var changes []Change
+	for id, dr := range current {
+		s, ok := snapshot[id]
+		switch {
+		case !ok:
+			changes = append(changes, Change{ID: id, Current: &dr})
+		case !sameRating(dr, s):
+			changes = append(changes, Change{ID: id, Current: &dr, Snapshot: &s})
+		}
+	}
+	for id, s := range snapshot {
+		if _, ok := current[id]; !ok {
+			changes = append(changes, Change{ID: id, Snapshot: &s})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes
+}
+
+// sameRating compares what a user sees of a day, bookkeeping such as revisions aside
+func sameRating(a, b rating.DayRating) bool {
+	if a.Rating != b.Rating || a.Note != b.Note || len(a.CheckIns) != len(b.CheckIns) {
+		return false
+	}
+	for i := range a.CheckIns {
+		if !a.CheckIns[i].At.Equal(b.CheckIns[i].At) || a.CheckIns[i].Rating != b.CheckIns[i].Rating || a.CheckIns[i].Note != b.CheckIns[i].Note {
+			return false
+		}
+	}
+	return true
+}
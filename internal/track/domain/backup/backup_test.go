@@ -0,0 +1,72 @@
+package backup
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+func TestKeep(t *testing.T) {
+	// Two snapshots a day over ten weeks, newest first on Sunday 2025-03-30
+	var snapshots []Snapshot
+	newest := time.Date(2025, time.March, 30, 20, 0, 0, 0, time.UTC)
+	for i := 0; i < 140; i++ {
+		taken := newest.Add(-time.Duration(i) * 12 * time.Hour)
+		snapshots = append(snapshots, Snapshot{ID: taken.Format(IDLayout), Taken: taken})
+	}
+
+	keep, drop := Keep(snapshots, Policy{Daily: 3, Weekly: 2, Monthly: 3}, time.UTC)
+	var ids []string
+	for _, s := range keep {
+		ids = append(ids, s.ID)
+	}
+	want := []string{
+		"20250330T200000Z", // newest, the last day, week and month
+		"20250329T200000Z",
+		"20250328T200000Z",
+		"20250323T200000Z", // the week before
+		"20250228T200000Z", // February and January
+		"20250131T200000Z",
+	}
+	if strings.Join(ids, " ") != strings.Join(want, " ") {
+		t.Errorf("kept %v, want %v", ids, want)
+	}
+	if len(keep)+len(drop) != len(snapshots) {
+		t.Errorf("kept %d and dropped %d of %d", len(keep), len(drop), len(snapshots))
+	}
+
+	if keep, _ := Keep(snapshots[:1], Policy{}, time.UTC); len(keep) != 1 {
+		t.Error("an empty policy dropped the only snapshot")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	day := func(id string, r rating.Rating, revision int) rating.DayRating {
+		return rating.DayRating{ID: id, Rating: r, Revision: revision}
+	}
+	current := map[string]rating.DayRating{
+		"25w08-1": day("25w08-1", rating.Good, 3),
+		"25w08-2": day("25w08-2", rating.Bad, 2),
+		"25w08-3": day("25w08-3", rating.Fair, 1),
+	}
+	snapshot := map[string]rating.DayRating{
+		"25w08-1": day("25w08-1", rating.Good, 1),
+		"25w08-2": day("25w08-2", rating.Poor, 1),
+		"25w08-4": day("25w08-4", rating.Awesome, 1),
+	}
+
+	changes := Diff(current, snapshot)
+	if len(changes) != 3 {
+		t.Fatalf("Diff = %+v, want 3 changes and the same rating at another revision ignored", changes)
+	}
+	if c := changes[0]; c.ID != "25w08-2" || c.Current.Rating != rating.Bad || c.Snapshot.Rating != rating.Poor {
+		t.Errorf("changed day = %+v", c)
+	}
+	if c := changes[1]; c.ID != "25w08-3" || c.Snapshot != nil {
+		t.Errorf("removed day = %+v", c)
+	}
+	if c := changes[2]; c.ID != "25w08-4" || c.Current != nil {
+		t.Errorf("added day = %+v", c)
+	}
+}
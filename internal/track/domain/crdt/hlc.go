@@ -0,0 +1,54 @@
+package crdt
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+)
+
+// Timestamp is a hybrid logical clock reading: wall time, a counter for events within
+// the same wall time, and the device that made it. Timestamps from different devices
+// never compare equal, so every write has a single winner wherever it is merged.
+type Timestamp struct {
+	Wall    int64  `json:"wall"`
+	Counter uint32 `json:"counter,omitempty"`
+	Device  string `json:"device"`
+}
+
+// Compare orders timestamps by wall time, then counter, then device
+func (t Timestamp) Compare(other Timestamp) int {
+	if c := cmp.Compare(t.Wall, other.Wall); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(t.Counter, other.Counter); c != 0 {
+		return c
+	}
+	return cmp.Compare(t.Device, other.Device)
+}
+
+// Next returns device's timestamp for an event at now that follows t, the latest one
+// the device has made or seen. It stays ahead of t when the wall clock is behind it.
+func (t Timestamp) Next(now time.Time, device string) Timestamp {
+	wall := now.UnixNano()
+	if wall > t.Wall {
+		return Timestamp{Wall: wall, Device: device}
+	}
+	return Timestamp{Wall: t.Wall, Counter: t.Counter + 1, Device: device}
+}
+
+// Time is the wall time part of the timestamp
+func (t Timestamp) Time() time.Time {
+	return time.Unix(0, t.Wall)
+}
+
+func (t Timestamp) String() string {
+	return fmt.Sprintf("%s.%d@%s", t.Time().UTC().Format(time.RFC3339Nano), t.Counter, t.Device)
+}
+
+// Latest returns the later of two timestamps
+func Latest(a, b Timestamp) Timestamp {
+	if a.Compare(b) >= 0 {
+		return a
+	}
+	return b
+}
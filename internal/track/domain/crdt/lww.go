@@ -0,0 +1,103 @@
+// Package crdt replicates ratings between devices without coordination: each device
+// writes to its own copy, and any two copies merge to the same result in any order.
+package crdt
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"track/internal/track/domain/rating"
+)
+
+// Entry is the latest write to a day: its rating, or a tombstone recording a delete
+type Entry struct {
+	Stamp Timestamp `json:"stamp"`
+	// Day is nil once the day was deleted
+	Day *rating.DayRating `json:"day,omitempty"`
+}
+
+// Map is a last-writer-wins map of day IDs to their latest write. Deletes are kept
+// as tombstones, so a merge can't bring back a day deleted on another device.
+type Map map[string]Entry
+
+// Set records a write to id at stamp, day being nil for a delete.
+// Like a merge, it keeps whichever write is later.
+func (m Map) Set(id string, stamp Timestamp, day *rating.DayRating) {
+	entry := Entry{Stamp: stamp, Day: day}
+	if current, ok := m[id]; !ok || wins(entry, current) {
+		m[id] = entry
+	}
+}
+
+// Merge combines two maps, keeping each day's later write. It is commutative,
+// associative and idempotent, so devices converge whatever order they merge in.
+func Merge(a, b Map) Map {
+	merged := make(Map, max(len(a), len(b)))
+	for id, e := range a {
+		merged[id] = e
+	}
+	for id, e := range b {
+		if current, ok := merged[id]; !ok || wins(e, current) {
+			merged[id] = e
+		}
+	}
+	return merged
+}
+
+// wins reports whether e is the later write. Equal stamps only come from one device
+// reusing another's ID; the content then decides, to keep merges deterministic.
+func wins(e, current Entry) bool {
+	if c := e.Stamp.Compare(current.Stamp); c != 0 {
+		return c > 0
+	}
+	return bytes.Compare(content(e), content(current)) > 0
+}
+
+func content(e Entry) []byte {
+	if e.Day == nil {
+		return nil
+	}
+	data, _ := json.Marshal(e.Day)
+	return data
+}
+
+// Ratings returns the days that are not deleted
+func (m Map) Ratings() map[string]rating.DayRating {
+	ratings := make(map[string]rating.DayRating, len(m))
+	for id, e := range m {
+		if e.Day != nil {
+			ratings[id] = *e.Day
+		}
+	}
+	return ratings
+}
+
+// Latest returns the latest stamp in the map, the zero Timestamp when it is empty
+func (m Map) Latest() Timestamp {
+	var latest Timestamp
+	for _, e := range m {
+		latest = Latest(latest, e.Stamp)
+	}
+	return latest
+}
+
+// Change is a day a merge changed: added when Before is nil, deleted when After is
+type Change struct {
+	ID            string
+	Before, After *rating.DayRating
+}
+
+// Changes lists the days whose rating differs between two maps, by ID
+func Changes(before, after Map) []Change {
+	var changes []Change
+	for id, e := range after {
+		old := before[id]
+		unchanged := old.Stamp == e.Stamp && bytes.Equal(content(old), content(e))
+		if unchanged || (old.Day == nil && e.Day == nil) {
+			continue
+		}
+		changes = append(changes, Change{ID: id, Before: old.Day, After: e.Day})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes
+}
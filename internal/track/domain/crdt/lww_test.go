@@ -0,0 +1,91 @@
+package crdt
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+// replica is a Map that testing/quick can generate. It draws from few days, devices and
+// times, so maps overlap and stamps collide often enough to exercise every tie-break.
+type replica Map
+
+func (replica) Generate(r *rand.Rand, size int) reflect.Value {
+	ids := []string{"25w08-1", "25w08-2", "25w08-3", "25w08-4"}
+	devices := []string{"laptop", "phone", "desktop"}
+	m := make(Map)
+	for i := r.Intn(size + 1); i > 0; i-- {
+		id := ids[r.Intn(len(ids))]
+		stamp := Timestamp{Wall: int64(r.Intn(4)), Counter: uint32(r.Intn(2)), Device: devices[r.Intn(len(devices))]}
+		var day *rating.DayRating
+		if r.Intn(4) > 0 {
+			day = &rating.DayRating{ID: id, Rating: rating.Rating(r.Intn(5) + 1), Revision: r.Intn(3) + 1}
+		}
+		m.Set(id, stamp, day)
+	}
+	return reflect.ValueOf(replica(m))
+}
+
+var quickConfig = &quick.Config{MaxCount: 500}
+
+func TestMergeCommutative(t *testing.T) {
+	commutative := func(a, b replica) bool {
+		return reflect.DeepEqual(Merge(Map(a), Map(b)), Merge(Map(b), Map(a)))
+	}
+	if err := quick.Check(commutative, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMergeAssociative(t *testing.T) {
+	associative := func(a, b, c replica) bool {
+		return reflect.DeepEqual(Merge(Merge(Map(a), Map(b)), Map(c)), Merge(Map(a), Merge(Map(b), Map(c))))
+	}
+	if err := quick.Check(associative, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMergeIdempotent(t *testing.T) {
+	idempotent := func(a, b replica) bool {
+		ab := Merge(Map(a), Map(b))
+		return reflect.DeepEqual(Merge(Map(a), Map(a)), Map(a)) && reflect.DeepEqual(Merge(ab, Map(b)), ab)
+	}
+	if err := quick.Check(idempotent, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeleteSurvivesMerge(t *testing.T) {
+	day := &rating.DayRating{ID: "25w08-1", Rating: rating.Good}
+	phone := Map{}
+	phone.Set(day.ID, Timestamp{Wall: 1, Device: "phone"}, day)
+	laptop := Merge(Map{}, phone)
+	laptop.Set(day.ID, Timestamp{Wall: 2, Device: "laptop"}, nil)
+
+	merged := Merge(phone, laptop)
+	if _, ok := merged.Ratings()[day.ID]; ok {
+		t.Error("the phone's older copy brought back a day deleted on the laptop")
+	}
+	changes := Changes(phone, merged)
+	if len(changes) != 1 || changes[0].Before == nil || changes[0].After != nil {
+		t.Errorf("Changes = %+v, want the delete", changes)
+	}
+}
+
+func TestNext(t *testing.T) {
+	now := time.Unix(100, 0)
+	first := Timestamp{}.Next(now, "laptop")
+	if first.Wall != now.UnixNano() || first.Counter != 0 {
+		t.Errorf("first stamp = %v", first)
+	}
+	// A clock running behind a stamp seen from another device still moves forward
+	seen := Timestamp{Wall: now.Add(time.Hour).UnixNano(), Counter: 3, Device: "phone"}
+	next := seen.Next(now, "laptop")
+	if next.Compare(seen) <= 0 || next.Device != "laptop" {
+		t.Errorf("stamp after %v = %v, want a later laptop stamp", seen, next)
+	}
+}
@@ -0,0 +1,96 @@
+package goal
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+// Kind is what a goal measures over its period
+type Kind string
+
+const (
+	// KindAverage wants the average rating to be at least Target
+	KindAverage Kind = "average"
+	// KindMaxBad allows at most Target days rated Bad
+	KindMaxBad Kind = "max-bad"
+	// KindEveryDay wants every day of the period rated
+	KindEveryDay Kind = "every-day"
+)
+
+// Period is the calendar span a goal is evaluated over, starting again when it ends
+type Period string
+
+const (
+	// PeriodWeek is the ISO week, Monday to Sunday
+	PeriodWeek Period = "week"
+	// PeriodMonth is the calendar month
+	PeriodMonth Period = "month"
+)
+
+var (
+	ErrNotFound = rating.Errorf(rating.KindNotFound, "goal not found")
+)
+
+// Goal is a target the user set for their ratings
+type Goal struct {
+	ID        string
+	Kind      Kind
+	Period    Period
+	Target    float64 `json:",omitempty"`
+	CreatedAt time.Time
+}
+
+// New validates a goal, every-day goals take no target
+func New(kind Kind, period Period, target float64) (Goal, error) {
+	switch period {
+	case PeriodWeek, PeriodMonth:
+	default:
+		return Goal{}, rating.Errorf(rating.KindValidation, "unknown period %q, expected week or month", period)
+	}
+
+	switch kind {
+	case KindAverage:
+		if target < float64(rating.Bad) || target > float64(rating.Awesome) {
+			return Goal{}, rating.Errorf(rating.KindValidation, "average target %g is outside %d-%d", target, rating.Bad, rating.Awesome)
+		}
+	case KindMaxBad:
+		if target < 0 || target != float64(int(target)) {
+			return Goal{}, rating.Errorf(rating.KindValidation, "Bad day allowance %g must be a whole number of days", target)
+		}
+	case KindEveryDay:
+		target = 0
+	default:
+		return Goal{}, rating.Errorf(rating.KindValidation, "unknown goal %q, expected average, max-bad or every-day", kind)
+	}
+
+	return Goal{Kind: kind, Period: period, Target: target}, nil
+}
+
+func (g Goal) String() string {
+	switch g.Kind {
+	case KindAverage:
+		return fmt.Sprintf("Average ≥ %s this %s", strconv.FormatFloat(g.Target, 'f', -1, 64), g.Period)
+	case KindMaxBad:
+		if g.Target == 1 {
+			return fmt.Sprintf("At most 1 Bad day this %s", g.Period)
+		}
+		return fmt.Sprintf("At most %d Bad days this %s", int(g.Target), g.Period)
+	case KindEveryDay:
+		return fmt.Sprintf("Rate every day this %s", g.Period)
+	}
+	return string(g.Kind)
+}
+
+// Bounds returns the first and last day of the period containing now, at midnight in now's location
+func (p Period) Bounds(now time.Time) (start, end time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if p == PeriodMonth {
+		start = today.AddDate(0, 0, 1-today.Day())
+		return start, start.AddDate(0, 1, -1)
+	}
+	// Go weeks start on Sunday, ISO weeks on Monday
+	start = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	return start, start.AddDate(0, 0, 6)
+}
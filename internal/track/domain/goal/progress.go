@@ -0,0 +1,140 @@
+package goal
+
+import (
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+// Status is how a goal stands in its current period
+type Status string
+
+const (
+	// StatusMet holds however the rest of the period goes
+	StatusMet Status = "met"
+	// StatusOnTrack holds if the period ended today
+	StatusOnTrack Status = "on track"
+	// StatusAtRisk fails if the period ended today, or one more Bad day fails it
+	StatusAtRisk Status = "at risk"
+	// StatusMissed fails however the rest of the period goes
+	StatusMissed Status = "missed"
+)
+
+// NeedsAttention reports whether the status is worth pointing out in a report
+func (s Status) NeedsAttention() bool {
+	return s == StatusAtRisk || s == StatusMissed
+}
+
+// Progress is a goal evaluated against the ratings of its current period
+type Progress struct {
+	Goal       Goal
+	Start, End time.Time
+	// Current is the measured value: the average, the Bad day count or the rated day count
+	Current float64
+	// Fraction of the target reached, or for max-bad of the allowance used, between 0 and 1
+	Fraction float64
+	Status   Status
+	// Rated and Days count the rated days and all days of the period
+	Rated, Days int
+}
+
+// Evaluate measures g over its period containing now. Unrated days from today on
+// may still be rated, which decides between met, missed and the two in between.
+func Evaluate(g Goal, ratings []rating.DayRating, now time.Time) Progress {
+	start, end := g.Period.Bounds(now)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	byDay := make(map[string]rating.Rating)
+	for _, dr := range ratings {
+		day := dr.Date.Format(time.DateOnly)
+		if day >= start.Format(time.DateOnly) && day <= end.Format(time.DateOnly) {
+			byDay[day] = dr.Rating
+		}
+	}
+
+	p := Progress{Goal: g, Start: start, End: end, Rated: len(byDay)}
+	// open days can still be rated, skipped days were left unrated
+	open, skipped := 0, 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		p.Days++
+		if _, ok := byDay[d.Format(time.DateOnly)]; ok {
+			continue
+		}
+		if d.Before(today) {
+			skipped++
+		} else {
+			open++
+		}
+	}
+
+	switch g.Kind {
+	case KindAverage:
+		sum := 0.0
+		for _, r := range byDay {
+			sum += float64(r)
+		}
+		if p.Rated > 0 {
+			p.Current = sum / float64(p.Rated)
+		}
+		p.Fraction = fraction(p.Current, g.Target)
+
+		count := float64(p.Rated + open)
+		switch {
+		case count > 0 && (sum+float64(rating.Bad)*float64(open))/count >= g.Target:
+			p.Status = StatusMet
+		case count == 0 || (sum+float64(rating.Awesome)*float64(open))/count < g.Target:
+			p.Status = StatusMissed
+		case p.Rated > 0 && p.Current < g.Target:
+			p.Status = StatusAtRisk
+		default:
+			p.Status = StatusOnTrack
+		}
+
+	case KindMaxBad:
+		for _, r := range byDay {
+			if r == rating.Bad {
+				p.Current++
+			}
+		}
+		p.Fraction = fraction(p.Current, g.Target)
+
+		switch {
+		case p.Current > g.Target:
+			p.Status = StatusMissed
+		case p.Current+float64(open) <= g.Target:
+			p.Status = StatusMet
+		case p.Current == g.Target:
+			p.Status = StatusAtRisk
+		default:
+			p.Status = StatusOnTrack
+		}
+
+	case KindEveryDay:
+		p.Current = float64(p.Rated)
+		p.Fraction = fraction(p.Current, float64(p.Days))
+
+		_, todayRated := byDay[today.Format(time.DateOnly)]
+		switch {
+		case skipped > 0:
+			p.Status = StatusMissed
+		case open == 0:
+			p.Status = StatusMet
+		case !todayRated:
+			p.Status = StatusAtRisk
+		default:
+			p.Status = StatusOnTrack
+		}
+	}
+
+	return p
+}
+
+// fraction is value/target clamped to 0-1, a zero target counts as full once anything is measured
+func fraction(value, target float64) float64 {
+	if target <= 0 {
+		if value > 0 {
+			return 1
+		}
+		return 0
+	}
+	return min(value/target, 1)
+}
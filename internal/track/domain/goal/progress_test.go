@@ -0,0 +1,90 @@
+package goal
+
+import (
+	"testing"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+// week rates the days of the week of Mon 17 Feb 2025 in order, 0 leaves a day unrated
+func week(ratings ...rating.Rating) []rating.DayRating {
+	var days []rating.DayRating
+	for i, r := range ratings {
+		if r == 0 {
+			continue
+		}
+		date := time.Date(2025, time.February, 17+i, 0, 0, 0, 0, time.UTC)
+		days = append(days, rating.DayRating{ID: rating.DayID(date), Date: date, Rating: r})
+	}
+	return days
+}
+
+func TestEvaluate(t *testing.T) {
+	wednesday := time.Date(2025, time.February, 19, 20, 0, 0, 0, time.UTC)
+	bad := rating.Bad
+
+	tests := []struct {
+		name    string
+		goal    Goal
+		ratings []rating.DayRating
+		want    Status
+		current float64
+	}{
+		{"average below target", Goal{Kind: KindAverage, Period: PeriodWeek, Target: 3.5}, week(4, 2), StatusAtRisk, 3},
+		{"average at target", Goal{Kind: KindAverage, Period: PeriodWeek, Target: 3}, week(4, 2), StatusOnTrack, 3},
+		{"average out of reach", Goal{Kind: KindAverage, Period: PeriodWeek, Target: 4.5}, week(bad, bad, bad), StatusMissed, 1},
+		{"average can't drop below", Goal{Kind: KindAverage, Period: PeriodWeek, Target: 1}, week(4, 2), StatusMet, 3},
+		{"Bad days within allowance", Goal{Kind: KindMaxBad, Period: PeriodWeek, Target: 2}, week(bad, 4), StatusOnTrack, 1},
+		{"Bad days at allowance", Goal{Kind: KindMaxBad, Period: PeriodWeek, Target: 2}, week(bad, bad), StatusAtRisk, 2},
+		{"Bad days over allowance", Goal{Kind: KindMaxBad, Period: PeriodWeek, Target: 1}, week(bad, bad), StatusMissed, 2},
+		{"Bad days can't exceed", Goal{Kind: KindMaxBad, Period: PeriodWeek, Target: 5}, week(4, 4), StatusMet, 0},
+		{"every day, today unrated", Goal{Kind: KindEveryDay, Period: PeriodWeek}, week(4, 4), StatusAtRisk, 2},
+		{"every day, so far", Goal{Kind: KindEveryDay, Period: PeriodWeek}, week(4, 4, 3), StatusOnTrack, 3},
+		{"every day, one skipped", Goal{Kind: KindEveryDay, Period: PeriodWeek}, week(4, 0, 3), StatusMissed, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Evaluate(tt.goal, tt.ratings, wednesday)
+			if got.Status != tt.want || got.Current != tt.current {
+				t.Errorf("Evaluate = %s at %g, want %s at %g", got.Status, got.Current, tt.want, tt.current)
+			}
+			if got.Days != 7 {
+				t.Errorf("Days = %d, want 7", got.Days)
+			}
+		})
+	}
+}
+
+func TestPeriodBounds(t *testing.T) {
+	sunday := time.Date(2025, time.February, 23, 22, 0, 0, 0, time.UTC)
+
+	start, end := PeriodWeek.Bounds(sunday)
+	if start.Format(time.DateOnly) != "2025-02-17" || end.Format(time.DateOnly) != "2025-02-23" {
+		t.Errorf("week bounds = %s to %s, want Mon 17 to Sun 23 Feb", start, end)
+	}
+	start, end = PeriodMonth.Bounds(sunday)
+	if start.Format(time.DateOnly) != "2025-02-01" || end.Format(time.DateOnly) != "2025-02-28" {
+		t.Errorf("month bounds = %s to %s, want 1 to 28 Feb", start, end)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(KindAverage, PeriodMonth, 3.5); err != nil {
+		t.Errorf("New(average 3.5) = %v", err)
+	}
+	invalid := []struct {
+		kind   Kind
+		period Period
+		target float64
+	}{
+		{KindAverage, PeriodWeek, 6},
+		{KindMaxBad, PeriodWeek, 1.5},
+		{KindEveryDay, "year", 0},
+		{"streak", PeriodWeek, 3},
+	}
+	for _, tt := range invalid {
+		if _, err := New(tt.kind, tt.period, tt.target); rating.KindOf(err) != rating.KindValidation {
+			t.Errorf("New(%s, %s, %g) = %v, want a validation error", tt.kind, tt.period, tt.target, err)
+		}
+	}
+}
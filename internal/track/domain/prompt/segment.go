@@ -0,0 +1,66 @@
+package prompt
+
+import (
+	"strings"
+	"text/template"
+	"track/internal/track/domain/rating"
+)
+
+const (
+	// DefaultFormat shows today's emoji, the week's average and its trend, like 🤩 4.2↑
+	DefaultFormat = "{{.Emoji}} {{.Average}}{{.Trend}}"
+	// DefaultUnrated is shown until today is rated
+	DefaultUnrated = "⚠ unrated"
+)
+
+// Segment is what a prompt template can show
+type Segment struct {
+	// Rated is false until today is rated, Rating, Label and Emoji are then empty.
+	// While the ratings are encrypted Rated and DayID are all a segment shows.
+	Rated  bool
+	DayID  string
+	Rating int
+	Label  string
+	Emoji  string
+	// Average of this week's ratings with one decimal, empty when none
+	Average string
+	// Days rated this week
+	Days int
+	// Trend of this week's average against last week's: ↑, ↓ or →, empty without last week
+	Trend string
+}
+
+// Render fills format, or unrated when today is not rated yet. Both are text/template
+// and both are checked, so a broken format shows up before the day is rated.
+func Render(format, unrated string, s Segment) (string, error) {
+	rated, err := template.New("format").Parse(format)
+	if err != nil {
+		return "", rating.Errorf(rating.KindValidation, "prompt format: %w", err)
+	}
+	tmpl, err := template.New("unrated").Parse(unrated)
+	if err != nil {
+		return "", rating.Errorf(rating.KindValidation, "prompt format: %w", err)
+	}
+	if s.Rated {
+		tmpl = rated
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, s); err != nil {
+		return "", rating.Errorf(rating.KindValidation, "prompt format: %w", err)
+	}
+	return b.String(), nil
+}
+
+// Trend compares two averages, empty when there is nothing to compare with
+func Trend(current, previous float64, previousDays int) string {
+	switch {
+	case previousDays == 0:
+		return ""
+	case current > previous:
+		return "↑"
+	case current < previous:
+		return "↓"
+	}
+	return "→"
+}
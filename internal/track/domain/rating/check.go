@@ -0,0 +1,72 @@
+package rating
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Problem is something wrong with a stored record, found by Check
+type Problem struct {
+	// Key is where the record is stored, which should be its ID
+	Key     string
+	Message string
+}
+
+func (p Problem) String() string {
+	return p.Key + ": " + p.Message
+}
+
+// Check validates stored records by the key they are stored under: that each has a
+// valid rating and check-ins, that its ID is its key and matches its date, and that
+// no two records rate the same date. Problems come sorted by key.
+func Check(records map[string]DayRating) []Problem {
+	var problems []Problem
+	add := func(key, format string, args ...any) {
+		problems = append(problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	byDate := make(map[string][]string)
+	for key, dr := range records {
+		if dr.ID != key {
+			add(key, "stored under %s but its ID is %s", key, dr.ID)
+		}
+		if dr.Date.IsZero() {
+			add(key, "has no date")
+		} else {
+			if id := DayID(dr.Date); id != dr.ID {
+				add(key, "ID %s doesn't match its date %s, which is %s", dr.ID, dr.Date.Format("2006-01-02"), id)
+			}
+			date := dr.Date.Format("2006-01-02")
+			byDate[date] = append(byDate[date], key)
+		}
+		if !dr.Rating.IsValid() {
+			add(key, "invalid rating %d", dr.Rating)
+		}
+		if dr.Revision < 0 {
+			add(key, "negative revision %d", dr.Revision)
+		}
+		if !dr.CreatedAt.IsZero() && dr.UpdatedAt.Before(dr.CreatedAt) {
+			add(key, "updated %s before it was created %s", dr.UpdatedAt.Format("2006-01-02 15:04"), dr.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		for _, c := range dr.CheckIns {
+			if !c.Rating.IsValid() {
+				add(key, "check-in at %s has invalid rating %d", c.At.Format("15:04"), c.Rating)
+			}
+			if !dr.Date.IsZero() && !DayStart(c.At.In(dr.Date.Location())).Equal(DayStart(dr.Date)) {
+				add(key, "check-in at %s is on another day", c.At.Format("2006-01-02 15:04"))
+			}
+		}
+	}
+
+	for date, keys := range byDate {
+		if len(keys) > 1 {
+			sort.Strings(keys)
+			for _, key := range keys {
+				add(key, "rates %s, as do %d other records", date, len(keys)-1)
+			}
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Key < problems[j].Key })
+	return problems
+}
@@ -0,0 +1,44 @@
+package rating
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	monday := time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC)
+	day := func(id string, date time.Time, r Rating) DayRating {
+		return DayRating{ID: id, Date: date, Rating: r, Revision: 1}
+	}
+
+	records := map[string]DayRating{
+		"25w08-1": day("25w08-1", monday, Good),
+		// Tuesday's ID on Monday's date: a mismatch and a duplicate date
+		"25w08-2": day("25w08-2", monday, Fair),
+		"25w08-3": day("25w08-4", monday.AddDate(0, 0, 2), Rating(9)),
+		"25w08-5": {ID: "25w08-5", Date: monday.AddDate(0, 0, 4), Rating: Good,
+			CheckIns: []CheckIn{{At: monday.Add(9 * time.Hour), Rating: Good}}},
+	}
+
+	var got []string
+	for _, p := range Check(records) {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"25w08-1: rates 2025-02-17, as do 1 other records",
+		"25w08-2: ID 25w08-2 doesn't match its date 2025-02-17, which is 25w08-1",
+		"25w08-2: rates 2025-02-17, as do 1 other records",
+		"25w08-3: stored under 25w08-3 but its ID is 25w08-4",
+		"25w08-3: ID 25w08-4 doesn't match its date 2025-02-19, which is 25w08-3",
+		"25w08-3: invalid rating 9",
+		"25w08-5: check-in at 2025-02-17 09:00 is on another day",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Check =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if problems := Check(map[string]DayRating{"25w08-1": day("25w08-1", monday, Good)}); len(problems) != 0 {
+		t.Errorf("Check of a valid record = %v", problems)
+	}
+}
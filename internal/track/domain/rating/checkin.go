@@ -0,0 +1,71 @@
+package rating
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// CheckIn is one of several ratings given over the course of a day
+type CheckIn struct {
+	At     time.Time
+	Rating Rating
+	Note   string `json:",omitempty"`
+}
+
+// Aggregation decides a day's rating from its check-ins
+type Aggregation string
+
+const (
+	AggregateLast Aggregation = "last"
+	AggregateMean Aggregation = "mean"
+	AggregateMin  Aggregation = "min"
+	AggregateMax  Aggregation = "max"
+)
+
+// ParseAggregation validates an aggregation name from configuration or flags
+func ParseAggregation(name string) (Aggregation, error) {
+	switch a := Aggregation(name); a {
+	case AggregateLast, AggregateMean, AggregateMin, AggregateMax:
+		return a, nil
+	}
+	return "", Errorf(KindValidation, "unknown aggregation %q, expected last, mean, min or max", name)
+}
+
+// Apply returns the day's rating for the check-ins, 0 when there are none.
+// The mean is rounded to the nearest rating, halves up.
+func (a Aggregation) Apply(checkIns []CheckIn) Rating {
+	if len(checkIns) == 0 {
+		return 0
+	}
+
+	switch a {
+	case AggregateMean:
+		sum := 0
+		for _, c := range checkIns {
+			sum += int(c.Rating)
+		}
+		return Rating(math.Floor(float64(sum)/float64(len(checkIns)) + 0.5))
+	case AggregateMin, AggregateMax:
+		result := checkIns[0].Rating
+		for _, c := range checkIns[1:] {
+			if (a == AggregateMin && c.Rating < result) || (a == AggregateMax && c.Rating > result) {
+				result = c.Rating
+			}
+		}
+		return result
+	default:
+		latest := checkIns[0]
+		for _, c := range checkIns[1:] {
+			if !c.At.Before(latest.At) {
+				latest = c
+			}
+		}
+		return latest.Rating
+	}
+}
+
+// SortCheckIns orders check-ins by time, earliest first
+func SortCheckIns(checkIns []CheckIn) {
+	sort.SliceStable(checkIns, func(i, j int) bool { return checkIns[i].At.Before(checkIns[j].At) })
+}
@@ -0,0 +1,43 @@
+package rating
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregationApply(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, time.February, 17, hour, 0, 0, 0, time.UTC) }
+	// Out of order on purpose, the evening check-in is the last one
+	checkIns := []CheckIn{
+		{At: at(13), Rating: Poor},
+		{At: at(20), Rating: Good},
+		{At: at(8), Rating: Awesome},
+	}
+
+	tests := []struct {
+		aggregation Aggregation
+		checkIns    []CheckIn
+		want        Rating
+	}{
+		{AggregateLast, checkIns, Good},
+		{AggregateMean, checkIns, Good}, // 11/3 rounds to 4
+		{AggregateMin, checkIns, Poor},
+		{AggregateMax, checkIns, Awesome},
+		{AggregateMean, []CheckIn{{At: at(8), Rating: Poor}, {At: at(9), Rating: Fair}}, Fair}, // 2.5 rounds up
+		{AggregateLast, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.aggregation.Apply(tt.checkIns); got != tt.want {
+			t.Errorf("%s of %d check-ins = %v, want %v", tt.aggregation, len(tt.checkIns), got, tt.want)
+		}
+	}
+}
+
+func TestParseAggregation(t *testing.T) {
+	if a, err := ParseAggregation("mean"); err != nil || a != AggregateMean {
+		t.Errorf("ParseAggregation(mean) = %q, %v", a, err)
+	}
+	if _, err := ParseAggregation("median"); KindOf(err) != KindValidation {
+		t.Errorf("ParseAggregation(median) = %v, want a validation error", err)
+	}
+}
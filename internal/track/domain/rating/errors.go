@@ -1,9 +1,85 @@
 package rating
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifies errors so adapters can map them to exit codes, statuses and messages
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindNotFound
+	KindConflict
+	KindStorage
+	KindConfig
+)
+
+func (k Kind) String() string {
+	labels := map[Kind]string{
+		KindInternal:   "internal",
+		KindValidation: "validation",
+		KindNotFound:   "not_found",
+		KindConflict:   "conflict",
+		KindStorage:    "storage",
+		KindConfig:     "config",
+	}
+	if label, exists := labels[k]; exists {
+		return label
+	}
+	return "unknown"
+}
+
+// Error is an error of a known Kind. Wrap it with %w as it travels outwards.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Kind.String() + " error"
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is makes the bare kind errors (ErrValidation, ErrStorage...) match any error of their kind
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Err == nil && t.Kind == e.Kind
+}
+
+// Errorf creates an error of the given kind, %w in format wraps the cause
+func Errorf(kind Kind, format string, args ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// KindOf returns the kind of the outermost classified error in err's chain
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindInternal
+}
 
 var (
-	ErrInvalidRating = errors.New("invalid rating value")
-	ErrInvalidDate   = errors.New("invalid date")
-	ErrNotFound      = errors.New("rating not found")
+	// Match any error of a kind with errors.Is(err, ErrStorage)
+	ErrValidation = &Error{Kind: KindValidation}
+	ErrConflict   = &Error{Kind: KindConflict}
+	ErrStorage    = &Error{Kind: KindStorage}
+	ErrConfig     = &Error{Kind: KindConfig}
+
+	ErrInvalidRating = Errorf(KindValidation, "invalid rating value")
+	ErrInvalidDate   = Errorf(KindValidation, "invalid date")
+	ErrNotFound      = Errorf(KindNotFound, "rating not found")
+	ErrAlreadyRated  = Errorf(KindConflict, "already rated")
+	ErrStaleRevision = Errorf(KindConflict, "rating was changed since it was read")
+	ErrHasCheckIns   = Errorf(KindConflict, "rated from check-ins")
 )
@@ -0,0 +1,40 @@
+package rating
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorKinds(t *testing.T) {
+	wrapped := fmt.Errorf("getting 25w08-1: %w", ErrNotFound)
+
+	tests := []struct {
+		name string
+		err  error
+		kind Kind
+		is   error
+	}{
+		{"sentinel", ErrInvalidRating, KindValidation, ErrValidation},
+		{"wrapped sentinel", wrapped, KindNotFound, ErrNotFound},
+		{"constructed", Errorf(KindStorage, "writing ratings: %w", errors.New("disk full")), KindStorage, ErrStorage},
+		{"unclassified", errors.New("boom"), KindInternal, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.kind {
+				t.Errorf("KindOf = %v, want %v", got, tt.kind)
+			}
+			if tt.is != nil && !errors.Is(tt.err, tt.is) {
+				t.Errorf("errors.Is(%v, %v) = false", tt.err, tt.is)
+			}
+		})
+	}
+
+	if errors.Is(ErrNotFound, ErrValidation) {
+		t.Error("not found matched the validation kind")
+	}
+	if errors.Is(ErrInvalidRating, ErrInvalidDate) {
+		t.Error("distinct sentinels of one kind matched each other")
+	}
+}
@@ -0,0 +1,149 @@
+package rating
+
+import (
+	"slices"
+	"sort"
+)
+
+// Merge rules, recorded on each conflict
+const (
+	RuleLatest         = "latest change wins"
+	RuleCheckIns       = "check-ins combined"
+	RuleKeepOverDelete = "change wins over delete"
+)
+
+// MergeConflict is a day both sides changed differently since they last agreed
+type MergeConflict struct {
+	ID string
+	// Ours and Theirs are nil where that side deleted the day
+	Ours, Theirs *DayRating
+	// Result is nil when the day ends up deleted
+	Result *DayRating
+	Rule   string
+}
+
+// Merge is a three-way merge of two copies of the ratings, ours and theirs, that
+// both started from base. A day changed on one side only takes that change. A day
+// changed on both sides is a conflict, resolved by the first rule that applies:
+//   - a change wins over a delete
+//   - check-ins from both sides are combined
+//   - the most recent update wins, or the higher revision when those are equal
+//
+// A merged day gets a revision above both sides', so writers holding either fail
+// their revision check instead of silently overwriting the merge.
+func Merge(base, ours, theirs map[string]DayRating) (map[string]DayRating, []MergeConflict) {
+	ids := make(map[string]bool)
+	for _, m := range []map[string]DayRating{base, ours, theirs} {
+		for id := range m {
+			ids[id] = true
+		}
+	}
+
+	merged := make(map[string]DayRating)
+	var conflicts []MergeConflict
+	for _, id := range sortedKeys(ids) {
+		b, inBase := base[id]
+		o, inOurs := ours[id]
+		t, inTheirs := theirs[id]
+
+		oursChanged := inOurs != inBase || (inOurs && !sameDay(o, b))
+		theirsChanged := inTheirs != inBase || (inTheirs && !sameDay(t, b))
+
+		switch {
+		case !theirsChanged:
+			if inOurs {
+				merged[id] = o
+			}
+			continue
+		case !oursChanged:
+			if inTheirs {
+				merged[id] = t
+			}
+			continue
+		case inOurs == inTheirs && (!inOurs || sameDay(o, t)):
+			// Both made the same change
+			if inOurs {
+				merged[id] = o
+			}
+			continue
+		}
+
+		conflict := MergeConflict{ID: id}
+		if inOurs {
+			conflict.Ours = &o
+		}
+		if inTheirs {
+			conflict.Theirs = &t
+		}
+
+		var result DayRating
+		switch {
+		case !inOurs:
+			result, conflict.Rule = t, RuleKeepOverDelete
+		case !inTheirs:
+			result, conflict.Rule = o, RuleKeepOverDelete
+		case len(o.CheckIns) > 0 || len(t.CheckIns) > 0:
+			result, conflict.Rule = latest(o, t), RuleCheckIns
+			result.CheckIns = unionCheckIns(o.CheckIns, t.CheckIns)
+		default:
+			result, conflict.Rule = latest(o, t), RuleLatest
+		}
+		result.Revision = max(o.Revision, t.Revision) + 1
+		if !o.CreatedAt.IsZero() && (result.CreatedAt.IsZero() || o.CreatedAt.Before(result.CreatedAt)) {
+			result.CreatedAt = o.CreatedAt
+		}
+		if !t.CreatedAt.IsZero() && (result.CreatedAt.IsZero() || t.CreatedAt.Before(result.CreatedAt)) {
+			result.CreatedAt = t.CreatedAt
+		}
+
+		merged[id] = result
+		conflict.Result = &result
+		conflicts = append(conflicts, conflict)
+	}
+	return merged, conflicts
+}
+
+// latest returns the more recently updated of two versions of a day, ours on a tie
+func latest(ours, theirs DayRating) DayRating {
+	switch {
+	case theirs.UpdatedAt.After(ours.UpdatedAt):
+		return theirs
+	case ours.UpdatedAt.After(theirs.UpdatedAt):
+		return ours
+	case theirs.Revision > ours.Revision:
+		return theirs
+	}
+	return ours
+}
+
+// unionCheckIns combines two days' check-ins, dropping the ones they share
+func unionCheckIns(a, b []CheckIn) []CheckIn {
+	union := slices.Clone(a)
+	for _, c := range b {
+		if !slices.ContainsFunc(union, func(u CheckIn) bool { return sameCheckIn(u, c) }) {
+			union = append(union, c)
+		}
+	}
+	SortCheckIns(union)
+	return union
+}
+
+// sameDay compares every stored field, times by instant since they went through JSON
+func sameDay(a, b DayRating) bool {
+	return a.ID == b.ID && a.Date.Equal(b.Date) && a.Rating == b.Rating && a.Note == b.Note &&
+		a.Revision == b.Revision && a.CreatedAt.Equal(b.CreatedAt) && a.UpdatedAt.Equal(b.UpdatedAt) &&
+		a.Source == b.Source && a.Host == b.Host && slices.EqualFunc(a.CheckIns, b.CheckIns, sameCheckIn)
+}
+
+func sameCheckIn(a, b CheckIn) bool {
+	return a.At.Equal(b.At) && a.Rating == b.Rating && a.Note == b.Note
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,94 @@
+package rating
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, time.February, 17, hour, 0, 0, 0, time.UTC) }
+	day := func(id string, r Rating, revision, updated int) DayRating {
+		return DayRating{ID: id, Rating: r, Revision: revision, CreatedAt: at(1), UpdatedAt: at(updated)}
+	}
+
+	base := map[string]DayRating{
+		"unchanged":     day("unchanged", Fair, 1, 1),
+		"ours-edit":     day("ours-edit", Fair, 1, 1),
+		"theirs-delete": day("theirs-delete", Fair, 1, 1),
+		"both-edit":     day("both-edit", Fair, 1, 1),
+		"edit-delete":   day("edit-delete", Fair, 1, 1),
+		"same-edit":     day("same-edit", Fair, 1, 1),
+	}
+	ours := map[string]DayRating{
+		"unchanged":     base["unchanged"],
+		"ours-edit":     day("ours-edit", Good, 2, 5),
+		"theirs-delete": base["theirs-delete"],
+		"both-edit":     day("both-edit", Good, 2, 5),
+		"same-edit":     day("same-edit", Bad, 2, 3),
+		"ours-new":      day("ours-new", Awesome, 1, 2),
+		"checkins": {ID: "checkins", Rating: Good, Revision: 2, UpdatedAt: at(9),
+			CheckIns: []CheckIn{{At: at(8), Rating: Fair}, {At: at(9), Rating: Good}}},
+	}
+	theirs := map[string]DayRating{
+		"unchanged":   base["unchanged"],
+		"ours-edit":   base["ours-edit"],
+		"both-edit":   day("both-edit", Poor, 3, 7),
+		"edit-delete": day("edit-delete", Awesome, 2, 4),
+		"same-edit":   day("same-edit", Bad, 2, 3),
+		"checkins": {ID: "checkins", Rating: Bad, Revision: 1, UpdatedAt: at(13),
+			CheckIns: []CheckIn{{At: at(8), Rating: Fair}, {At: at(13), Rating: Bad}}},
+	}
+
+	merged, conflicts := Merge(base, ours, theirs)
+
+	want := map[string]Rating{
+		"unchanged":   Fair,
+		"ours-edit":   Good,
+		"both-edit":   Poor,
+		"edit-delete": Awesome,
+		"same-edit":   Bad,
+		"ours-new":    Awesome,
+		"checkins":    Bad,
+	}
+	if len(merged) != len(want) {
+		t.Errorf("merged %d days, want %d: %v", len(merged), len(want), merged)
+	}
+	for id, r := range want {
+		if merged[id].Rating != r {
+			t.Errorf("%s = %v, want %v", id, merged[id].Rating, r)
+		}
+	}
+
+	rules := map[string]string{}
+	for _, c := range conflicts {
+		rules[c.ID] = c.Rule
+	}
+	wantRules := map[string]string{"both-edit": RuleLatest, "edit-delete": RuleKeepOverDelete, "checkins": RuleCheckIns}
+	if len(rules) != len(wantRules) {
+		t.Errorf("conflicts = %v, want %v", rules, wantRules)
+	}
+	for id, rule := range wantRules {
+		if rules[id] != rule {
+			t.Errorf("%s resolved by %q, want %q", id, rules[id], rule)
+		}
+	}
+
+	if got := merged["both-edit"].Revision; got != 4 {
+		t.Errorf("merged revision = %d, want 4, above both sides", got)
+	}
+	if got := len(merged["checkins"].CheckIns); got != 3 {
+		t.Errorf("combined %d check-ins, want 3 without the shared one", got)
+	}
+}
+
+func TestMergeIsSymmetric(t *testing.T) {
+	at := time.Date(2025, time.February, 17, 9, 0, 0, 0, time.UTC)
+	ours := map[string]DayRating{"d": {ID: "d", Rating: Good, Revision: 2, UpdatedAt: at}}
+	theirs := map[string]DayRating{"d": {ID: "d", Rating: Bad, Revision: 2, UpdatedAt: at.Add(time.Minute)}}
+
+	a, _ := Merge(nil, ours, theirs)
+	b, _ := Merge(nil, theirs, ours)
+	if a["d"].Rating != Bad || b["d"].Rating != Bad {
+		t.Errorf("merges = %v and %v, want the later Bad both ways", a["d"].Rating, b["d"].Rating)
+	}
+}
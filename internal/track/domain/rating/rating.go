@@ -47,17 +47,55 @@ func (r Rating) IsValid() bool {
 	return r >= Bad && r <= Awesome
 }
 
+// Source is the interface a rating was entered through
+type Source string
+
+const (
+	SourceCLI  Source = "cli"
+	SourceTUI  Source = "tui" // the interactive prompt
+	SourceHTTP Source = "http"
+	SourceMCP  Source = "mcp"
+	SourceFill Source = "fill"
+)
+
 type DayRating struct {
 	ID     string
 	Date   time.Time
 	Rating Rating
+	Note   string `json:",omitempty"`
+	// Revision counts the saves of this day, starting at 1. Repositories only accept
+	// a save whose Revision is one more than the stored one, so stale writers fail.
+	Revision int `json:",omitempty"`
+	// CreatedAt and UpdatedAt are when the rating was entered and last changed, Source
+	// and Host where it was entered. They are zero on ratings from before they were kept.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Source    Source `json:",omitempty"`
+	Host      string `json:",omitempty"`
+	// CheckIns hold the ratings given through the day, if it was rated that way.
+	// Rating is then their aggregate.
+	CheckIns []CheckIn `json:",omitempty"`
+}
+
+// DayID returns the YYwWW-D identifier used to key a day's rating.
+// YY is the ISO year, so late-December days in week 1 don't collide with the start of the same calendar year
+func DayID(date time.Time) string {
+	year, week := date.ISOWeek()
+	weekday := date.Weekday()
+	return fmt.Sprintf("%02dw%02d-%d", year%100, week, weekday)
 }
 
 func (dr DayRating) Label() string {
-	yr := dr.Date.Format("06") // Last two digits of year
-	_, week := dr.Date.ISOWeek()
-	weekday := dr.Date.Weekday()
-	return fmt.Sprintf("%sw%02d-%d", yr, week, weekday)
+	return DayID(dr.Date)
+}
+
+// RatedLate reports whether the rating was entered on a later day than the one it rates,
+// false when that is not known
+func (dr DayRating) RatedLate() bool {
+	if dr.CreatedAt.IsZero() {
+		return false
+	}
+	return DayStart(dr.CreatedAt.In(dr.Date.Location())).After(DayStart(dr.Date))
 }
 
 func (dr DayRating) String() string {
@@ -67,7 +105,7 @@ func (dr DayRating) String() string {
 // NewDayRating creates a new DayRating with validation
 func NewDayRating(date time.Time, rating Rating) (DayRating, error) {
 	if !rating.IsValid() {
-		return DayRating{}, fmt.Errorf("invalid rating value: %d", rating)
+		return DayRating{}, Errorf(KindValidation, "invalid rating value: %d", rating)
 	}
 	return DayRating{
 		Date:   date,
@@ -79,7 +117,7 @@ func NewDayRating(date time.Time, rating Rating) (DayRating, error) {
 func NewRating(value int) (Rating, error) {
 	rating := Rating(value)
 	if !rating.IsValid() {
-		return 0, fmt.Errorf("rating must be between %d and %d, got %d", Bad, Awesome, value)
+		return 0, Errorf(KindValidation, "rating must be between %d and %d, got %d", Bad, Awesome, value)
 	}
 	return rating, nil
 }
@@ -0,0 +1,41 @@
+package rating
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRatedLate(t *testing.T) {
+	day := time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		created time.Time
+		want    bool
+	}{
+		{"unknown", time.Time{}, false},
+		{"same evening", time.Date(2025, time.February, 17, 22, 0, 0, 0, time.UTC), false},
+		{"after midnight", time.Date(2025, time.February, 18, 0, 30, 0, 0, time.UTC), true},
+		{"a week later", time.Date(2025, time.February, 24, 9, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dr := DayRating{Date: day, CreatedAt: tt.created}
+			if got := dr.RatedLate(); got != tt.want {
+				t.Errorf("RatedLate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRatedLateUsesTheDaysZone(t *testing.T) {
+	// 22:00 in New York is already the next day in UTC
+	est := time.FixedZone("EST", -5*3600)
+	dr := DayRating{
+		Date:      time.Date(2025, time.February, 17, 0, 0, 0, 0, est),
+		CreatedAt: time.Date(2025, time.February, 18, 3, 0, 0, 0, time.UTC),
+	}
+	if dr.RatedLate() {
+		t.Error("rating entered the same evening in the day's zone is marked late")
+	}
+}
@@ -0,0 +1,72 @@
+package rating
+
+import "time"
+
+// WeekSummary summarises the ratings of one ISO week
+type WeekSummary struct {
+	Year     int
+	Week     int
+	Average  float64
+	Best     DayRating
+	Worst    DayRating
+	DayCount int
+}
+
+// Stats summarises the ratings recorded between two dates
+type Stats struct {
+	Start         time.Time
+	End           time.Time
+	Count         int
+	Average       float64
+	Distribution  map[Rating]int
+	CurrentStreak int
+	LongestStreak int
+	// Late counts the ratings entered after their day, they are left out of
+	// everything else when StatsOptions.ExcludeLate is set
+	Late int
+}
+
+// StatsOptions narrows the ratings that statistics are computed over
+type StatsOptions struct {
+	// ExcludeLate leaves out ratings entered after their day, such as backfilled gaps
+	ExcludeLate bool
+}
+
+// DayStart truncates t to midnight in its own location
+func DayStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// DayEnd returns the last instant of t's day, for inclusive range queries
+func DayEnd(t time.Time) time.Time {
+	return DayStart(t).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// WeekTrend is the average rating of one ISO week
+type WeekTrend struct {
+	Year    int
+	Week    int
+	Average float64
+	Count   int
+}
+
+// WeekdayStat is the average rating of one day of the week
+type WeekdayStat struct {
+	Weekday time.Weekday
+	Average float64
+	Count   int
+}
+
+// Metrics is a point-in-time snapshot of the ratings for monitoring systems
+type Metrics struct {
+	Today         Rating // zero when today is unrated
+	Average7d     float64
+	Count7d       int
+	Average30d    float64
+	Count30d      int
+	Streak        int
+	DaysSinceLast int // negative when nothing has been rated
+	Total         int
+	Counts        map[Rating]int
+}
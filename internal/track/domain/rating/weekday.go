@@ -0,0 +1,31 @@
+package rating
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseWeekday reads a weekday as a name, full or abbreviated (sat, Sunday),
+// or a number 1-7 with 1 = Monday as --weekday takes it
+func ParseWeekday(value string) (time.Weekday, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 1 || n > 7 {
+			return 0, Errorf(KindValidation, "weekday %d is outside 1-7", n)
+		}
+		return time.Weekday(n % 7), nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if value == name || value == name[:3] {
+			return d, nil
+		}
+	}
+	return 0, Errorf(KindValidation, "unknown weekday %q", value)
+}
+
+// ISOWeekday numbers d from 1 = Monday to 7 = Sunday
+func ISOWeekday(d time.Weekday) int {
+	return (int(d)+6)%7 + 1
+}
@@ -0,0 +1,26 @@
+package rating
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWeekday(t *testing.T) {
+	valid := map[string]time.Weekday{
+		"1":        time.Monday,
+		"7":        time.Sunday,
+		"sat":      time.Saturday,
+		"Thursday": time.Thursday,
+		" SUN ":    time.Sunday,
+	}
+	for value, want := range valid {
+		if got, err := ParseWeekday(value); err != nil || got != want {
+			t.Errorf("ParseWeekday(%q) = %v, %v, want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"0", "8", "thu-fri", "tues", ""} {
+		if _, err := ParseWeekday(value); KindOf(err) != KindValidation {
+			t.Errorf("ParseWeekday(%q) = %v, want a validation error", value, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package remind
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+// Schedule is when to remind: every day from a time of day on, except quiet days
+type Schedule struct {
+	Hour, Minute int
+	Quiet        []time.Weekday
+}
+
+// DefaultSchedule reminds at 20:00 every day
+func DefaultSchedule() Schedule {
+	return Schedule{Hour: 20}
+}
+
+// ParseTime reads a time of day as HH:MM
+func ParseTime(value string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, rating.Errorf(rating.KindValidation, "invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// ParseWeekdays reads a comma separated list of weekdays as names (sat, Sunday)
+// or numbers 1-7 with 1 = Monday, as --weekday takes them. Empty means none.
+func ParseWeekdays(value string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, part := range strings.Split(value, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		day, err := rating.ParseWeekday(part)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+// IsQuiet reports whether no reminders are wanted on t's day
+func (s Schedule) IsQuiet(t time.Time) bool {
+	return slices.Contains(s.Quiet, t.Weekday())
+}
+
+// DueAt returns when the reminder is due on t's day
+func (s Schedule) DueAt(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), s.Hour, s.Minute, 0, 0, t.Location())
+}
+
+func (s Schedule) String() string {
+	at := fmt.Sprintf("%02d:%02d", s.Hour, s.Minute)
+	if len(s.Quiet) == 0 {
+		return "daily at " + at
+	}
+	var quiet []string
+	for _, d := range s.Quiet {
+		quiet = append(quiet, d.String()[:3])
+	}
+	return fmt.Sprintf("at %s except %s", at, strings.Join(quiet, ", "))
+}
+
+// Outcome is what a reminder check decided
+type Outcome string
+
+const (
+	OutcomeNotified Outcome = "notified"
+	OutcomeRated    Outcome = "already rated"
+	OutcomeQuiet    Outcome = "quiet day"
+	OutcomeEarly    Outcome = "not due yet"
+	OutcomeSnoozed  Outcome = "snoozed"
+)
+
+// SchedulerKind names a way of running the check periodically
+type SchedulerKind string
+
+const (
+	SchedulerSystemd SchedulerKind = "systemd"
+	SchedulerCron    SchedulerKind = "cron"
+)
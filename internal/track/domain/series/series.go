@@ -0,0 +1,203 @@
+// Package series relates dated numeric series, such as hours slept, to the day ratings.
+package series
+
+import (
+	"math"
+	"sort"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+// Series is one measure by day, days keyed by their midnight UTC like the rating dates
+type Series struct {
+	Name   string
+	Values map[time.Time]float64
+}
+
+// Day returns t's date as a key of Values
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Pair is a rated day's rating and the series value it is compared with
+type Pair struct {
+	Day    time.Time
+	Value  float64
+	Rating rating.Rating
+}
+
+// Align pairs each rated day's rating with the series value lag days before it,
+// leaving out days without either
+func Align(ratings []rating.DayRating, s Series, lag int) []Pair {
+	var pairs []Pair
+	for _, dr := range ratings {
+		day := Day(dr.Date)
+		v, ok := s.Values[day.AddDate(0, 0, -lag)]
+		if !ok || !dr.Rating.IsValid() {
+			continue
+		}
+		pairs = append(pairs, Pair{Day: day, Value: v, Rating: dr.Rating})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Day.Before(pairs[j].Day) })
+	return pairs
+}
+
+// Correlation measures how a series relates to the ratings at one lag. The
+// coefficients are NaN when there are fewer than 3 pairs or either side is constant.
+type Correlation struct {
+	Series   string
+	Lag      int
+	N        int
+	Pearson  float64
+	Spearman float64
+}
+
+// Correlate computes both coefficients over pairs
+func Correlate(name string, lag int, pairs []Pair) Correlation {
+	xs, ys := split(pairs)
+	return Correlation{Series: name, Lag: lag, N: len(pairs), Pearson: Pearson(xs, ys), Spearman: Spearman(xs, ys)}
+}
+
+// Pearson is the linear correlation coefficient of xs and ys, which have the same length
+func Pearson(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if len(xs) < 3 {
+		return math.NaN()
+	}
+	var mx, my float64
+	for i := range xs {
+		mx += xs[i]
+		my += ys[i]
+	}
+	mx, my = mx/n, my/n
+	var sxy, sxx, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// Spearman is the rank correlation coefficient, ties given their mean rank. It
+// catches relations that are monotonic but not linear.
+func Spearman(xs, ys []float64) float64 {
+	return Pearson(ranks(xs), ranks(ys))
+}
+
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+
+	r := make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && values[order[j+1]] == values[order[i]] {
+			j++
+		}
+		// Ranks i+1 to j+1 are tied
+		mean := float64(i+j+2) / 2
+		for k := i; k <= j; k++ {
+			r[order[k]] = mean
+		}
+		i = j + 1
+	}
+	return r
+}
+
+// Strength describes the size of a coefficient in words
+func Strength(r float64) string {
+	switch a := math.Abs(r); {
+	case math.IsNaN(r):
+		return "not enough data"
+	case a >= 0.7:
+		return "strong"
+	case a >= 0.4:
+		return "moderate"
+	case a >= 0.2:
+		return "weak"
+	}
+	return "none"
+}
+
+// Quantile is the ratings of the days whose series value is between Low and High
+type Quantile struct {
+	Low, High float64
+	Days      int
+	Average   float64
+}
+
+// Quantiles splits the pairs into n groups of about equal size by series value,
+// lowest first, equal values always falling in the same group
+func Quantiles(pairs []Pair, n int) []Quantile {
+	sorted := append([]Pair(nil), pairs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Value < sorted[j].Value })
+
+	var quantiles []Quantile
+	for i := 0; i < len(sorted); {
+		// The days left shared by the groups left
+		end := i + (len(sorted)-i+n-len(quantiles)-1)/max(n-len(quantiles), 1)
+		for end < len(sorted) && sorted[end].Value == sorted[end-1].Value {
+			end++
+		}
+		q := Quantile{Low: sorted[i].Value, High: sorted[end-1].Value, Days: end - i}
+		for _, p := range sorted[i:end] {
+			q.Average += float64(p.Rating)
+		}
+		q.Average /= float64(q.Days)
+		quantiles = append(quantiles, q)
+		i = end
+	}
+	return quantiles
+}
+
+// Scatter counts the pairs by rating and by which of bins equal ranges of series
+// values they fall in. Counts[r][b] is for rating r, Edges the bins' n+1 bounds.
+type Scatter struct {
+	Edges  []float64
+	Counts map[rating.Rating][]int
+}
+
+func NewScatter(pairs []Pair, bins int) Scatter {
+	s := Scatter{Counts: make(map[rating.Rating][]int)}
+	if len(pairs) == 0 || bins < 1 {
+		return s
+	}
+	low, high := pairs[0].Value, pairs[0].Value
+	for _, p := range pairs {
+		low, high = math.Min(low, p.Value), math.Max(high, p.Value)
+	}
+	if low == high {
+		bins = 1
+	}
+	width := (high - low) / float64(bins)
+	for i := 0; i <= bins; i++ {
+		s.Edges = append(s.Edges, low+float64(i)*width)
+	}
+	for r := rating.Bad; r <= rating.Awesome; r++ {
+		s.Counts[r] = make([]int, bins)
+	}
+	for _, p := range pairs {
+		b := bins - 1
+		if width > 0 {
+			b = min(int((p.Value-low)/width), bins-1)
+		}
+		s.Counts[p.Rating][b]++
+	}
+	return s
+}
+
+func split(pairs []Pair) (xs, ys []float64) {
+	xs, ys = make([]float64, len(pairs)), make([]float64, len(pairs))
+	for i, p := range pairs {
+		xs[i], ys[i] = p.Value, float64(p.Rating)
+	}
+	return xs, ys
+}
@@ -0,0 +1,89 @@
+package series
+
+import (
+	"math"
+	"testing"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+// day is midnight UTC on the dth of February 2025
+func day(d int) time.Time {
+	return time.Date(2025, time.February, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPearsonAndSpearman(t *testing.T) {
+	tests := []struct {
+		name              string
+		xs, ys            []float64
+		pearson, spearman float64
+	}{
+		{"linear", []float64{1, 2, 3, 4}, []float64{2, 4, 6, 8}, 1, 1},
+		{"inverse", []float64{1, 2, 3, 4}, []float64{4, 3, 2, 1}, -1, -1},
+		// Monotonic but not linear, which only the ranks see as perfect
+		{"monotonic", []float64{1, 2, 3, 4, 5}, []float64{1, 2, 4, 8, 100}, 0.7479, 1},
+		// Ties take their mean rank: x ranks 1.5 1.5 3 4
+		{"ties", []float64{1, 1, 2, 3}, []float64{1, 2, 3, 4}, 0.9439, 0.9487},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pearson(tt.xs, tt.ys); math.Abs(got-tt.pearson) > 1e-4 {
+				t.Errorf("Pearson = %.4f, want %.4f", got, tt.pearson)
+			}
+			if got := Spearman(tt.xs, tt.ys); math.Abs(got-tt.spearman) > 1e-4 {
+				t.Errorf("Spearman = %.4f, want %.4f", got, tt.spearman)
+			}
+		})
+	}
+
+	if got := Pearson([]float64{1, 2}, []float64{1, 2}); !math.IsNaN(got) {
+		t.Errorf("Pearson of two pairs = %v, want NaN", got)
+	}
+	if got := Pearson([]float64{1, 2, 3}, []float64{3, 3, 3}); !math.IsNaN(got) {
+		t.Errorf("Pearson against a constant = %v, want NaN", got)
+	}
+}
+
+func TestAlign(t *testing.T) {
+	ratings := []rating.DayRating{
+		{Date: day(18), Rating: rating.Good},
+		{Date: day(17), Rating: rating.Poor},
+		{Date: day(19), Rating: rating.Fair},
+	}
+	sleep := Series{Name: "sleep", Values: map[time.Time]float64{day(16): 5, day(17): 8, day(19): 7}}
+
+	if pairs := Align(ratings, sleep, 0); len(pairs) != 2 || pairs[0].Value != 8 || pairs[1].Rating != rating.Fair {
+		t.Errorf("Align at lag 0 = %+v, want the 17th and the 19th", pairs)
+	}
+	// Each rating against the night before
+	pairs := Align(ratings, sleep, 1)
+	if len(pairs) != 2 || pairs[0].Day != day(17) || pairs[0].Value != 5 || pairs[1].Value != 8 || pairs[1].Rating != rating.Good {
+		t.Errorf("Align at lag 1 = %+v, want the 17th with 5 and the 18th with 8", pairs)
+	}
+}
+
+func TestQuantiles(t *testing.T) {
+	var pairs []Pair
+	for i, v := range []float64{1, 2, 3, 4, 5, 6, 6, 6, 9} {
+		pairs = append(pairs, Pair{Value: v, Rating: rating.Rating(i%5 + 1)})
+	}
+	quantiles := Quantiles(pairs, 3)
+	// The tied sixes all fall in the middle group
+	want := []Quantile{{Low: 1, High: 3, Days: 3}, {Low: 4, High: 6, Days: 5}, {Low: 9, High: 9, Days: 1}}
+	if len(quantiles) != len(want) {
+		t.Fatalf("Quantiles = %+v, want %d groups", quantiles, len(want))
+	}
+	for i, q := range quantiles {
+		if q.Low != want[i].Low || q.High != want[i].High || q.Days != want[i].Days {
+			t.Errorf("group %d = %+v, want %+v", i, q, want[i])
+		}
+	}
+	if quantiles[0].Average != 2 {
+		t.Errorf("first group average = %v, want 2", quantiles[0].Average)
+	}
+
+	scatter := NewScatter(pairs, 4)
+	if len(scatter.Edges) != 5 || scatter.Edges[4] != 9 || scatter.Counts[rating.Bad][0] != 1 || scatter.Counts[rating.Poor][3] != 0 {
+		t.Errorf("NewScatter = %+v", scatter)
+	}
+}
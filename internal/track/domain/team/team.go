@@ -0,0 +1,163 @@
+// Package team aggregates several people's ratings without revealing anyone's.
+package team
+
+import (
+	"fmt"
+	"math"
+	"time"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/series"
+)
+
+// DefaultMinGroup is the fewest respondents a group's ratings are reported for
+const DefaultMinGroup = 3
+
+// Member is one person's exported ratings. The name only tells members apart,
+// it is never reported.
+type Member struct {
+	Name    string
+	Ratings []rating.DayRating
+}
+
+// Group is the aggregate of the ratings given in one day or week, or over the
+// whole report. With fewer than the minimum respondents it is Suppressed, and
+// reports nothing else: not even how many responded, which next to the other
+// groups would tell how many rated on a hidden day.
+type Group struct {
+	Label string
+	// Respondents counts the members who rated, Members those who could have
+	Respondents int
+	Members     int
+	Suppressed  bool
+	// Average weighs each respondent equally, however many days they rated
+	Average float64
+	// Distribution counts each respondent once, by their average rounded halves up.
+	// Counting every rating would let a week's distribution, less its shown days,
+	// give away the ratings of its hidden days.
+	Distribution map[rating.Rating]int
+}
+
+// Participation is the share of members who rated
+func (g Group) Participation() float64 {
+	if g.Members == 0 {
+		return 0
+	}
+	return float64(g.Respondents) / float64(g.Members)
+}
+
+// Report covers whole ISO weeks, oldest first
+type Report struct {
+	Members  int
+	MinGroup int
+	Weeks    []Group
+	Days     []Group
+	Overall  Group
+	// Trend is the change of the weekly average per week, fitted over TrendWeeks reported weeks
+	Trend      float64
+	TrendWeeks int
+}
+
+// Aggregate reports the weeks from start's to end's. A group with fewer than
+// minGroup respondents is suppressed, so a small group's average and
+// distribution can't be traced back to the few people in it.
+func Aggregate(members []Member, start, end time.Time, minGroup int) Report {
+	if minGroup < 1 {
+		minGroup = 1
+	}
+	report := Report{Members: len(members), MinGroup: minGroup}
+
+	first, last := weekStart(start), weekStart(end).AddDate(0, 0, 7)
+	overall := newTally("overall")
+	for week := first; week.Before(last); week = week.AddDate(0, 0, 7) {
+		weekly := newTally(weekLabel(week))
+		for day := week; day.Before(week.AddDate(0, 0, 7)); day = day.AddDate(0, 0, 1) {
+			daily := newTally(rating.DayID(day))
+			for _, m := range members {
+				for _, dr := range m.Ratings {
+					// By calendar date, as members rate in their own time zones
+					if !dr.Rating.IsValid() || !series.Day(dr.Date).Equal(day) {
+						continue
+					}
+					daily.add(m.Name, dr.Rating)
+					weekly.add(m.Name, dr.Rating)
+					overall.add(m.Name, dr.Rating)
+				}
+			}
+			report.Days = append(report.Days, daily.group(len(members), minGroup))
+		}
+		report.Weeks = append(report.Weeks, weekly.group(len(members), minGroup))
+	}
+	report.Overall = overall.group(len(members), minGroup)
+	report.Trend, report.TrendWeeks = trend(report.Weeks)
+	return report
+}
+
+// tally collects a group's ratings by member
+type tally struct {
+	label   string
+	ratings map[string][]rating.Rating
+}
+
+func newTally(label string) *tally {
+	return &tally{label: label, ratings: make(map[string][]rating.Rating)}
+}
+
+func (t *tally) add(member string, r rating.Rating) {
+	t.ratings[member] = append(t.ratings[member], r)
+}
+
+func (t *tally) group(members, minGroup int) Group {
+	g := Group{Label: t.label, Respondents: len(t.ratings), Members: members}
+	if g.Respondents < minGroup {
+		if g.Respondents > 0 {
+			g = Group{Label: t.label, Members: members, Suppressed: true}
+		}
+		return g
+	}
+
+	g.Distribution = make(map[rating.Rating]int)
+	var sum float64
+	for _, ratings := range t.ratings {
+		var own float64
+		for _, r := range ratings {
+			own += float64(r)
+		}
+		own /= float64(len(ratings))
+		sum += own
+		g.Distribution[rating.Rating(math.Floor(own+0.5))]++
+	}
+	g.Average = sum / float64(g.Respondents)
+	return g
+}
+
+// trend fits a line through the weeks that are reported, by least squares
+func trend(weeks []Group) (float64, int) {
+	var n, sx, sy, sxx, sxy float64
+	for i, w := range weeks {
+		if w.Suppressed || w.Respondents == 0 {
+			continue
+		}
+		x := float64(i)
+		n++
+		sx += x
+		sy += w.Average
+		sxx += x * x
+		sxy += x * w.Average
+	}
+	if n < 2 {
+		return 0, int(n)
+	}
+	return (n*sxy - sx*sy) / (n*sxx - sx*sx), int(n)
+}
+
+// weekStart returns the Monday starting t's ISO week, keyed like series.Day
+func weekStart(t time.Time) time.Time {
+	day := series.Day(t)
+	return day.AddDate(0, 0, -(rating.ISOWeekday(day.Weekday()) - 1))
+}
+
+// weekLabel names a week like the day IDs, YYwWW
+func weekLabel(monday time.Time) string {
+	year, week := monday.ISOWeek()
+	return fmt.Sprintf("%02dw%02d", year%100, week)
+}
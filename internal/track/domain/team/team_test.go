@@ -0,0 +1,113 @@
+package team
+
+import (
+	"math"
+	"testing"
+	"time"
+	"track/internal/track/domain/rating"
+)
+
+func day(d int, r rating.Rating) rating.DayRating {
+	date := time.Date(2025, time.February, d, 0, 0, 0, 0, time.UTC)
+	return rating.DayRating{ID: rating.DayID(date), Date: date, Rating: r}
+}
+
+func TestAggregate(t *testing.T) {
+	members := []Member{
+		// Rated twice in week 8, which counts as one respondent's average of 3
+		{Name: "ann", Ratings: []rating.DayRating{day(10, rating.Good), day(17, rating.Good), day(18, rating.Poor)}},
+		{Name: "bob", Ratings: []rating.DayRating{day(10, rating.Fair), day(17, rating.Awesome)}},
+		{Name: "cem", Ratings: []rating.DayRating{day(11, rating.Bad), day(17, rating.Fair)}},
+		{Name: "dee"},
+	}
+	// Weeks 7 and 8 of 2025, asked for mid-week
+	report := Aggregate(members, time.Date(2025, time.February, 12, 0, 0, 0, 0, time.UTC), time.Date(2025, time.February, 20, 0, 0, 0, 0, time.UTC), 3)
+
+	if len(report.Weeks) != 2 || len(report.Days) != 14 {
+		t.Fatalf("got %d weeks and %d days, want 2 and 14", len(report.Weeks), len(report.Days))
+	}
+	w7, w8 := report.Weeks[0], report.Weeks[1]
+	if w7.Label != "25w07" || w7.Respondents != 3 || w7.Suppressed || math.Abs(w7.Average-(4+3+1)/3.0) > 1e-9 {
+		t.Errorf("week 7 = %+v", w7)
+	}
+	// One count per respondent, ann's Good and Poor being her average of Fair
+	if w8.Average != (3+5+3)/3.0 || w8.Distribution[rating.Fair] != 2 || w8.Distribution[rating.Awesome] != 1 || w8.Distribution[rating.Good] != 0 {
+		t.Errorf("week 8 = %+v", w8)
+	}
+	if w8.Participation() != 0.75 {
+		t.Errorf("week 8 participation = %v, want 0.75", w8.Participation())
+	}
+
+	monday, tuesday, wednesday := report.Days[0], report.Days[1], report.Days[2]
+	if monday.Label != "25w07-1" || !monday.Suppressed || monday.Respondents != 0 || monday.Average != 0 || monday.Distribution != nil {
+		t.Errorf("Monday of week 7, rated by 2 = %+v, want suppressed", monday)
+	}
+	if !tuesday.Suppressed || wednesday.Suppressed || wednesday.Respondents != 0 {
+		t.Errorf("Tuesday = %+v and Wednesday = %+v, want one respondent suppressed and none not", tuesday, wednesday)
+	}
+	if report.Days[7].Average != 4 {
+		t.Errorf("Monday of week 8 = %+v, want an average of 4", report.Days[7])
+	}
+
+	if report.TrendWeeks != 2 || math.Abs(report.Trend-(11.0-8.0)/3) > 1e-9 {
+		t.Errorf("trend = %v over %d weeks", report.Trend, report.TrendWeeks)
+	}
+	if report.Overall.Respondents != 3 || report.Overall.Members != 4 {
+		t.Errorf("overall = %+v", report.Overall)
+	}
+
+	if strict := Aggregate(members, time.Date(2025, time.February, 12, 0, 0, 0, 0, time.UTC), time.Date(2025, time.February, 20, 0, 0, 0, 0, time.UTC), 4); !strict.Overall.Suppressed || strict.TrendWeeks != 0 {
+		t.Errorf("with a minimum of 4 of 3 respondents, overall = %+v and trend over %d weeks", strict.Overall, strict.TrendWeeks)
+	}
+}
+
+func TestAggregateHiddenDayCantBeSubtracted(t *testing.T) {
+	// Everyone rates Monday, only ann rates Tuesday, which is hidden
+	for hidden := rating.Bad; hidden <= rating.Awesome; hidden++ {
+		members := []Member{
+			{Name: "ann", Ratings: []rating.DayRating{day(17, rating.Bad), day(18, hidden)}},
+			{Name: "bob", Ratings: []rating.DayRating{day(17, rating.Fair)}},
+			{Name: "cem", Ratings: []rating.DayRating{day(17, rating.Fair)}},
+		}
+		report := Aggregate(members, time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC), time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC), 3)
+		week, monday, tuesday := report.Weeks[0], report.Days[0], report.Days[1]
+		if !tuesday.Suppressed || tuesday.Respondents != 0 {
+			t.Fatalf("Tuesday = %+v, want hidden without its respondents", tuesday)
+		}
+
+		// Whatever is left of the week once Monday is taken out must not be Tuesday's rating
+		left := make(map[rating.Rating]int)
+		for r := rating.Bad; r <= rating.Awesome; r++ {
+			if n := week.Distribution[r] - monday.Distribution[r]; n != 0 {
+				left[r] = n
+			}
+		}
+		if len(left) == 1 && left[hidden] == 1 {
+			t.Errorf("the week less Monday gives away Tuesday's %s: %v", hidden, left)
+		}
+	}
+}
+
+func TestAggregateLocalDates(t *testing.T) {
+	// Rated in the morning east of UTC and late in the evening west of it, so each
+	// rating's instant falls on another day in UTC than the day it rates
+	east, west := time.FixedZone("EET", 2*60*60), time.FixedZone("PST", -8*60*60)
+	rated := func(at time.Time, r rating.Rating) rating.DayRating {
+		return rating.DayRating{ID: rating.DayID(at), Date: at, Rating: r}
+	}
+	members := []Member{
+		{Name: "ann", Ratings: []rating.DayRating{rated(time.Date(2025, time.March, 12, 1, 0, 0, 0, east), rating.Good)}},
+		{Name: "bob", Ratings: []rating.DayRating{rated(time.Date(2025, time.March, 12, 9, 0, 0, 0, east), rating.Good)}},
+		{Name: "cem", Ratings: []rating.DayRating{rated(time.Date(2025, time.March, 12, 22, 0, 0, 0, west), rating.Fair)}},
+	}
+	at := time.Date(2025, time.March, 12, 0, 0, 0, 0, time.UTC)
+	report := Aggregate(members, at, at, 3)
+
+	if report.Overall.Respondents != 3 {
+		t.Fatalf("overall = %+v, want all three respondents", report.Overall)
+	}
+	// Wednesday the 12th is the week's third day
+	if wednesday := report.Days[2]; wednesday.Label != "25w11-3" || wednesday.Respondents != 3 {
+		t.Errorf("Wednesday = %+v, want the three ratings of 25w11-3", wednesday)
+	}
+}
@@ -0,0 +1,36 @@
+// internal/ports/primary/activity/service.go
+package activity
+
+import (
+	"context"
+	"time"
+	"track/internal/track/domain/activity"
+	"track/internal/track/domain/series"
+)
+
+// Imported tells what an import found
+type Imported struct {
+	Metric string
+	// Sources are the repositories or files read
+	Sources int
+	// Items are the commits or meetings counted
+	Items int
+	// Hours are the meetings' total length
+	Hours float64
+	// Start and End are the days whose values were replaced
+	Start, End time.Time
+	// Days are the days with a value above zero
+	Days int
+}
+
+// Service imports measures of what was done on each day and relates them to the ratings
+type Service interface {
+	// ImportCommits counts the user's commits per day since since in the configured repositories
+	ImportCommits(ctx context.Context, since time.Time) (Imported, error)
+	// ImportMeetings counts the meetings per day, and the hours in them, since since in the calendar files
+	ImportMeetings(ctx context.Context, paths []string, since time.Time) (Imported, error)
+	// Metrics returns the imported metrics' values from start to end
+	Metrics(ctx context.Context, start, end time.Time) ([]series.Series, error)
+	// Insights compares each metric with the ratings from start to end
+	Insights(ctx context.Context, start, end time.Time) ([]activity.Insight, error)
+}
@@ -0,0 +1,29 @@
+// internal/ports/primary/backup/service.go
+package backup
+
+import (
+	"context"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/rating"
+)
+
+// Verification is the result of checking one snapshot
+type Verification struct {
+	Snapshot backup.Snapshot
+	// Err is set when the snapshot can't be read or fails its checksum
+	Err      error
+	Problems []rating.Problem
+}
+
+// Service backs the ratings up and restores them
+type Service interface {
+	Create(ctx context.Context) (backup.Snapshot, error)
+	List(ctx context.Context) ([]backup.Snapshot, error)
+	// Verify checks the snapshot with id, or every snapshot when id is empty
+	Verify(ctx context.Context, id string) ([]Verification, error)
+	// Preview returns what restoring the snapshot would change
+	Preview(ctx context.Context, id string) ([]backup.Change, error)
+	// Restore replaces the ratings with the snapshot's, after taking a snapshot of them
+	// to undo it with, which it returns
+	Restore(ctx context.Context, id string) (backup.Snapshot, error)
+}
@@ -0,0 +1,32 @@
+// internal/ports/primary/correlate/service.go
+package correlate
+
+import (
+	"context"
+	"time"
+	"track/internal/track/domain/series"
+)
+
+// Options pick the days and lags to correlate over
+type Options struct {
+	// Start and End bound the rated days
+	Start, End time.Time
+	// Lags are how many days each series value comes before the rating it is compared with
+	Lags []int
+	// Quantiles and Bins are how many groups the quantile table and the scatter plot split values into
+	Quantiles int
+	Bins      int
+}
+
+// Result relates one series at one lag to the ratings
+type Result struct {
+	series.Correlation
+	Quantiles []series.Quantile
+	Scatter   series.Scatter
+}
+
+// Service relates external series, such as sleep or steps, to the day ratings
+type Service interface {
+	// Correlate reads the series in each file and relates each to the ratings at each lag
+	Correlate(ctx context.Context, paths []string, opts Options) ([]Result, error)
+}
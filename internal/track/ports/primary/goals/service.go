@@ -0,0 +1,17 @@
+// internal/ports/primary/goals/service.go
+package goals
+
+import (
+	"context"
+	"track/internal/track/domain/goal"
+)
+
+// Service manages goals and measures the ratings against them
+type Service interface {
+	AddGoal(ctx context.Context, kind goal.Kind, period goal.Period, target float64) (goal.Goal, error)
+	RemoveGoal(ctx context.Context, id string) error
+	ListGoals(ctx context.Context) ([]goal.Goal, error)
+
+	// GetProgress evaluates every goal over its period containing today
+	GetProgress(ctx context.Context) ([]goal.Progress, error)
+}
@@ -0,0 +1,10 @@
+// internal/ports/primary/prompt/service.go
+package prompt
+
+import "context"
+
+// Service renders the shell prompt segment, fast enough to run on every prompt
+type Service interface {
+	// Segment renders format for today, or unrated while today is not rated
+	Segment(ctx context.Context, format, unrated string) (string, error)
+}
@@ -0,0 +1,21 @@
+// internal/ports/primary/rating/context.go
+package rating
+
+import (
+	"context"
+
+	"track/internal/track/domain/rating"
+)
+
+type sourceKey struct{}
+
+// WithSource tags ctx with the interface a write comes through, new ratings record it
+func WithSource(ctx context.Context, source rating.Source) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// SourceFrom returns the source set with WithSource, empty if there is none
+func SourceFrom(ctx context.Context) rating.Source {
+	source, _ := ctx.Value(sourceKey{}).(rating.Source)
+	return source
+}
@@ -2,13 +2,43 @@
 package rating
 
 import (
-	"track/internal/track/domain/rating"
 	"context"
 	"time"
+	"track/internal/track/domain/rating"
 )
 
+// Service is everything the primary adapters (CLI, HTTP, MCP) can ask of day ratings
 type Service interface {
-	CreateDayRating(ctx context.Context, date time.Time, rating rating.Rating) (rating.DayRating, error)
+	// Create, get, update and delete single days. Create fails with rating.ErrAlreadyRated
+	// on a rated day, Update with rating.ErrStaleRevision when revision is not the stored
+	// one (0 skips the check), and Set overwrites for callers that asked to.
+	CreateDayRating(ctx context.Context, date time.Time, r rating.Rating, note string) (rating.DayRating, error)
+	SetDayRating(ctx context.Context, date time.Time, r rating.Rating, note string) (rating.DayRating, error)
+	GetDayRating(ctx context.Context, date time.Time) (rating.DayRating, error)
+	GetTodayRating(ctx context.Context) (rating.DayRating, error)
+	// IsDayRated doesn't read the day, so it works on encrypted ratings without the passphrase
+	IsDayRated(ctx context.Context, date time.Time) (bool, error)
+	UpdateDayRating(ctx context.Context, date time.Time, r rating.Rating, note string, revision int) (rating.DayRating, error)
+	UpdateTodayRating(ctx context.Context, r rating.Rating) (rating.DayRating, error)
+	DeleteDayRating(ctx context.Context, date time.Time) error
+
+	// AddCheckIn rates a moment of a day, the day's rating becomes the aggregate of its check-ins
+	AddCheckIn(ctx context.Context, at time.Time, r rating.Rating, note string) (rating.DayRating, error)
+
+	// Listings
 	GetWeekRatings(ctx context.Context, year, week int) ([]rating.DayRating, error)
+	GetCurrentWeekRatings(ctx context.Context) ([]rating.DayRating, error)
 	GetDateRangeRatings(ctx context.Context, start, end time.Time) ([]rating.DayRating, error)
+
+	// Summaries and stats
+	GetWeekSummary(ctx context.Context, year, week int) (rating.WeekSummary, error)
+	GetWeeklyTrend(ctx context.Context, end time.Time, weeks int) ([]rating.WeekTrend, error)
+	GetWeekdayBreakdown(ctx context.Context, start, end time.Time) ([]rating.WeekdayStat, error)
+	GetStats(ctx context.Context, start, end time.Time, opts rating.StatsOptions) (rating.Stats, error)
+	GetMetrics(ctx context.Context) (rating.Metrics, error)
+
+	// Gaps
+	GetLastRatingBefore(ctx context.Context, date time.Time) (rating.DayRating, error)
+	GetMissingDays(ctx context.Context, start, end time.Time) ([]time.Time, error)
+	FillMissingRatings(ctx context.Context, start, end time.Time, r rating.Rating) ([]rating.DayRating, error)
 }
@@ -0,0 +1,21 @@
+// internal/ports/primary/remind/service.go
+package remind
+
+import (
+	"context"
+	"time"
+	"track/internal/track/domain/remind"
+)
+
+// Service reminds the user to rate the day
+type Service interface {
+	// Check notifies if today is unrated past the reminder time and not snoozed or quiet
+	Check(ctx context.Context) (remind.Outcome, error)
+	// Snooze holds back reminders for d and returns when they resume
+	Snooze(ctx context.Context, d time.Duration) (time.Time, error)
+	Schedule() remind.Schedule
+
+	// Install and Uninstall the periodic check with the given scheduler
+	Install(ctx context.Context, kind remind.SchedulerKind) (string, error)
+	Uninstall(ctx context.Context, kind remind.SchedulerKind) (string, error)
+}
@@ -0,0 +1,13 @@
+// internal/ports/primary/replica/service.go
+package replica
+
+import (
+	"context"
+	"track/internal/track/domain/crdt"
+)
+
+// Service merges other devices' copies of the ratings into this one
+type Service interface {
+	// Merge merges the ratings file at path and returns the days it changed
+	Merge(ctx context.Context, path string) ([]crdt.Change, error)
+}
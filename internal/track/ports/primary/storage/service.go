@@ -0,0 +1,24 @@
+// internal/ports/primary/storage/service.go
+package storage
+
+import (
+	"context"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/rating"
+)
+
+// Service turns encryption of the stored ratings on and off
+type Service interface {
+	// Encrypt seals every stored day under a new passphrase and returns how many it sealed.
+	// Run again, it seals the days an interrupted run left.
+	Encrypt(ctx context.Context) (int, error)
+	// Decrypt stores every day in the clear again and returns how many it opened
+	Decrypt(ctx context.Context) (int, error)
+	// PlainSnapshots lists the snapshots holding days unencrypted, newest first.
+	// Encrypting leaves the snapshots taken before as they were.
+	PlainSnapshots(ctx context.Context) ([]backup.Snapshot, error)
+	// Rekey changes the passphrase
+	Rekey(ctx context.Context) error
+	// Fsck checks every stored record, decrypting them when they are encrypted
+	Fsck(ctx context.Context) ([]rating.Problem, error)
+}
@@ -0,0 +1,22 @@
+// internal/ports/primary/sync/service.go
+package sync
+
+import (
+	"context"
+	"track/internal/track/domain/rating"
+)
+
+// Report describes what a sync did
+type Report struct {
+	// Pulled is set when remote changes were applied, by a fast-forward or a merge
+	Pulled bool
+	Merged bool
+	Pushed bool
+	// Conflicts are the days both sides changed, with how each was resolved
+	Conflicts []rating.MergeConflict
+}
+
+// Service synchronises the ratings with a remote copy
+type Service interface {
+	Sync(ctx context.Context) (Report, error)
+}
@@ -0,0 +1,16 @@
+// internal/ports/primary/team/service.go
+package team
+
+import (
+	"context"
+	"time"
+	"track/internal/track/domain/team"
+)
+
+// Service shares this member's ratings with the team and aggregates everyone's
+type Service interface {
+	// Export publishes every rating of this member, without notes or check-ins, and returns how many
+	Export(ctx context.Context) (int, error)
+	// Report aggregates the members' ratings over the weeks from start's to end's
+	Report(ctx context.Context, start, end time.Time) (team.Report, error)
+}
@@ -0,0 +1,28 @@
+// internal/ports/secondary/activity.go
+package secondary
+
+import (
+	"context"
+	"time"
+	"track/internal/track/domain/activity"
+	"track/internal/track/domain/series"
+)
+
+// MetricStore keeps imported measures by day, such as commits, next to the ratings
+type MetricStore interface {
+	// Put replaces the metric's values from start to end, days in the range missing from values are removed
+	Put(ctx context.Context, name string, start, end time.Time, values map[time.Time]float64) error
+	// Get returns every metric's values from start to end, metrics in name order
+	Get(ctx context.Context, start, end time.Time) ([]series.Series, error)
+}
+
+// CommitLog finds the user's commits in a repository
+type CommitLog interface {
+	Commits(ctx context.Context, repo string, since time.Time) ([]activity.Commit, error)
+}
+
+// CalendarReader reads the events of a calendar file, each occurrence of a recurring event
+// as one, that overlap start to end
+type CalendarReader interface {
+	Events(ctx context.Context, path string, start, end time.Time) ([]activity.Event, error)
+}
@@ -0,0 +1,30 @@
+// internal/ports/secondary/backup.go
+package secondary
+
+import (
+	"context"
+	"track/internal/track/domain/backup"
+	"track/internal/track/domain/rating"
+)
+
+// SnapshotStore keeps copies of the ratings file as they were, newest first
+type SnapshotStore interface {
+	// Create copies the ratings file as it is now and prunes the snapshots the retention policy drops
+	Create(ctx context.Context) (backup.Snapshot, error)
+	List(ctx context.Context) ([]backup.Snapshot, error)
+	// Records returns a snapshot's records by the key they are stored under,
+	// or backup.ErrNotFound for an unknown ID
+	Records(ctx context.Context, id string) (map[string]rating.DayRating, error)
+	// Verify checks the snapshot against the checksum taken with it
+	Verify(ctx context.Context, id string) error
+	// Restore replaces the ratings file with the snapshot, after taking a snapshot of
+	// the file to undo it with, which it returns. The undo snapshot may prune the
+	// restored one, which is then the ratings file.
+	Restore(ctx context.Context, id string) (backup.Snapshot, error)
+}
+
+// RecordReader reads the stored records as they are, by the key they are stored under,
+// for checks that a repository's lookups by ID would hide
+type RecordReader interface {
+	Records(ctx context.Context) (map[string]rating.DayRating, error)
+}
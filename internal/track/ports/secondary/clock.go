@@ -0,0 +1,9 @@
+// internal/ports/secondary/clock.go
+package secondary
+
+import "time"
+
+// Clock tells the application what "now" is, so today and the current week can be faked or pinned
+type Clock interface {
+	Now() time.Time
+}
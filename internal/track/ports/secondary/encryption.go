@@ -0,0 +1,29 @@
+// internal/ports/secondary/encryption.go
+package secondary
+
+import (
+	"context"
+	"track/internal/track/domain/rating"
+)
+
+// Cipher seals days before they reach the storage backend, which then only sees their
+// ID, date, revision and times: enough to query and sync them, not to read them
+type Cipher interface {
+	// Enabled reports whether the ratings are encrypted, when they are not days pass as they are
+	Enabled() bool
+	Seal(ctx context.Context, dr rating.DayRating) (rating.DayRating, error)
+	// Open returns the day sealed in dr, failing when it was changed or is not sealed
+	Open(ctx context.Context, dr rating.DayRating) (rating.DayRating, error)
+	// Sealed reports whether a stored day is sealed
+	Sealed(dr rating.DayRating) bool
+}
+
+// KeyStore keeps the key that seals the days, itself encrypted with a passphrase
+type KeyStore interface {
+	// Create makes a new key under a new passphrase, turning encryption on
+	Create(ctx context.Context) error
+	// Remove deletes the key, turning encryption off
+	Remove(ctx context.Context) error
+	// Rekey encrypts the key with a new passphrase, the sealed days stay as they are
+	Rekey(ctx context.Context) error
+}
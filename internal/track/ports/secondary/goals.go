@@ -0,0 +1,17 @@
+// internal/ports/secondary/goals.go
+package secondary
+
+import (
+	"context"
+	"track/internal/track/domain/goal"
+)
+
+// GoalRepository stores the user's goals keyed by their ID
+type GoalRepository interface {
+	// List returns every goal in the order they were added
+	List(ctx context.Context) ([]goal.Goal, error)
+	// Add stores g, assigning it the next free ID
+	Add(ctx context.Context, g goal.Goal) (goal.Goal, error)
+	// Delete returns goal.ErrNotFound when nothing is stored under id
+	Delete(ctx context.Context, id string) error
+}
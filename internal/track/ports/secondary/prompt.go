@@ -0,0 +1,12 @@
+// internal/ports/secondary/prompt.go
+package secondary
+
+import "context"
+
+// PromptCache keeps rendered prompt segments for one version of the ratings
+type PromptCache interface {
+	// Get returns the segment cached for format at version, ok is false on a miss
+	Get(ctx context.Context, version, format string) (segment string, ok bool, err error)
+	// Put caches segment, dropping the segments of other versions
+	Put(ctx context.Context, version, format, segment string) error
+}
@@ -0,0 +1,27 @@
+// internal/ports/secondary/remind.go
+package secondary
+
+import (
+	"context"
+	"time"
+)
+
+// Notifier shows the user a reminder: a desktop notification, a terminal bell, a hook
+type Notifier interface {
+	Notify(ctx context.Context, title, message string) error
+}
+
+// Scheduler runs `track remind check` periodically through the system's scheduler
+type Scheduler interface {
+	// Install sets up or replaces the periodic check and describes what it did
+	Install(ctx context.Context) (string, error)
+	// Uninstall removes the periodic check, doing nothing if it is not installed
+	Uninstall(ctx context.Context) (string, error)
+}
+
+// SnoozeStore remembers until when reminders are snoozed between check runs
+type SnoozeStore interface {
+	// SnoozedUntil returns the zero time when reminders were never snoozed
+	SnoozedUntil(ctx context.Context) (time.Time, error)
+	SnoozeUntil(ctx context.Context, until time.Time) error
+}
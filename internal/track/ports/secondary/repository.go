@@ -2,14 +2,43 @@
 package secondary
 
 import (
-	"track/internal/track/domain/rating"
 	"context"
 	"time"
+	"track/internal/track/domain/crdt"
+	"track/internal/track/domain/rating"
 )
 
+// RatingRepository stores day ratings keyed by their ID.
+// Every implementation must pass the contract tests in adapters/secondary/repotest.
 type RatingRepository interface {
+	// Save inserts or replaces the rating with dr.ID. dr.Revision must be one more than the
+	// stored revision (1 for a new ID), otherwise Save returns rating.ErrStaleRevision.
 	Save(ctx context.Context, r rating.DayRating) error
+	// GetByID returns rating.ErrNotFound when nothing is stored under id
 	GetByID(ctx context.Context, id string) (rating.DayRating, error)
+	// GetByDateRange returns ratings with start <= Date <= end, oldest first
 	GetByDateRange(ctx context.Context, start, end time.Time) ([]rating.DayRating, error)
+	// GetByWeek returns the ratings in an ISO year and week, oldest first
 	GetByWeek(ctx context.Context, year, week int) ([]rating.DayRating, error)
+	// Delete returns rating.ErrNotFound when nothing is stored under id
+	Delete(ctx context.Context, id string) error
+}
+
+// Prober is implemented by repositories that can tell whether a day is stored without
+// reading it, where reading would open a sealed day and so ask for the passphrase
+type Prober interface {
+	Exists(ctx context.Context, id string) (bool, error)
+}
+
+// Versioned is implemented by repositories that can tell cheaply whether their content changed
+type Versioned interface {
+	// Version returns a token that differs whenever the stored ratings may have changed
+	Version(ctx context.Context) (string, error)
+}
+
+// Replica is implemented by repositories that keep a device's copy of the ratings,
+// which merges with other devices' copies without conflicts
+type Replica interface {
+	// MergeFile merges another copy, or a plain ratings file, and returns the days that changed
+	MergeFile(ctx context.Context, path string) ([]crdt.Change, error)
 }
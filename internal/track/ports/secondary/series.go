@@ -0,0 +1,12 @@
+// internal/ports/secondary/series.go
+package secondary
+
+import (
+	"context"
+	"track/internal/track/domain/series"
+)
+
+// SeriesReader reads the dated numeric series in a file, such as a health app's export
+type SeriesReader interface {
+	Read(ctx context.Context, path string) ([]series.Series, error)
+}
@@ -0,0 +1,41 @@
+// internal/ports/secondary/sync.go
+package secondary
+
+import (
+	"context"
+	"track/internal/track/domain/rating"
+)
+
+// SyncRelation is how the local ratings history relates to the remote's
+type SyncRelation string
+
+const (
+	SyncUpToDate SyncRelation = "up to date"
+	// SyncAhead has local changes the remote doesn't
+	SyncAhead SyncRelation = "ahead"
+	// SyncBehind has remote changes to take as they are
+	SyncBehind SyncRelation = "behind"
+	// SyncDiverged has changes on both sides since their last common state
+	SyncDiverged SyncRelation = "diverged"
+	// SyncNoRemote is a remote that has no ratings yet
+	SyncNoRemote SyncRelation = "no remote branch"
+)
+
+// SyncState is the outcome of a fetch. Base, Ours and Theirs are only set when the
+// histories diverged, Base being empty when they share no common state.
+type SyncState struct {
+	Relation           SyncRelation
+	Base, Ours, Theirs map[string]rating.DayRating
+}
+
+// SyncRemote keeps the ratings in a history shared with a remote, such as a git repository
+type SyncRemote interface {
+	// Fetch records local changes and brings in the remote's, without applying them
+	Fetch(ctx context.Context) (SyncState, error)
+	// FastForward applies the fetched changes when there are no local ones
+	FastForward(ctx context.Context) error
+	// CommitMerge records merged as the result of combining both sides' changes
+	CommitMerge(ctx context.Context, merged map[string]rating.DayRating) error
+	// Push sends the local history to the remote
+	Push(ctx context.Context) error
+}
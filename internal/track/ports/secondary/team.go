@@ -0,0 +1,16 @@
+// internal/ports/secondary/team.go
+package secondary
+
+import (
+	"context"
+	"track/internal/track/domain/rating"
+	"track/internal/track/domain/team"
+)
+
+// TeamDirectory is the shared place team members export their ratings to, one file each
+type TeamDirectory interface {
+	// Members reads every member's exported ratings
+	Members(ctx context.Context) ([]team.Member, error)
+	// Publish replaces member's exported ratings
+	Publish(ctx context.Context, member string, ratings []rating.DayRating) error
+}